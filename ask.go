@@ -0,0 +1,125 @@
+package jaws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/linkdata/jaws/lib/what"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+type askQuestion struct {
+	ID      uint64 `json:"id"`
+	Kind    string `json:"kind"`
+	Message string `json:"message,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Name    string `json:"name,omitempty"`
+}
+
+type askAnswer struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  string          `json:"error"`
+}
+
+// Confirm shows message in a browser confirmation dialog and waits for the
+// user to answer it, returning true if the user accepted.
+//
+// See [Request.Query] for the blocking behavior and the errors returned.
+func (rq *Request) Confirm(ctx context.Context, message string) (ok bool, err error) {
+	var result json.RawMessage
+	if result, err = rq.ask(ctx, askQuestion{Kind: "confirm", Message: message}); err == nil {
+		err = json.Unmarshal(result, &ok)
+	}
+	return
+}
+
+// Prompt shows message in a browser prompt dialog with defaultValue filled in,
+// and waits for the user to answer it. ok is false if the user cancelled the
+// dialog.
+//
+// See [Request.Query] for the blocking behavior and the errors returned.
+func (rq *Request) Prompt(ctx context.Context, message, defaultValue string) (value string, ok bool, err error) {
+	var result json.RawMessage
+	if result, err = rq.ask(ctx, askQuestion{Kind: "prompt", Message: message, Value: defaultValue}); err == nil {
+		var p *string
+		if err = json.Unmarshal(result, &p); err == nil && p != nil {
+			value, ok = *p, true
+		}
+	}
+	return
+}
+
+// Query reads the client value registered as name in the browser and returns
+// it as JSON.
+//
+// The bundled client provides "viewport" ({"width","height"} of the window),
+// "scroll" ({"x","y"} of the page) and "selection" (the selected text), and
+// page JavaScript can add more with jawsRegisterQuery(name, fn), where fn
+// returns the value or a Promise of it.
+//
+// Query, [Request.Confirm] and [Request.Prompt] block until the browser
+// answers, ctx is done or the Request context ends, returning the cause in the
+// latter cases. They return [ErrRequestNotRunning] if the Request has no
+// running WebSocket, and an error holding the browser's message if it failed
+// to answer. They may be called from event handlers, since answers are
+// delivered by the processing loop, but not from JawsRender or JawsUpdate,
+// which run on it. Events arriving while an event handler waits are queued.
+func (rq *Request) Query(ctx context.Context, name string) (json.RawMessage, error) {
+	return rq.ask(ctx, askQuestion{Kind: "query", Name: name})
+}
+
+func (rq *Request) ask(ctx context.Context, q askQuestion) (result json.RawMessage, err error) {
+	err = ErrRequestNotRunning
+	if k := rq.destKey(); k != 0 && rq.loadState() == reqRunning {
+		ch := make(chan askAnswer, 1)
+		rq.mu.Lock()
+		rq.askSeq++
+		q.ID = rq.askSeq
+		if rq.asks == nil {
+			rq.asks = make(map[uint64]chan askAnswer)
+		}
+		rq.asks[q.ID] = ch
+		rqCtx := rq.ctx
+		rq.mu.Unlock()
+		defer func() {
+			rq.mu.Lock()
+			delete(rq.asks, q.ID)
+			rq.mu.Unlock()
+		}()
+		b, _ := json.Marshal(q) // askQuestion fields always marshal
+		rq.Jaws.Broadcast(wire.Message{Dest: k, What: what.Ask, Data: string(b)})
+		select {
+		case ans := <-ch:
+			if err = nil; ans.Error != "" {
+				err = errors.New(ans.Error)
+			} else {
+				result = ans.Result
+			}
+		case <-ctx.Done():
+			err = context.Cause(ctx)
+		case <-rqCtx.Done():
+			err = context.Cause(rqCtx)
+		}
+	}
+	return
+}
+
+// handleAnswer delivers an Answer event to the question waiting for it.
+// Answers to unknown or abandoned questions are ignored.
+func (rq *Request) handleAnswer(data string) {
+	var ans askAnswer
+	if json.Unmarshal([]byte(data), &ans) == nil {
+		rq.mu.Lock()
+		ch := rq.asks[ans.ID]
+		delete(rq.asks, ans.ID)
+		rq.mu.Unlock()
+		if ch != nil {
+			if ans.Result == nil && ans.Error == "" {
+				ans.Result = json.RawMessage("null")
+			}
+			ch <- ans
+		}
+	}
+}
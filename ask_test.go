@@ -0,0 +1,153 @@
+package jaws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/lib/what"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+type testConfirmClick struct {
+	resultCh chan string
+}
+
+func (h testConfirmClick) JawsClick(elem *Element, click Click) error {
+	ok, err := elem.Request.Confirm(context.Background(), "sure?")
+	h.resultCh <- click.Name + " " + strconv.FormatBool(ok)
+	return err
+}
+
+func readAsk(t *testing.T, th *testHelper, rq *testRequest) (q askQuestion) {
+	t.Helper()
+	select {
+	case <-th.C:
+		th.Timeout()
+	case msg := <-rq.OutCh:
+		if msg.What != what.Ask || msg.Jid != 0 {
+			t.Fatalf("got %v", msg)
+		}
+		if err := json.Unmarshal([]byte(msg.Data), &q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return
+}
+
+func TestAsk_ConfirmFromEventHandler(t *testing.T) {
+	th := newTestHelper(t)
+	rq := newTestRequest(t)
+	defer rq.Close()
+
+	resultCh := make(chan string, 2)
+	elem := rq.NewElement(testDivWidget{inner: "x"})
+	elem.AddHandlers(testConfirmClick{resultCh: resultCh})
+	elem.Freeze()
+
+	rq.InCh <- wire.WsMsg{Data: "1 2 0 first", Jid: elem.Jid(), What: what.Click}
+	q := readAsk(t, th, rq)
+	if q.Kind != "confirm" || q.Message != "sure?" {
+		t.Errorf("question %+v", q)
+	}
+	// Events received while the handler waits are queued behind it.
+	rq.InCh <- wire.WsMsg{Data: "1 2 0 second", Jid: elem.Jid(), What: what.Click}
+	rq.InCh <- wire.WsMsg{Data: `{"id":999,"result":false}`, What: what.Answer}
+	rq.InCh <- wire.WsMsg{Data: `{"id":` + strconv.FormatUint(q.ID, 10) + `,"result":true}`, What: what.Answer}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-resultCh:
+		if s != "first true" {
+			t.Error(s)
+		}
+	}
+	q = readAsk(t, th, rq)
+	rq.InCh <- wire.WsMsg{Data: `{"id":` + strconv.FormatUint(q.ID, 10) + `,"result":false}`, What: what.Answer}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-resultCh:
+		if s != "second false" {
+			t.Error(s)
+		}
+	}
+}
+
+func TestAsk_PromptQueryAndErrors(t *testing.T) {
+	th := newTestHelper(t)
+	rq := newTestRequest(t)
+	defer rq.Close()
+
+	answer := func(data string) {
+		q := readAsk(t, th, rq)
+		rq.InCh <- wire.WsMsg{Data: `{"id":` + strconv.FormatUint(q.ID, 10) + `,` + data + `}`, What: what.Answer}
+	}
+
+	type promptResult struct {
+		value string
+		ok    bool
+		err   error
+	}
+	promptCh := make(chan promptResult)
+	prompt := func() {
+		var r promptResult
+		r.value, r.ok, r.err = rq.Prompt(context.Background(), "name?", "bob")
+		promptCh <- r
+	}
+	go prompt()
+	answer(`"result":"alice"`)
+	if r := <-promptCh; r.value != "alice" || !r.ok || r.err != nil {
+		t.Errorf("prompt %+v", r)
+	}
+	go prompt()
+	answer(`"result":null`)
+	if r := <-promptCh; r.value != "" || r.ok || r.err != nil {
+		t.Errorf("cancelled prompt %+v", r)
+	}
+
+	queryCh := make(chan string)
+	go func() {
+		result, err := rq.Query(context.Background(), "missing")
+		queryCh <- string(result) + "|" + err.Error()
+	}()
+	answer(`"error":"jaws: unknown query: missing"`)
+	if s := <-queryCh; s != "|jaws: unknown query: missing" {
+		t.Error(s)
+	}
+	go func() {
+		result, err := rq.Query(context.Background(), "viewport")
+		if err != nil {
+			queryCh <- err.Error()
+		} else {
+			queryCh <- string(result)
+		}
+	}()
+	answer(`"result":{"width":1,"height":2}`)
+	if s := <-queryCh; s != `{"width":1,"height":2}` {
+		t.Error(s)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := rq.Confirm(ctx, "slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err %v", err)
+	}
+	readAsk(t, th, rq)
+	rq.mu.RLock()
+	pending := len(rq.asks)
+	rq.mu.RUnlock()
+	if pending != 0 {
+		t.Errorf("%d asks pending", pending)
+	}
+
+	pendingRq := rq.Jaws.NewRequest(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if _, err := pendingRq.Confirm(context.Background(), "x"); !errors.Is(err, ErrRequestNotRunning) {
+		t.Errorf("err %v", err)
+	}
+}
@@ -37,6 +37,9 @@ import (
 // Dest is expanded into tags. Plain strings and [Jid] values are illegal tag
 // types; use [tag.Tag], a domain tag, or an [Element] method instead.
 //
+// With a [Jaws.Broadcaster], the message is also published to peer instances;
+// see [Broadcaster] for which destinations are portable.
+//
 // That expansion runs through [Jaws.MustTagExpand], which reports a failure such as an
 // illegal tag type through [Jaws.MustLog]: that panics when no [Jaws.Logger] is set,
 // while with a Logger the error is queued and the message is sent to the destinations
@@ -72,6 +75,13 @@ func (jw *Jaws) Broadcast(msg wire.Message) {
 			msg.Dest = expanded
 		}
 	}
+	jw.publishMessage(msg)
+	jw.sendBroadcast(msg)
+}
+
+// sendBroadcast hands msg, with Dest already validated and expanded, to the
+// processing loop.
+func (jw *Jaws) sendBroadcast(msg wire.Message) {
 	select {
 	case <-jw.Done():
 	case jw.bcastCh <- msg:
@@ -116,9 +126,13 @@ func (jw *Jaws) setDirty(tags []any) {
 // on every live [Request]. Updates run on the normal batched dirty pass, so
 // [Jaws.Serve] or [Jaws.ServeWithTimeout] must be running for delivery.
 //
+// With a [Jaws.Broadcaster], portable tags are also published to peer instances.
+//
 // [Request.Dirty] is equivalent.
 func (jw *Jaws) Dirty(dirtyTags ...any) {
-	jw.setDirty(jw.MustTagExpand(dirtyTags))
+	tags := jw.MustTagExpand(dirtyTags)
+	jw.setDirty(tags)
+	jw.publishDirty(tags)
 }
 
 // dirtPair pairs a dirty tag with its insertion-order rank, used by sortedDirtTags
@@ -242,7 +256,8 @@ func (jw *Jaws) Redirect(url string) {
 	}
 }
 
-// Alert sends an alert to all active [Request] values.
+// Alert sends an alert to all active [Request] values, shown as described for
+// [Request.Alert].
 //
 // The level argument should be one of Bootstrap's alert levels:
 // primary, secondary, success, danger, warning, info, light or dark.
@@ -326,6 +341,36 @@ func (jw *Jaws) SetValue(target any, value string) {
 	jw.broadcastTo(target, what.Value, value)
 }
 
+// Focus sends a request to move keyboard focus to the elements matching target.
+// If several match, the last one to receive it keeps focus.
+func (jw *Jaws) Focus(target any) {
+	jw.broadcastTo(target, what.Focus, "")
+}
+
+// ScrollIntoView sends a request to scroll the elements matching target into
+// view using opts.
+func (jw *Jaws) ScrollIntoView(target any, opts ScrollOptions) {
+	jw.broadcastTo(target, what.Scroll, opts.data())
+}
+
+// Select sends a request to focus the elements matching target and select
+// their text; see [Element.Select].
+func (jw *Jaws) Select(target any, start, end int) {
+	jw.broadcastTo(target, what.Select, selectData(start, end))
+}
+
+// ShowModal sends a request to open the <dialog> elements matching target as
+// modals.
+func (jw *Jaws) ShowModal(target any) {
+	jw.broadcastTo(target, what.ShowModal, "")
+}
+
+// CloseDialog sends a request to close the <dialog> elements matching target.
+// It is the tag-targeted counterpart of [Element.Close].
+func (jw *Jaws) CloseDialog(target any) {
+	jw.broadcastTo(target, what.Close, "")
+}
+
 // Insert inserts html before the child at childIndex in every element matching
 // target.
 //
@@ -0,0 +1,169 @@
+package jaws
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/linkdata/jaws/lib/key"
+	"github.com/linkdata/jaws/lib/tag"
+	"github.com/linkdata/jaws/lib/what"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+// Broadcaster carries [Jaws.Dirty] and [Jaws.Broadcast] calls to peer [Jaws]
+// instances, such as replicas of an application behind a load balancer.
+//
+// When [Jaws.Broadcaster] is set, each Dirty and Broadcast call is also encoded
+// into a packet and passed to Publish. The transport must deliver the packet to
+// [Jaws.ReceiveBroadcast] on every peer. Delivering it back to the publishing
+// instance is harmless; such packets are ignored.
+//
+// Only portable destinations cross instances: tags whose type is registered with
+// [Jaws.RegisterPortableTag] ([tag.Tag] is registered by [New]). Pointers, [Element]
+// targets and other process-local tags are applied locally only, as are messages
+// addressed to a Request [key.Key]. A message with a nil Dest reaches every peer.
+//
+// Publish is called without core locks held, from the goroutine calling Dirty or
+// Broadcast, and should not block for long. Errors are reported through [Jaws.Log].
+type Broadcaster interface {
+	Publish(packet []byte) (err error)
+}
+
+// broadcastPacket is the JSON form of a published Dirty or Broadcast call.
+// What is empty for a Dirty call. For a Broadcast call, a nil Dest targets every
+// active Request.
+type broadcastPacket struct {
+	Origin key.Key      `json:"origin"`
+	Dirty  []typedValue `json:"dirty,omitempty"`
+	What   string       `json:"what,omitempty"`
+	Dest   []typedValue `json:"dest,omitempty"`
+	Data   string       `json:"data,omitempty"`
+}
+
+// RegisterPortableTag makes tags of the type of value portable under name, so
+// that [Jaws.Dirty] and [Jaws.Broadcast] forward them through the
+// [Jaws.Broadcaster].
+//
+// The type must be usable as a tag and round-trip through [encoding/json] to an
+// equal value, for example a string type or a struct of exported comparable fields.
+// All instances sharing a Broadcaster must register the same names.
+func (jw *Jaws) RegisterPortableTag(name string, value any) (err error) {
+	if err = tag.NewErrNotComparable(value); err == nil {
+		err = jw.portableTags.register(name, value)
+	}
+	if err != nil {
+		err = fmt.Errorf("jaws: RegisterPortableTag: %w", err)
+	}
+	return
+}
+
+// marshalPortableTags returns the JSON forms of the portable tags in tags.
+func (jw *Jaws) marshalPortableTags(tags []any) (tvs []typedValue) {
+	for _, tagValue := range tags {
+		if jw.portableTags.known(tagValue) {
+			if tv, err := jw.portableTags.marshal(tagValue, ErrPortableTagNotRegistered); jw.Log(err) == nil {
+				tvs = append(tvs, tv)
+			}
+		}
+	}
+	return
+}
+
+func (jw *Jaws) unmarshalPortableTags(tvs []typedValue) (tags []any, err error) {
+	for _, tv := range tvs {
+		var tagValue any
+		if tagValue, err = jw.portableTags.unmarshal(tv, ErrPortableTagNotRegistered); err != nil {
+			return nil, err
+		}
+		if err = tag.NewErrNotComparable(tagValue); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tagValue)
+	}
+	return
+}
+
+func (jw *Jaws) publish(pkt *broadcastPacket) {
+	if bc := jw.Broadcaster; bc != nil {
+		pkt.Origin = jw.nodeID
+		b, err := json.Marshal(pkt)
+		if err == nil {
+			err = bc.Publish(b)
+		}
+		_ = jw.Log(err)
+	}
+}
+
+// publishDirty forwards the portable tags among the expanded dirty tags.
+func (jw *Jaws) publishDirty(tags []any) {
+	if jw.Broadcaster != nil {
+		if tvs := jw.marshalPortableTags(tags); len(tvs) > 0 {
+			jw.publish(&broadcastPacket{Dirty: tvs})
+		}
+	}
+}
+
+// publishMessage forwards msg, whose Dest has been expanded by Broadcast, if
+// it has a portable destination.
+func (jw *Jaws) publishMessage(msg wire.Message) {
+	if jw.Broadcaster != nil {
+		pkt := broadcastPacket{What: msg.What.String(), Data: msg.Data}
+		switch dest := msg.Dest.(type) {
+		case nil:
+		case key.Key:
+			return
+		case []any:
+			pkt.Dest = jw.marshalPortableTags(dest)
+		default:
+			pkt.Dest = jw.marshalPortableTags([]any{dest})
+		}
+		if msg.Dest == nil || len(pkt.Dest) > 0 {
+			jw.publish(&pkt)
+		}
+	}
+}
+
+// ReceiveBroadcast applies a packet published by a peer's [Broadcaster].
+//
+// Dirty tags are scheduled as by [Jaws.Dirty] and messages are sent as by
+// [Jaws.Broadcast], but neither is published again. Packets published by jw
+// itself are ignored. Like Broadcast, it must not be called before the JaWS
+// processing loop is running, or it may block.
+func (jw *Jaws) ReceiveBroadcast(packet []byte) (err error) {
+	var pkt broadcastPacket
+	if err = json.Unmarshal(packet, &pkt); err != nil {
+		return fmt.Errorf("jaws: ReceiveBroadcast: %w: %w", ErrInvalidBroadcastPacket, err)
+	}
+	if pkt.Origin == jw.nodeID {
+		return nil
+	}
+	var dirt, dest []any
+	if dirt, err = jw.unmarshalPortableTags(pkt.Dirty); err == nil {
+		dest, err = jw.unmarshalPortableTags(pkt.Dest)
+	}
+	if err != nil {
+		return fmt.Errorf("jaws: ReceiveBroadcast: %w", err)
+	}
+	if len(dirt) > 0 {
+		jw.setDirty(dirt)
+	}
+	if pkt.What != "" {
+		msg := wire.Message{What: what.Parse(pkt.What), Data: pkt.Data}
+		switch msg.What {
+		case what.Invalid, what.Replace, what.Remove:
+			return fmt.Errorf("jaws: ReceiveBroadcast: %w: what %q", ErrInvalidBroadcastPacket, pkt.What)
+		}
+		switch len(dest) {
+		case 0:
+			if len(pkt.Dest) > 0 {
+				return
+			}
+		case 1:
+			msg.Dest = dest[0]
+		default:
+			msg.Dest = dest
+		}
+		jw.sendBroadcast(msg)
+	}
+	return
+}
@@ -0,0 +1,126 @@
+package jaws
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/lib/key"
+	"github.com/linkdata/jaws/lib/tag"
+	"github.com/linkdata/jaws/lib/what"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+type testPortableTag struct {
+	Table string
+	Row   int
+}
+
+func newTestBroadcastPair(t *testing.T) (jw1, jw2 *Jaws) {
+	t.Helper()
+	hub := NewLoopbackHub()
+	var err error
+	if jw1, err = New(); err != nil {
+		t.Fatal(err)
+	}
+	if jw2, err = New(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(jw1.Close)
+	t.Cleanup(jw2.Close)
+	hub.Join(jw1)
+	hub.Join(jw2)
+	return
+}
+
+func waitDirty(t *testing.T, jw *Jaws, want any) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		jw.mu.RLock()
+		_, ok := jw.dirty[want]
+		jw.mu.RUnlock()
+		if ok {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("%v not dirtied", want)
+}
+
+func TestBroadcaster_Message(t *testing.T) {
+	jw1, jw2 := newTestBroadcastPair(t)
+
+	jw1.SetInner(tag.Tag("foo"), "<b>hi</b>")
+	for _, jw := range []*Jaws{jw1, jw2} {
+		msg := nextBroadcast(t, jw)
+		if msg.Dest != tag.Tag("foo") || msg.What != what.Inner || msg.Data != "<b>hi</b>" {
+			t.Errorf("%v", msg)
+		}
+	}
+
+	jw2.Reload()
+	for _, jw := range []*Jaws{jw2, jw1} {
+		if msg := nextBroadcast(t, jw); msg.Dest != nil || msg.What != what.Reload {
+			t.Errorf("%v", msg)
+		}
+	}
+
+	jw1.Broadcast(wire.Message{Dest: key.Key(1234), What: what.Update})
+	nextBroadcast(t, jw1)
+	select {
+	case msg := <-jw2.bcastCh:
+		t.Errorf("request key destination published: %v", msg)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestBroadcaster_Dirty(t *testing.T) {
+	jw1, jw2 := newTestBroadcastPair(t)
+	for _, jw := range []*Jaws{jw1, jw2} {
+		if err := jw.RegisterPortableTag("row", testPortableTag{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var local int
+	jw1.Dirty(tag.Tag("a"), &local, testPortableTag{Table: "t", Row: 2})
+	waitDirty(t, jw2, tag.Tag("a"))
+	waitDirty(t, jw2, testPortableTag{Table: "t", Row: 2})
+	jw2.mu.RLock()
+	_, ok := jw2.dirty[&local]
+	jw2.mu.RUnlock()
+	if ok {
+		t.Error("process-local tag published")
+	}
+}
+
+func TestBroadcaster_ReceiveErrors(t *testing.T) {
+	jw, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jw.Close()
+	if err := jw.ReceiveBroadcast([]byte("{")); !errors.Is(err, ErrInvalidBroadcastPacket) {
+		t.Error(err)
+	}
+	if err := jw.ReceiveBroadcast([]byte(`{"origin":1,"dirty":[{"type":"nope","value":1}]}`)); !errors.Is(err, ErrPortableTagNotRegistered) {
+		t.Error(err)
+	}
+	if err := jw.ReceiveBroadcast([]byte(`{"origin":1,"what":"Remove"}`)); !errors.Is(err, ErrInvalidBroadcastPacket) {
+		t.Error(err)
+	}
+	if err := jw.ReceiveBroadcast([]byte(`{"origin":` + jsonKey(jw.nodeID) + `,"what":"Reload"}`)); err != nil {
+		t.Error(err)
+	}
+	if err := jw.RegisterPortableTag("tag.Tag", testPortableTag{}); err == nil {
+		t.Error("expected name conflict")
+	}
+	if err := jw.RegisterPortableTag("slice", []int{}); err == nil {
+		t.Error("expected non-comparable type to be rejected")
+	}
+}
+
+func jsonKey(k key.Key) string {
+	return strconv.FormatUint(uint64(k), 10)
+}
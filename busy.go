@@ -0,0 +1,75 @@
+package jaws
+
+import (
+	"github.com/linkdata/jaws/lib/what"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+// BusyPolicy is a render parameter making the bundled client mark an Element
+// busy while the server handles an event it sent.
+//
+// While busy, the Element has aria-busy="true" and the jaws-busy CSS class.
+// Once the event handlers return, the server sends an Ack command that clears
+// the mark, after the updates for the tags the handlers dirtied. For a Click
+// or ContextMenu, the Element marked and acknowledged is the nearest one to
+// the event target. Events that other Elements send are not affected.
+type BusyPolicy uint8
+
+const (
+	// BusyIndicate marks the Element busy and keeps sending its events.
+	BusyIndicate BusyPolicy = iota + 1
+	// BusyDrop marks the Element busy and drops the events it sends while busy,
+	// such as a second click on a button whose first is still being handled.
+	BusyDrop
+	// BusyDisable marks the Element busy and sets its disabled property while
+	// busy, unless it was already disabled.
+	BusyDisable
+)
+
+func (bp BusyPolicy) attr() (s string) {
+	switch bp {
+	case BusyIndicate:
+		s = `data-jawsbusy`
+	case BusyDrop:
+		s = `data-jawsbusy="drop"`
+	case BusyDisable:
+		s = `data-jawsbusy="disable"`
+	}
+	return
+}
+
+// ackTag is a pending-dirt entry asking the Request owning elem to
+// acknowledge an event on it.
+type ackTag struct{ elem *Element }
+
+// queueAck schedules acknowledging an event on elem, whose handlers have
+// returned, so the bundled client can clear its busy mark.
+//
+// The Ack travels with the dirty tags the handlers set, through the next
+// update pass, and is sent after the updates that pass queues. The browser
+// therefore clears the busy mark only once it shows the handlers' results.
+func (rq *Request) queueAck(elem *Element) {
+	rq.Jaws.setDirty([]any{ackTag{elem: elem}})
+}
+
+// sendAcks sends the Acks collected by the last makeUpdateList.
+//
+// Like an RPC reply, an Ack is not dropped when outboundMsgCh is full, since
+// the Element would otherwise stay busy; the send is abandoned only once the
+// Request context is done. No Ack is sent for a deleted Element.
+func (rq *Request) sendAcks(outboundMsgCh chan<- wire.WsMsg) {
+	rq.mu.Lock()
+	acks := rq.acks
+	rq.acks = nil
+	rq.mu.Unlock()
+	for _, elem := range acks {
+		if !elem.deleted.Load() {
+			select {
+			case outboundMsgCh <- wire.WsMsg{What: what.Ack, Jid: elem.Jid()}:
+				rq.Jaws.metrics.countSent(what.Ack)
+			case <-rq.Context().Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package jaws
+
+import (
+	"html/template"
+	"io"
+	"testing"
+
+	"github.com/linkdata/jaws/lib/htmlio"
+	"github.com/linkdata/jaws/lib/what"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+func TestBusyPolicy_AttrAndAck(t *testing.T) {
+	th := newTestHelper(t)
+	rq := newTestRequest(t)
+	defer rq.Close()
+
+	for _, tt := range []struct {
+		bp   BusyPolicy
+		want string
+	}{
+		{BusyIndicate, `data-jawsbusy`},
+		{BusyDrop, `data-jawsbusy="drop"`},
+		{BusyDisable, `data-jawsbusy="disable"`},
+		{BusyPolicy(0), ``},
+	} {
+		if got := tt.bp.attr(); got != tt.want {
+			t.Errorf("%d: got %q, want %q", tt.bp, got, tt.want)
+		}
+	}
+
+	clickCh := make(chan string, 2)
+	plain := rq.NewElement(testDivWidget{inner: "plain"})
+	plain.AddHandlers(testNameClick(clickCh))
+	plain.Freeze()
+	busy := rq.NewElement(testDivWidget{inner: "busy"})
+	if attrs := busy.ApplyParams([]any{BusyDrop}); len(attrs) != 1 || attrs[0] != `data-jawsbusy="drop"` {
+		t.Errorf("attrs %q", attrs)
+	}
+	busy.AddHandlers(testNameClick(clickCh))
+	busy.Freeze()
+
+	// Only the Element rendered with a BusyPolicy acknowledges its events.
+	rq.InCh <- wire.WsMsg{Data: "1 2 0 plain\t" + plain.Jid().String(), What: what.Click}
+	rq.InCh <- wire.WsMsg{Data: "1 2 0 busy\t" + busy.Jid().String(), What: what.Click}
+	for range 2 {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case <-clickCh:
+		}
+	}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case msg := <-rq.OutCh:
+		if msg.What != what.Ack || msg.Jid != busy.Jid() || msg.Data != "" {
+			t.Errorf("got %v", msg)
+		}
+	}
+}
+
+type testNameClick chan string
+
+func (ch testNameClick) JawsClick(elem *Element, click Click) error {
+	ch <- click.Name
+	return nil
+}
+
+type testInnerUpdater template.HTML
+
+func (u testInnerUpdater) JawsRender(elem *Element, w io.Writer, params []any) error {
+	return htmlio.WriteHTMLInner(w, elem.Jid(), "div", "", "")
+}
+
+func (u testInnerUpdater) JawsUpdate(elem *Element) {
+	elem.SetInner(template.HTML(u))
+}
+
+type testDirtyClick struct{ target *Element }
+
+func (h testDirtyClick) JawsClick(elem *Element, click Click) error {
+	elem.Dirty(h.target)
+	return nil
+}
+
+func TestBusyPolicy_AckFollowsUpdates(t *testing.T) {
+	th := newTestHelper(t)
+	rq := newTestRequest(t)
+	defer rq.Close()
+
+	busy := rq.NewElement(testDivWidget{inner: "busy"})
+	busy.ApplyParams([]any{BusyIndicate})
+	target := rq.NewElement(testInnerUpdater("updated"))
+	busy.AddHandlers(testDirtyClick{target: target})
+	busy.Freeze()
+
+	// The Ack follows the update the handler caused.
+	rq.InCh <- wire.WsMsg{Data: "1 2 0 busy\t" + busy.Jid().String(), What: what.Click}
+	for _, want := range []wire.WsMsg{
+		{Data: "updated", Jid: target.Jid(), What: what.Inner},
+		{Jid: busy.Jid(), What: what.Ack},
+	} {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case msg := <-rq.OutCh:
+			if msg != want {
+				t.Errorf("got %v, want %v", msg, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package jaws
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// maxClientLanguages bounds the languages kept from a ClientInfo report.
+const maxClientLanguages = 16
+
+// ClientInfo describes the browser environment of a [Request], as reported by
+// the bundled client when its WebSocket connects and again whenever it changes.
+//
+// The values are supplied by the browser and must not be trusted for anything
+// but presentation.
+type ClientInfo struct {
+	TimeZone    string   `json:"timeZone,omitempty"`    // IANA time zone name, such as "Europe/Stockholm"
+	Languages   []string `json:"languages,omitempty"`   // preferred languages, most preferred first
+	Width       int      `json:"width,omitempty"`       // viewport width in CSS pixels
+	Height      int      `json:"height,omitempty"`      // viewport height in CSS pixels
+	PixelRatio  float64  `json:"pixelRatio,omitempty"`  // device pixels per CSS pixel
+	ColorScheme string   `json:"colorScheme,omitempty"` // preferred color scheme, "light" or "dark"
+	Hidden      bool     `json:"hidden,omitempty"`      // true if the page is not visible
+}
+
+// Location returns the [time.Location] for TimeZone, or [time.UTC] if it is
+// empty or unknown.
+func (ci ClientInfo) Location() (loc *time.Location) {
+	loc = time.UTC
+	if ci.TimeZone != "" {
+		if l, err := time.LoadLocation(ci.TimeZone); err == nil {
+			loc = l
+		}
+	}
+	return
+}
+
+func (ci ClientInfo) equal(other ClientInfo) bool {
+	return ci.TimeZone == other.TimeZone &&
+		slices.Equal(ci.Languages, other.Languages) &&
+		ci.Width == other.Width &&
+		ci.Height == other.Height &&
+		ci.PixelRatio == other.PixelRatio &&
+		ci.ColorScheme == other.ColorScheme &&
+		ci.Hidden == other.Hidden
+}
+
+// ClientInfo returns the browser environment last reported for the Request.
+//
+// It is available from the [ConnectFn] on. Before the WebSocket connects, or
+// if the client did not report it, it is the zero value.
+func (rq *Request) ClientInfo() (ci ClientInfo) {
+	rq.mu.RLock()
+	ci = rq.clientInfo
+	rq.mu.RUnlock()
+	ci.Languages = slices.Clone(ci.Languages)
+	return
+}
+
+// ClientInfoTag returns this instance's client info tag.
+//
+// When a Request's [ClientInfo] changes after it has connected, JaWS dirties the
+// tag on that Request only. Tag the Elements that render using
+// [Request.ClientInfo] with it to keep them current. The tag is stable for the
+// Jaws lifetime and unique to this instance.
+func (jw *Jaws) ClientInfoTag() any {
+	return &jw.statusTags.clientInfo
+}
+
+// setClientInfo decodes a ClientInfo report and stores it, returning true if
+// it changed. Reports that fail to decode are ignored.
+func (rq *Request) setClientInfo(data string) (changed bool) {
+	var ci ClientInfo
+	if data != "" && json.Unmarshal([]byte(data), &ci) == nil {
+		if len(ci.Languages) > maxClientLanguages {
+			ci.Languages = ci.Languages[:maxClientLanguages]
+		}
+		rq.mu.Lock()
+		if changed = !rq.clientInfo.equal(ci); changed {
+			rq.clientInfo = ci
+		}
+		rq.mu.Unlock()
+	}
+	return
+}
+
+// handleClientInfo stores a Client event report, dirtying the ClientInfoTag
+// on the Request if it changed. Called only from process.
+func (rq *Request) handleClientInfo(data string) {
+	if rq.setClientInfo(data) {
+		rq.appendDirtyTags([]any{rq.Jaws.ClientInfoTag()})
+	}
+}
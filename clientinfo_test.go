@@ -0,0 +1,87 @@
+package jaws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+func TestClientInfo_ConnectAndChange(t *testing.T) {
+	jw, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(jw.Close)
+	go jw.Serve()
+	waitForServeLoop(t, jw)
+	server := httptest.NewServer(jw)
+	t.Cleanup(server.Close)
+
+	hr := httptest.NewRequest(http.MethodGet, server.URL+"/", nil)
+	hr.RemoteAddr = "127.0.0.1:1"
+	rq := jw.NewRequest(httptest.NewRecorder(), hr)
+	if ci := rq.ClientInfo(); !ci.equal(ClientInfo{}) {
+		t.Errorf("before connect %+v", ci)
+	}
+	connectCh := make(chan ClientInfo, 1)
+	rq.SetConnectFn(func(rq *Request) error {
+		connectCh <- rq.ClientInfo()
+		return nil
+	})
+	ui := &testUi{}
+	rq.NewElement(ui).Tag(jw.ClientInfoTag())
+
+	hdr := http.Header{}
+	hdr.Set("Origin", server.URL)
+	ctx, cancel := context.WithTimeout(t.Context(), testTimeout)
+	defer cancel()
+	info := `{"timeZone":"Europe/Stockholm","languages":["sv-SE","en"],"width":800,"height":600,"pixelRatio":2,"colorScheme":"dark"}`
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/jaws/"+rq.JawsKeyString()+"?client="+url.QueryEscape(info), &websocket.DialOptions{HTTPHeader: hdr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+
+	ci := <-connectCh
+	want := ClientInfo{TimeZone: "Europe/Stockholm", Languages: []string{"sv-SE", "en"}, Width: 800, Height: 600, PixelRatio: 2, ColorScheme: "dark"}
+	if !ci.equal(want) {
+		t.Errorf("ConnectFn got %+v", ci)
+	}
+	if loc := ci.Location(); loc.String() != "Europe/Stockholm" {
+		t.Errorf("location %v", loc)
+	}
+	if loc := (ClientInfo{TimeZone: "Nowhere/Special"}).Location(); loc != time.UTC {
+		t.Errorf("unknown location %v", loc)
+	}
+
+	// An unchanged report does not dirty the tag; a changed one does.
+	send := func(data string) {
+		t.Helper()
+		if err := conn.Write(ctx, websocket.MessageText, []byte("Client\t\t"+data+"\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send(strconv.Quote(info))
+	send(strconv.Quote(`{"width":1024,"hidden":true}`))
+	deadline := time.Now().Add(testTimeout)
+	for rq.ClientInfo().Width != 1024 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	for atomic.LoadInt32(&ui.updateCalled) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := rq.ClientInfo(); !got.equal(ClientInfo{Width: 1024, Hidden: true}) {
+		t.Errorf("after change %+v", got)
+	}
+	if n := atomic.LoadInt32(&ui.updateCalled); n != 1 {
+		t.Errorf("updates %d", n)
+	}
+}
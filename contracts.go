@@ -154,6 +154,54 @@ type ContextMenuHandler interface {
 	JawsContextMenu(elem *Element, click Click) (err error)
 }
 
+// KeyHandler handles keyboard events sent from the browser.
+type KeyHandler interface {
+	// JawsKey is called for KeyDown and KeyUp events on an [Element].
+	//
+	// The bundled client only listens for keys listed in the Element's
+	// data-jawskeydown and data-jawskeyup attributes, as whitespace-separated
+	// KeyboardEvent.key or KeyboardEvent.code values. A value may be prefixed
+	// with "Shift+", "Control+", "Alt+" or "Meta+" to require that modifier:
+	//
+	//	<div tabindex="0" data-jawskeydown="ArrowUp ArrowDown Enter Control+s">
+	//
+	// Matching keys are not passed on to the browser's default handling. Elements
+	// that are not form controls need a tabindex to receive keyboard focus.
+	//
+	// Events that occur while the bundled client's WebSocket is not open are not
+	// forwarded or replayed.
+	JawsKey(elem *Element, key Key) (err error)
+}
+
+// OrderHandler handles the user reordering the children of an [Element].
+type OrderHandler interface {
+	// JawsOrder is called for a Reorder event on an [Element] with the UI
+	// values of its listed children in their new browser order. Each listed
+	// Element is passed at most once, and those no longer registered with the
+	// Request, or created before elem and so not its children, are left out.
+	// The server does not see the browser's DOM, so a handler that keeps its
+	// children, as ui.Container does, must still ignore values it does not own.
+	//
+	// The bundled client lets the user drag the managed direct children of an
+	// element carrying a data-jawssortable attribute, and sends the new order
+	// when a drag ends. The browser shows that order until the server replies,
+	// so re-render or update the Element afterwards if the order is rejected.
+	//
+	// Events that occur while the bundled client's WebSocket is not open are not
+	// forwarded or replayed.
+	JawsOrder(elem *Element, children []UI) (err error)
+}
+
+// DOMEventHandler handles DOM events forwarded from the browser.
+type DOMEventHandler interface {
+	// JawsDOMEvent is called for a DOM event an [Element] opted into
+	// forwarding with [ForwardDOMEvents] or a data-jawsevents attribute.
+	//
+	// Events that occur while the bundled client's WebSocket is not open are not
+	// forwarded or replayed.
+	JawsDOMEvent(elem *Element, ev DOMEvent) (err error)
+}
+
 // InitialHTMLAttrHandler provides attributes for initial [Element] rendering.
 type InitialHTMLAttrHandler interface {
 	// JawsInitialHTMLAttr returns attributes for elem's initial render, or an empty string.
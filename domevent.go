@@ -0,0 +1,68 @@
+package jaws
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/url"
+	"strings"
+
+	"github.com/linkdata/jaws/lib/htmlio"
+)
+
+// DOMEvent describes a DOM event forwarded by the bundled client; see
+// [DOMEventHandler].
+//
+// Only the fields that apply to the event type are set.
+type DOMEvent struct {
+	Type         string  `json:"type"`         // Type is the DOM event type, such as "focus", "wheel" or "submit".
+	X            float64 `json:"x"`            // X is the clientX coordinate in CSS pixels of mouse, pointer, wheel and touch events.
+	Y            float64 `json:"y"`            // Y is the clientY coordinate in CSS pixels of mouse, pointer, wheel and touch events.
+	DeltaX       float64 `json:"deltaX"`       // DeltaX is the horizontal scroll amount of a wheel event.
+	DeltaY       float64 `json:"deltaY"`       // DeltaY is the vertical scroll amount of a wheel event.
+	ScrollLeft   float64 `json:"scrollLeft"`   // ScrollLeft is the Element's scrollLeft after a scroll event.
+	ScrollTop    float64 `json:"scrollTop"`    // ScrollTop is the Element's scrollTop after a scroll event.
+	ClientWidth  float64 `json:"clientWidth"`  // ClientWidth is the Element's clientWidth after a scroll event.
+	ClientHeight float64 `json:"clientHeight"` // ClientHeight is the Element's clientHeight after a scroll event.
+	Buttons      int     `json:"buttons"`      // Buttons is the MouseEvent.buttons bitmask of mouse, pointer and wheel events.
+	PointerType  string  `json:"pointerType"`  // PointerType is "mouse", "pen" or "touch" for pointer events.
+	Touches      int     `json:"touches"`      // Touches is the number of touch points still in contact for touch events.
+	Shift        bool    `json:"shift"`        // Shift reports whether the Shift key was held during the event.
+	Control      bool    `json:"control"`      // Control reports whether the Control key was held during the event.
+	Alt          bool    `json:"alt"`          // Alt reports whether the Alt key was held during the event.
+	Meta         bool    `json:"meta"`         // Meta reports whether the Meta key was held during the event.
+	// Value holds the URL-encoded fields of the form for a submit event. File
+	// fields are left out; see [DOMEvent.Form].
+	Value string `json:"value"`
+}
+
+// Form returns the form fields of a submit event, or nil if there are none.
+func (ev DOMEvent) Form() (values url.Values) {
+	if ev.Value != "" {
+		values, _ = url.ParseQuery(ev.Value) // keeps the fields parsed before any malformed one
+	}
+	return
+}
+
+func parseDOMEventData(value string) (ev DOMEvent, ok bool) {
+	ok = json.Unmarshal([]byte(value), &ev) == nil && ev.Type != ""
+	return
+}
+
+// ForwardDOMEvents returns a data-jawsevents attribute making the bundled
+// client forward the listed DOM events of an Element to its [DOMEventHandler]s.
+// Pass it as a render parameter, like any other [template.HTMLAttr].
+//
+// Each spec is one or more whitespace-separated event types, such as "focus",
+// "blur", "dblclick", "mouseenter", "mouseleave", "wheel", "scroll", "submit",
+// "pointermove" or "touchstart". An event type may be suffixed with
+// ":throttle=MS" to send at most one event every MS milliseconds, keeping the
+// latest, or ":debounce=MS" to send only the latest event once none have
+// occurred for MS milliseconds:
+//
+//	jaws.ForwardDOMEvents("dblclick", "wheel:throttle=100", "scroll:debounce=250")
+//
+// The client prevents the default action of submit events. Malformed specs
+// are ignored by the client.
+func ForwardDOMEvents(specs ...string) template.HTMLAttr {
+	return htmlio.Attr("data-jawsevents", strings.Join(specs, " "))
+}
@@ -0,0 +1,138 @@
+package jaws
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/linkdata/jaws/lib/key"
+	"github.com/linkdata/jaws/lib/what"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+// download is content staged by [Request.Download] until the browser fetches
+// it.
+type download struct {
+	name     string
+	mimeType string
+	io.Reader
+}
+
+func (dl *download) close() {
+	if c, ok := dl.Reader.(io.Closer); ok {
+		_ = c.Close()
+	}
+}
+
+// Download makes the browser save the content read from r as a file named
+// name, with the MIME type mimeType, or "application/octet-stream" if empty.
+//
+// The content is staged behind a single-use URL under "/jaws/.download/",
+// which the bundled client fetches as an attachment. [Jaws.ServeHTTP] serves it
+// once, only while the Request's WebSocket is connected and only to the
+// Request's remote IP. r is read when the browser fetches the URL, on that
+// HTTP request's goroutine, so it must remain valid after Download returns. If
+// r implements [io.Closer], it is closed after being read, or when the
+// Request ends without the browser fetching it. Nothing is staged, and r is
+// closed at once, if the Request has no running WebSocket.
+//
+// See [Request] for pointer lifetime and [Jaws.Broadcast] for processing-loop
+// requirements.
+func (rq *Request) Download(name, mimeType string, r io.Reader) {
+	dl := &download{name: name, mimeType: mimeType, Reader: r}
+	if k := rq.destKey(); k != 0 {
+		rq.Jaws.mu.Lock()
+		token := rq.Jaws.nonZeroRandomLocked()
+		rq.Jaws.mu.Unlock()
+		rq.mu.Lock()
+		// Only a running Request ends in stopServe, which calls dropDownloads,
+		// and once rq is cancelled that may already have run.
+		staged := rq.loadState() == reqRunning && rq.ctx.Err() == nil
+		if staged {
+			if rq.downloads == nil {
+				rq.downloads = make(map[key.Key]*download)
+			}
+			rq.downloads[token] = dl
+		}
+		rq.mu.Unlock()
+		if staged {
+			rq.Jaws.Broadcast(wire.Message{
+				Dest: k,
+				What: what.Download,
+				Data: "/jaws/.download/" + k.String() + "/" + token.String(),
+			})
+			return
+		}
+	}
+	dl.close()
+}
+
+// CopyToClipboard makes the browser write text to the system clipboard.
+//
+// Browsers may refuse clipboard writes from a page that is not focused or
+// that the user has not recently interacted with, so call it in response to a
+// browser event such as a click. A refusal is logged to the browser console.
+//
+// See [Request] for pointer lifetime and [Jaws.Broadcast] for processing-loop
+// requirements.
+func (rq *Request) CopyToClipboard(text string) {
+	if k := rq.destKey(); k != 0 {
+		rq.Jaws.Broadcast(wire.Message{
+			Dest: k,
+			What: what.Clipboard,
+			Data: text,
+		})
+	}
+}
+
+// takeDownload removes and returns the content staged with token.
+func (rq *Request) takeDownload(token key.Key) (dl *download) {
+	rq.mu.Lock()
+	if dl = rq.downloads[token]; dl != nil {
+		delete(rq.downloads, token)
+	}
+	rq.mu.Unlock()
+	return
+}
+
+// dropDownloads closes the content staged but not fetched when rq ends.
+func (rq *Request) dropDownloads() {
+	rq.mu.Lock()
+	downloads := rq.downloads
+	rq.downloads = nil
+	rq.mu.Unlock()
+	for _, dl := range downloads {
+		dl.close()
+	}
+}
+
+// serveDownload handles a GET of "/jaws/.download/<key>/<token>", returning
+// false if the path does not name content staged by a running Request.
+func (jw *Jaws) serveDownload(w http.ResponseWriter, r *http.Request) (handled bool) {
+	if rest, ok := strings.CutPrefix(r.URL.Path, "/jaws/.download/"); ok {
+		if jawsKey, tail := key.Parse(rest); jawsKey != 0 {
+			if tokenString, ok := strings.CutPrefix(tail, "/"); ok {
+				if token, tail := key.Parse(tokenString); token != 0 && tail == "" {
+					if rq := jw.runningRequest(jawsKey, r); rq != nil {
+						if dl := rq.takeDownload(token); dl != nil {
+							defer dl.close()
+							mimeType := dl.mimeType
+							if mimeType == "" {
+								mimeType = "application/octet-stream"
+							}
+							hdr := w.Header()
+							hdr.Set("Cache-Control", headerCacheControlNoStore)
+							hdr.Set("Content-Type", mimeType)
+							hdr.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": dl.name}))
+							hdr.Set("X-Content-Type-Options", "nosniff")
+							_, _ = io.Copy(w, dl)
+							handled = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,126 @@
+package jaws
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+type testDownloadReader struct {
+	io.Reader
+	closed atomic.Bool
+}
+
+func (r *testDownloadReader) Close() error {
+	r.closed.Store(true)
+	return nil
+}
+
+func TestDownload_ServeHTTP(t *testing.T) {
+	_, server, rq, dial := newResumeTestRequest(t, 0)
+
+	// A Request that is not running, and so may be retired without ever
+	// running, does not stage content.
+	early := &testDownloadReader{Reader: strings.NewReader("x")}
+	rq.Download("early.txt", "text/plain", early)
+	if !early.closed.Load() || rq.downloads != nil {
+		t.Error("content staged before the Request runs")
+	}
+
+	conn, err := dial("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+	deadline := time.Now().Add(3 * time.Second)
+	for rq.loadState() != reqRunning && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	next := func(prefix string) string {
+		t.Helper()
+		for {
+			_, data, err := conn.Read(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for line := range strings.SplitSeq(string(data), "\n") {
+				if s, ok := strings.CutPrefix(line, prefix); ok {
+					s, err = strconv.Unquote(s)
+					if err != nil {
+						t.Fatal(err)
+					}
+					return s
+				}
+			}
+		}
+	}
+	get := func(path string) (resp *http.Response, body string) {
+		t.Helper()
+		resp, err := server.Client().Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return resp, string(b)
+	}
+
+	rq.CopyToClipboard("copied\ttext")
+	if got := next("Clipboard\t\t"); got != "copied\ttext" {
+		t.Errorf("clipboard %q", got)
+	}
+
+	content := &testDownloadReader{Reader: strings.NewReader("a,b\n1,2\n")}
+	rq.Download("räkning.csv", "text/csv", content)
+	path := next("Download\t\t")
+	if !strings.HasPrefix(path, "/jaws/.download/"+rq.JawsKeyString()+"/") {
+		t.Fatalf("download path %q", path)
+	}
+	if resp, _ := get("/jaws/.download/" + rq.JawsKeyString() + "/1"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown token: %d", resp.StatusCode)
+	}
+	resp, body := get(path)
+	if resp.StatusCode != http.StatusOK || body != "a,b\n1,2\n" {
+		t.Fatalf("download: %d %q", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "text/csv" {
+		t.Errorf("Content-Type %q", got)
+	}
+	if got := resp.Header.Get("Content-Disposition"); got != "attachment; filename*=utf-8''r%C3%A4kning.csv" {
+		t.Errorf("Content-Disposition %q", got)
+	}
+	if !content.closed.Load() {
+		t.Error("content not closed after download")
+	}
+	if resp, _ := get(path); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("second download: %d", resp.StatusCode)
+	}
+
+	rq.Download("data.bin", "", strings.NewReader("x"))
+	path = next("Download\t\t")
+	if resp, _ := get(path); resp.Header.Get("Content-Type") != "application/octet-stream" {
+		t.Errorf("default Content-Type %q", resp.Header.Get("Content-Type"))
+	}
+
+	// Content not fetched before the Request ends is closed.
+	unfetched := &testDownloadReader{Reader: strings.NewReader("x")}
+	rq.Download("late.txt", "text/plain", unfetched)
+	path = next("Download\t\t")
+	_ = conn.Close(websocket.StatusNormalClosure, "")
+	for !unfetched.closed.Load() && time.Now().Before(deadline.Add(3*time.Second)) {
+		time.Sleep(time.Millisecond)
+	}
+	if !unfetched.closed.Load() {
+		t.Error("unfetched content not closed")
+	}
+	if resp, _ := get(path); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("download after close: %d", resp.StatusCode)
+	}
+}
@@ -1,10 +1,12 @@
 package jaws
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
 	"io"
+	"strconv"
 	"strings"
 	"sync/atomic"
 
@@ -46,6 +48,7 @@ type Element struct {
 	jid     jid.Jid     // JaWS ID, unique to this Element within its Request
 	deleted atomic.Bool // true once the Element has been removed from its Request
 	frozen  atomic.Bool // set when handlers are sealed (JawsRender returns or Freeze called); guards handler mutators in all builds
+	busy    atomic.Bool // set when rendered with a BusyPolicy; the eventCaller then queues an Ack after each event
 }
 
 // String returns a debug representation of elem: its UI type, Jid, and tags.
@@ -334,6 +337,70 @@ func (elem *Element) SetValue(value string) {
 	elem.queue(what.Value, value)
 }
 
+// ScrollOptions are the options for [Element.ScrollIntoView], as for the
+// browser's scrollIntoView(). Empty fields use the browser defaults.
+type ScrollOptions struct {
+	Behavior string `json:"behavior,omitempty"` // "smooth", "instant" or "auto"
+	Block    string `json:"block,omitempty"`    // "start", "center", "end" or "nearest"
+	Inline   string `json:"inline,omitempty"`   // "start", "center", "end" or "nearest"
+}
+
+func (opts ScrollOptions) data() string {
+	b, _ := json.Marshal(opts) // cannot fail for strings
+	return string(b)
+}
+
+func selectData(start, end int) string {
+	return strconv.Itoa(max(start, 0)) + " " + strconv.Itoa(max(end, -1))
+}
+
+// Focus queues moving keyboard focus to the [Element].
+//
+// Like [Element.SetAttr], call this while the [Element] is rendering or
+// updating. To focus an Element added in response to a browser event, such as
+// the first field of a new row, call Focus from its render or update. Queued
+// commands are sent in [Jid] order, and a new Element has a higher Jid than the
+// container appending it, so the Focus follows the Append that inserts it.
+func (elem *Element) Focus() {
+	elem.queue(what.Focus, "")
+}
+
+// ScrollIntoView queues scrolling the [Element] into view using opts.
+//
+// Like [Element.SetAttr], call this while the [Element] is rendering or
+// updating.
+func (elem *Element) ScrollIntoView(opts ScrollOptions) {
+	elem.queue(what.Scroll, opts.data())
+}
+
+// Select queues focusing the [Element] and selecting its text from offset start
+// up to end. A negative end selects to the end of the text, so Select(0, -1)
+// selects all of it. The Element should be an input or textarea whose type
+// supports a selection.
+//
+// Like [Element.SetAttr], call this while the [Element] is rendering or
+// updating.
+func (elem *Element) Select(start, end int) {
+	elem.queue(what.Select, selectData(start, end))
+}
+
+// ShowModal queues opening the [Element], which must be a <dialog>, as a
+// modal. It does nothing if the dialog is already open.
+//
+// Like [Element.SetAttr], call this while the [Element] is rendering or
+// updating.
+func (elem *Element) ShowModal() {
+	elem.queue(what.ShowModal, "")
+}
+
+// Close queues closing the [Element], which must be a <dialog>.
+//
+// Like [Element.SetAttr], call this while the [Element] is rendering or
+// updating.
+func (elem *Element) Close() {
+	elem.queue(what.Close, "")
+}
+
 // JsCall queues a browser JavaScript function path call for the [Element].
 //
 // In the receiving browser, jsfunc is resolved as a path from window and called
@@ -484,7 +551,8 @@ func (elem *Element) validChildElement(operation string, child *Element) (ok boo
 // ApplyParams applies UI-helper parameters to elem.
 //
 // For a live Element, it registers tags and event handlers and returns any HTML
-// attributes found by [ParseParams]. A deleted Element applies nothing and returns nil.
+// attributes found by [ParseParams]. A [BusyPolicy] param also makes the Element
+// acknowledge each handled event. A deleted Element applies nothing and returns nil.
 //
 // On a live, frozen Element, handler params are queued for logging and dropped
 // in production when [Jaws.Logger] is configured, after which tags and attributes
@@ -493,6 +561,11 @@ func (elem *Element) validChildElement(operation string, child *Element) (ok boo
 func (elem *Element) ApplyParams(params []any) (attrs []template.HTMLAttr) {
 	tags, handlers, rawAttrs := ParseParams(params)
 	if !elem.deleted.Load() {
+		for _, p := range params {
+			if _, ok := p.(BusyPolicy); ok {
+				elem.busy.Store(true)
+			}
+		}
 		elem.appendHandlers(handlers...)
 		elem.Tag(tags...)
 		for _, s := range rawAttrs {
@@ -513,9 +586,12 @@ func (elem *Element) ApplyParams(params []any) (attrs []template.HTMLAttr) {
 // TagGetter or a []any containing one. Other non-comparable candidates are not
 // automatically tagged.
 //
-// If getter implements [InputHandler], [ClickHandler], or [ContextMenuHandler], it is
-// added as an event handler. ApplyGetter does not invoke [InitialHTMLAttrHandler];
-// call [Element.ApplyInitialHTMLAttr] separately.
+// If getter implements [InputHandler], [ClickHandler], [ContextMenuHandler],
+// [KeyHandler], [DOMEventHandler] or [UploadHandler], it is added as an event
+// handler. ApplyGetter does not invoke [InitialHTMLAttrHandler]; call
+// [Element.ApplyInitialHTMLAttr] separately. An [OrderHandler] getter is left
+// to the UI that applied it, since container widgets must see a reordering of
+// their children.
 //
 // The returned tagValue does not confirm registration. It is nil if getter or its
 // candidate is a nil interface, or if the candidate is ineligible for expansion. A
@@ -541,6 +617,12 @@ func (elem *Element) ApplyGetter(getter any) (tagValue any) {
 			elem.appendHandlers(getter)
 		} else if _, ok := getter.(ContextMenuHandler); ok {
 			elem.appendHandlers(getter)
+		} else if _, ok := getter.(KeyHandler); ok {
+			elem.appendHandlers(getter)
+		} else if _, ok := getter.(DOMEventHandler); ok {
+			elem.appendHandlers(getter)
+		} else if _, ok := getter.(UploadHandler); ok {
+			elem.appendHandlers(getter)
 		}
 		if eligibleAsTag(tagValue, tag.NewErrNotComparable) {
 			elem.Tag(tagValue)
@@ -299,6 +299,42 @@ func TestElement_ChildOperations(t *testing.T) {
 	}
 }
 
+func TestElement_FocusScrollSelectDialog(t *testing.T) {
+	jw, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jw.Close()
+	rq := jw.newRequest(nil)
+	defer jw.recycle(rq)
+
+	elem := rq.NewElement(&testUi{})
+	elem.Focus()
+	elem.ScrollIntoView(ScrollOptions{})
+	elem.ScrollIntoView(ScrollOptions{Behavior: "smooth", Block: "center"})
+	elem.Select(2, 5)
+	elem.Select(-1, -7)
+	elem.ShowModal()
+	elem.Close()
+	rq.DeleteElement(elem)
+	elem.Focus()
+
+	rq.muQueue.Lock()
+	defer rq.muQueue.Unlock()
+	want := []wire.WsMsg{
+		{Jid: elem.Jid(), What: what.Focus},
+		{Jid: elem.Jid(), What: what.Scroll, Data: `{}`},
+		{Jid: elem.Jid(), What: what.Scroll, Data: `{"behavior":"smooth","block":"center"}`},
+		{Jid: elem.Jid(), What: what.Select, Data: "2 5"},
+		{Jid: elem.Jid(), What: what.Select, Data: "0 -1"},
+		{Jid: elem.Jid(), What: what.ShowModal},
+		{Jid: elem.Jid(), What: what.Close},
+	}
+	if !reflect.DeepEqual(rq.wsQueue, want) {
+		t.Fatalf("queue = %+v, want %+v", rq.wsQueue, want)
+	}
+}
+
 func TestElement_ChildOperationsRejectInvalidElement(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -231,7 +231,8 @@ func newErrNoWebSocketRequest(rq *Request) error {
 }
 
 // ErrEventHandlerPanic is returned by [CallEventHandlers] when a user event handler
-// panics.
+// panics. Panics in [RPCFn] functions and toast callbacks are reported the same
+// way.
 //
 // Match it with [errors.Is]. When the recovered panic value is itself an error it is
 // available via Unwrap (and thus [errors.As] / [errors.Is]); a non-error panic value
@@ -267,6 +268,32 @@ func (errEventUnhandled) Error() string {
 	return "event unhandled"
 }
 
-// ErrEventUnhandled returned by [InputHandler.JawsInput], [ClickHandler.JawsClick]
-// or [ContextMenuHandler.JawsContextMenu] causes the next available handler to be invoked.
+// ErrEventUnhandled returned by [InputHandler.JawsInput], [ClickHandler.JawsClick],
+// [ContextMenuHandler.JawsContextMenu] or [KeyHandler.JawsKey] causes the next
+// available handler to be invoked.
 var ErrEventUnhandled = errEventUnhandled{}
+
+// ErrSessionValueNotRegistered is returned by [JSONSessionCodec] when a
+// [SessionRecord] holds a value whose type was not registered with
+// [JSONSessionCodec.Register], or the encoded data names an unknown type.
+var ErrSessionValueNotRegistered = errors.New("session value type not registered")
+
+// ErrPortableTagNotRegistered is returned by [Jaws.ReceiveBroadcast] when a
+// packet names a tag type that was not registered with [Jaws.RegisterPortableTag].
+var ErrPortableTagNotRegistered = errors.New("portable tag type not registered")
+
+// ErrInvalidBroadcastPacket is returned by [Jaws.ReceiveBroadcast] for a packet
+// that cannot be decoded or carries a command that may not be broadcast.
+var ErrInvalidBroadcastPacket = errors.New("invalid broadcast packet")
+
+// ErrResumeWindowExpired is the cancellation cause of a [Request] whose
+// WebSocket was not resumed within [Jaws.ResumeWindow].
+var ErrResumeWindowExpired = errors.New("resume window expired")
+
+// ErrRequestNotRunning is returned by [Request.Confirm], [Request.Prompt] and
+// [Request.Query] when the Request has no running WebSocket to ask through.
+var ErrRequestNotRunning = errors.New("request not running")
+
+// ErrUploadTooLarge is returned by an [UploadHandler] to reject a file that
+// exceeds its size limit. The browser receives HTTP status 413.
+var ErrUploadTooLarge = errors.New("upload too large")
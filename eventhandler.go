@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
+	"github.com/linkdata/jaws/lib/jid"
 	"github.com/linkdata/jaws/lib/what"
 )
 
@@ -65,12 +67,51 @@ func callEventHandler(obj any, elem *Element, wht what.What, value string) (err
 				err = h.JawsContextMenu(elem, clk)
 			}
 		}
+	case what.KeyDown, what.KeyUp:
+		if h, ok := obj.(KeyHandler); ok {
+			var key Key
+			if key, ok = parseKeyData(value); ok {
+				key.Up = wht == what.KeyUp
+				err = h.JawsKey(elem, key)
+			}
+		}
+	case what.Reorder:
+		if h, ok := obj.(OrderHandler); ok {
+			err = h.JawsOrder(elem, elem.Request.orderedChildren(elem, value))
+		}
+	case what.DOMEvent:
+		if h, ok := obj.(DOMEventHandler); ok {
+			var ev DOMEvent
+			if ev, ok = parseDOMEventData(value); ok {
+				err = h.JawsDOMEvent(elem, ev)
+			}
+		}
 	case what.Input, what.Hook, what.Set:
 		err = callInputHandler(obj, elem, value)
 	}
 	return
 }
 
+// orderedChildren returns the UI values of the registered Elements listed in
+// data, a space-separated Jid list, in order. An Element is created after the
+// parent rendering it, so only Elements with a higher Jid than parent can be
+// its children; others, and repeated Jids, are left out.
+func (rq *Request) orderedChildren(parent *Element, data string) (children []UI) {
+	rq.mu.RLock()
+	defer rq.mu.RUnlock()
+	seen := make(map[Jid]struct{})
+	for s := range strings.FieldsSeq(data) {
+		id := jid.ParseString(s)
+		if _, dup := seen[id]; !dup && id > parent.Jid() {
+			if elem := rq.getElementByJidLocked(id); elem != nil && !elem.deleted.Load() {
+				seen[id] = struct{}{}
+				children = append(children, elem.UI())
+			}
+		}
+	}
+	return
+}
+
 func callEventHandlers(ui any, elem *Element, wht what.What, value string) (err error) {
 	for i := len(elem.handlers) - 1; i >= 0; i-- {
 		if err = callEventHandler(elem.handlers[i], elem, wht, value); !errors.Is(err, ErrEventUnhandled) {
@@ -833,3 +833,93 @@ func Test_JawsInput_ExtraHandler(t *testing.T) {
 		th.Equal(s, `JawsInput: "typed"`)
 	}
 }
+
+type testOrderHandler struct {
+	children []UI
+}
+
+func (h *testOrderHandler) JawsOrder(elem *Element, children []UI) error {
+	h.children = children
+	return nil
+}
+
+func Test_CallEventHandlers_Reorder(t *testing.T) {
+	rq := newTestRequest(t)
+	defer rq.Close()
+	older := rq.NewElement(testDivWidget{inner: "older"})
+	h := &testOrderHandler{}
+	parent := rq.NewElement(testDivWidget{inner: "parent"})
+	parent.AddHandlers(h)
+	parent.Freeze()
+	first := testDivWidget{inner: "first"}
+	second := testDivWidget{inner: "second"}
+	elem1 := rq.NewElement(first)
+	elem2 := rq.NewElement(second)
+	gone := rq.NewElement(testDivWidget{inner: "gone"})
+	rq.DeleteElement(gone)
+
+	// Deleted, older, repeated and unparsable Jids and the parent itself are
+	// left out.
+	data := elem2.Jid().String() + " " + gone.Jid().String() + " junk " + older.Jid().String() +
+		" " + parent.Jid().String() + " " + elem1.Jid().String() + " " + elem2.Jid().String()
+	if err := rq.callAllEventHandlers(parent.Jid(), what.Reorder, data); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.children) != 2 || h.children[0] != second || h.children[1] != first {
+		t.Errorf("got %v", h.children)
+	}
+	if _, handlers, _ := ParseParams([]any{h}); len(handlers) != 1 {
+		t.Error("ParseParams did not recognize an OrderHandler")
+	}
+	if err := CallEventHandlers(testDivWidget{}, parent, what.Reorder, ""); err != nil {
+		t.Errorf("empty order: %v", err)
+	}
+}
+
+type testDOMEventHandler struct {
+	events []DOMEvent
+}
+
+func (h *testDOMEventHandler) JawsDOMEvent(elem *Element, ev DOMEvent) error {
+	h.events = append(h.events, ev)
+	return nil
+}
+
+func Test_CallEventHandlers_DOMEvent(t *testing.T) {
+	rq := newTestRequest(t)
+	defer rq.Close()
+	h := &testDOMEventHandler{}
+	elem := rq.NewElement(testDivWidget{inner: "x"})
+	elem.AddHandlers(h)
+	elem.Freeze()
+
+	data := `{"type":"submit","x":1.5,"shift":true,"value":"a=1&b=x+y&a=2"}`
+	if err := rq.callAllEventHandlers(elem.Jid(), what.DOMEvent, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := callEventHandler(h, elem, what.DOMEvent, `{"x":1}`); !errors.Is(err, ErrEventUnhandled) {
+		t.Errorf("missing type: %v", err)
+	}
+	if err := callEventHandler(h, elem, what.DOMEvent, "junk"); !errors.Is(err, ErrEventUnhandled) {
+		t.Errorf("junk: %v", err)
+	}
+	if len(h.events) != 1 {
+		t.Fatalf("got %v", h.events)
+	}
+	ev := h.events[0]
+	if ev.Type != "submit" || ev.X != 1.5 || !ev.Shift || ev.Control {
+		t.Errorf("got %+v", ev)
+	}
+	if form := ev.Form(); len(form["a"]) != 2 || form.Get("b") != "x y" {
+		t.Errorf("form %v", form)
+	}
+	if form := (DOMEvent{Type: "focus"}).Form(); form != nil {
+		t.Errorf("focus form %v", form)
+	}
+	if _, handlers, _ := ParseParams([]any{h}); len(handlers) != 1 {
+		t.Error("ParseParams did not recognize a DOMEventHandler")
+	}
+	if got := ForwardDOMEvents("focus blur", `wheel:throttle=100"`); got != `data-jawsevents="focus blur wheel:throttle=100&#34;"` {
+		t.Errorf("attr %q", got)
+	}
+}
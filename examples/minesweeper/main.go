@@ -177,6 +177,17 @@ func (c *Cell) JawsContextMenu(elem *jaws.Element, click jaws.Click) error {
 	return nil
 }
 
+// JawsKey toggles the cell flag when F is pressed on the focused cell, giving
+// keyboard users the equivalent of the context menu. The template registers the
+// key with data-jawskeydown, so other keys keep their native behavior and a
+// focused cell button still reveals with Enter or Space.
+func (c *Cell) JawsKey(elem *jaws.Element, key jaws.Key) error {
+	if !key.Up && !key.Repeat && (key.Key == "f" || key.Key == "F") {
+		elem.Request.Dirty(c.game.toggleFlag(c)...)
+	}
+	return nil
+}
+
 type game struct {
 	mu sync.Mutex
 
@@ -105,6 +105,13 @@ func TestCellButtonUsesCellTagsAndHandlers(t *testing.T) {
 		t.Fatalf("flags = %d, want 1", g.flags)
 	}
 
+	if err := jaws.CallEventHandlers(elem.UI(), elem, what.KeyDown, "0 KeyF f"); err != nil {
+		t.Fatalf("key error: %v", err)
+	}
+	if cell.flagged {
+		t.Fatal("expected F key to toggle the flag off")
+	}
+
 	other := g.cells[0][1]
 	otherElem := rq.NewElement(ui.NewButton(other))
 	if err := otherElem.JawsRender(&body, []any{`class="cell"`}); err != nil {
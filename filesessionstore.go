@@ -0,0 +1,117 @@
+package jaws
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/linkdata/deadlock"
+	"github.com/linkdata/jaws/lib/key"
+)
+
+const fileSessionSuffix = ".session"
+
+// FileSessionStore is a [SessionStore] keeping one file per [Session] in a directory.
+//
+// Records are written atomically by renaming a temporary file, so a
+// FileSessionStore on a shared file system may be used by several [Jaws]
+// instances at once.
+type FileSessionStore struct {
+	Dir   string       // (read-only) directory holding the session files
+	Codec SessionCodec // (read-only) codec used for the session files
+	mu    deadlock.Mutex
+}
+
+var _ SessionStore = (*FileSessionStore)(nil)
+
+// NewFileSessionStore returns a [FileSessionStore] storing records in dir using codec,
+// creating dir if needed. If codec is nil, [GobSessionCodec] is used.
+func NewFileSessionStore(dir string, codec SessionCodec) (fss *FileSessionStore, err error) {
+	if codec == nil {
+		codec = GobSessionCodec{}
+	}
+	if err = os.MkdirAll(dir, 0o700); err == nil {
+		fss = &FileSessionStore{Dir: dir, Codec: codec}
+	}
+	return
+}
+
+func (fss *FileSessionStore) fileName(id key.Key) string {
+	return filepath.Join(fss.Dir, id.String()+fileSessionSuffix)
+}
+
+func (fss *FileSessionStore) loadFile(fn string) (rec *SessionRecord, err error) {
+	var f *os.File
+	if f, err = os.Open(fn); err == nil { // #nosec G304 -- fn is built from a key.Key inside Dir
+		defer f.Close()
+		rec, err = fss.Codec.DecodeSession(f)
+	}
+	return
+}
+
+// LoadSession implements [SessionStore].
+func (fss *FileSessionStore) LoadSession(id key.Key) (rec *SessionRecord, err error) {
+	if rec, err = fss.loadFile(fss.fileName(id)); errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+	return
+}
+
+// SaveSession implements [SessionStore].
+func (fss *FileSessionStore) SaveSession(rec *SessionRecord) (err error) {
+	fss.mu.Lock()
+	defer fss.mu.Unlock()
+	var f *os.File
+	if f, err = os.CreateTemp(fss.Dir, "tmp-*"+fileSessionSuffix+".tmp"); err == nil {
+		tmpName := f.Name()
+		err = fss.Codec.EncodeSession(f, rec)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			err = os.Rename(tmpName, fss.fileName(rec.ID))
+		}
+		if err != nil {
+			_ = os.Remove(tmpName)
+		}
+	}
+	return
+}
+
+// DeleteSession implements [SessionStore].
+func (fss *FileSessionStore) DeleteSession(id key.Key) (err error) {
+	fss.mu.Lock()
+	defer fss.mu.Unlock()
+	if err = os.Remove(fss.fileName(id)); errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+	return
+}
+
+// ExpireSessions implements [SessionStore].
+//
+// Files that cannot be decoded are also removed.
+func (fss *FileSessionStore) ExpireSessions(now time.Time) (err error) {
+	fss.mu.Lock()
+	defer fss.mu.Unlock()
+	var entries []os.DirEntry
+	if entries, err = os.ReadDir(fss.Dir); err == nil {
+		var errs []error
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.Type().IsRegular() && strings.HasSuffix(name, fileSessionSuffix) {
+				fn := filepath.Join(fss.Dir, name)
+				if rec, lerr := fss.loadFile(fn); lerr != nil || rec.Expired(now) {
+					if rerr := os.Remove(fn); rerr != nil && !errors.Is(rerr, fs.ErrNotExist) {
+						errs = append(errs, rerr)
+					}
+				}
+			}
+		}
+		err = errors.Join(errs...)
+	}
+	return
+}
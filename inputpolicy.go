@@ -0,0 +1,40 @@
+package jaws
+
+import (
+	"html/template"
+	"strconv"
+	"time"
+)
+
+// InputOnChange is a render parameter making the bundled client send an input
+// Element's value only on its change event, when the user commits the edit,
+// rather than on every keystroke.
+const InputOnChange = template.HTMLAttr(`data-jawsinput="change"`)
+
+// InputDebounce returns a render parameter making the bundled client send an
+// input Element's value only once edits have paused for d, or when the user
+// commits the edit. It returns an empty attribute if d is less than a
+// millisecond.
+//
+// While an edit is held back, the client keeps it rather than applying value
+// updates from the server; the server reconciles once the edit is sent.
+func InputDebounce(d time.Duration) template.HTMLAttr {
+	return inputPolicy("debounce", d)
+}
+
+// InputThrottle returns a render parameter making the bundled client send an
+// input Element's value at most once every d while the user edits it, always
+// ending with the latest value. It returns an empty attribute if d is less
+// than a millisecond.
+//
+// Held back edits are treated as for [InputDebounce].
+func InputThrottle(d time.Duration) template.HTMLAttr {
+	return inputPolicy("throttle", d)
+}
+
+func inputPolicy(mode string, d time.Duration) (attr template.HTMLAttr) {
+	if ms := d.Milliseconds(); ms > 0 {
+		attr = template.HTMLAttr(`data-jawsinput="` + mode + "=" + strconv.FormatInt(ms, 10) + `"`) // #nosec G203
+	}
+	return
+}
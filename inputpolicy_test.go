@@ -0,0 +1,26 @@
+package jaws
+
+import (
+	"html/template"
+	"testing"
+	"time"
+)
+
+func TestInputPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		got  template.HTMLAttr
+		want template.HTMLAttr
+	}{
+		{"change", InputOnChange, `data-jawsinput="change"`},
+		{"debounce", InputDebounce(300 * time.Millisecond), `data-jawsinput="debounce=300"`},
+		{"throttle", InputThrottle(2 * time.Second), `data-jawsinput="throttle=2000"`},
+		{"sub-millisecond", InputDebounce(time.Microsecond), ""},
+		{"negative", InputThrottle(-time.Second), ""},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
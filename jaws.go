@@ -28,10 +28,12 @@ import (
 	"crypto/rand"
 	"errors"
 	"html/template"
+	"maps"
 	"net/http"
 	"net/netip"
 	"net/url"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -60,6 +62,10 @@ const (
 	// DefaultMaxPendingRequestsPerIP is the default maximum number of unclaimed
 	// Requests allowed for each client IP.
 	DefaultMaxPendingRequestsPerIP = 100
+
+	// DefaultSessionStoreTTL is the default time a [SessionRecord] stays valid
+	// after its Session was last in use.
+	DefaultSessionStoreTTL = time.Hour * 24
 )
 
 type subscription struct {
@@ -130,6 +136,27 @@ type Jaws struct {
 	// tag updates. Atomic operations may be used concurrently, including before
 	// serving.
 	StatusMetrics atomic.Uint32
+	// SessionStore optionally persists Sessions across Jaws instances.
+	//
+	// When nil (the default), Sessions live only in memory. See [SessionStore]
+	// for when records are loaded, saved, and deleted.
+	SessionStore SessionStore
+	// SessionStoreTTL is how long a persisted Session stays valid after it
+	// expires in memory, so it can be restored after a restart or by another
+	// instance. It defaults to [DefaultSessionStoreTTL]; non-positive values keep
+	// records only as long as the Session lives in memory.
+	SessionStoreTTL time.Duration
+	// ResumeWindow enables WebSocket resume when positive.
+	//
+	// A running Request whose WebSocket fails without a close handshake is kept
+	// for this long, buffering its outbound records, so the browser can reconnect
+	// and have the records it missed replayed instead of reloading the page. The
+	// default zero keeps the previous behavior. Call GenerateHeadHTML after
+	// changing it.
+	ResumeWindow time.Duration
+	// Broadcaster optionally publishes Dirty and Broadcast calls to peer
+	// instances. See [Broadcaster].
+	Broadcaster Broadcaster
 	// WebSocketPingInterval controls read-idle keepalive pings.
 	//
 	// When a WebSocket read remains pending for this interval, JaWS pings the peer.
@@ -150,6 +177,8 @@ type Jaws struct {
 	updateTicker            *time.Ticker
 	serving                 atomic.Bool
 	reportedErrors          atomic.Uint64
+	metrics                 metrics                              // counters exported by MetricsHandler
+	lifecycleObservers      atomic.Pointer[[]*lifecycleObserver] // copy-on-write; replaced under mu
 	loggerQueue             *loggerQueue
 	defaultAuthOnce         sync.Once    // guards lazy creation of defaultAuthVal
 	defaultAuthVal          *DefaultAuth // shared fail-open Auth; see [Jaws.DefaultAuth]
@@ -157,6 +186,8 @@ type Jaws struct {
 	serveJS                 *staticserve.StaticServe
 	serveCSS                *staticserve.StaticServe
 	statusTags              statusTags
+	nodeID                  key.Key          // random identity of this instance in broadcast packets
+	portableTags            typeRegistry     // tag types forwarded through Broadcaster
 	mu                      deadlock.RWMutex // protects following
 	headPrefix              string
 	faviconURL              string
@@ -171,6 +202,9 @@ type Jaws struct {
 	dirty                   map[any]int
 	dirtOrder               int
 	statusSample            statusSample
+	rpcFns                  map[string]RPCFn      // RPC functions registered with HandleRPC
+	sessionStoreExpiry      time.Time             // when maintenance last called SessionStore.ExpireSessions
+	sessionMisses           map[key.Key]time.Time // session IDs recently not found in the SessionStore, and when
 }
 
 // New allocates a JaWS instance with the default configuration.
@@ -189,6 +223,7 @@ func New() (jw *Jaws, err error) {
 				BaseContext:             context.Background(),
 				WebSocketPingInterval:   DefaultWebSocketPingInterval,
 				MaxPendingRequestsPerIP: DefaultMaxPendingRequestsPerIP,
+				SessionStoreTTL:         DefaultSessionStoreTTL,
 				webSocketTimeout:        DefaultWebSocketTimeout,
 				created:                 time.Now(),
 				serveJS:                 serveJS,
@@ -204,6 +239,8 @@ func New() (jw *Jaws, err error) {
 				dirty:                   make(map[any]int),
 				closeCh:                 make(chan struct{}),
 			}
+			tmp.nodeID = tmp.nonZeroRandomLocked()
+			_ = tmp.portableTags.register("tag.Tag", tag.Tag(""))
 			if err = tmp.GenerateHeadHTML(); err == nil {
 				jw = tmp
 				jw.loggerQueue = newLoggerQueue()
@@ -227,7 +264,9 @@ func New() (jw *Jaws, err error) {
 //
 // Registered [Session] values are invalidated and detached from their Requests,
 // and their key/value data is permanently cleared; new Sessions cannot be
-// created after shutdown begins.
+// created after shutdown begins. With a [Jaws.SessionStore], live Sessions are
+// saved before Close returns so a later Jaws instance can restore them, and
+// expired ones are deleted.
 //
 // Calls to [Jaws.NewRequest] after shutdown begins return Requests with
 // already-canceled contexts that [Jaws.UseRequest] cannot claim. Broadcasts and
@@ -247,6 +286,7 @@ func (jw *Jaws) Close() {
 		close(jw.closeCh)
 	}
 	jw.updateTicker.Stop()
+	var retired []*Request
 	for _, rq := range jw.requests {
 		if rq == nil {
 			continue
@@ -258,12 +298,16 @@ func (jw *Jaws) Close() {
 			// also safely handles a Request whose context is already done.
 			rq.cancelFn(nil)
 			rq.mu.Unlock()
-		} else {
-			jw.retireNonRunningRequestLocked(rq)
+		} else if jw.retireNonRunningRequestLocked(rq) {
+			retired = append(retired, rq)
 		}
 	}
-	jw.closeSessionsLocked()
+	closed := slices.Collect(maps.Values(jw.sessions))
+	records, expired := jw.closeSessionsLocked()
 	jw.mu.Unlock()
+	jw.storeClosedSessions(records, expired)
+	jw.notifyRequests(RequestRecycled, retired)
+	jw.notifySessions(SessionClosed, closed)
 }
 
 // Done returns a channel closed when [Jaws.Close] begins shutdown.
@@ -508,6 +552,9 @@ func (jw *Jaws) GenerateHeadHTML(extra ...string) (err error) {
 			if jw.Debug {
 				headPrefix += `<meta name="jawsDebug" content="true">`
 			}
+			if jw.ResumeWindow > 0 {
+				headPrefix += `<meta name="jawsResume" content="` + strconv.FormatInt(jw.ResumeWindow.Milliseconds(), 10) + `">`
+			}
 			headPrefix += `<meta name="jawsKey" content="`
 			cspHeader := secureheaders.BuildContentSecurityPolicyForURLs(urls...)
 			jw.mu.Lock()
@@ -581,7 +628,7 @@ func (jw *Jaws) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				}
 				return
 			default:
-				if r.Method == http.MethodGet && jw.serveTailScript(w, r) {
+				if r.Method == http.MethodGet && (jw.serveTailScript(w, r) || jw.serveDownload(w, r)) {
 					return
 				}
 			}
@@ -597,12 +644,23 @@ func (jw *Jaws) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			// defense) before upgrading. Consuming the key on a non-handshake request is
 			// acceptable because only a holder of the key can reach here.
 			jawsKey, tail := key.Parse(r.URL.Path[6:])
+			if jawsKey != 0 && tail == "" && r.URL.Query().Has("resume") {
+				jw.serveResume(w, r, jawsKey)
+				return
+			}
 			if jawsKey != 0 && (tail == "" || tail == "/noscript") {
 				if rq := jw.UseRequest(jawsKey, r); rq != nil {
 					rq.ServeHTTP(w, r)
 					return
 				}
 			}
+		} else if r.Method == http.MethodPost {
+			if jawsKey, tail := key.Parse(r.URL.Path[6:]); jawsKey != 0 {
+				if jidStr, ok := strings.CutPrefix(tail, "/upload/"); ok {
+					jw.serveUpload(w, r, jawsKey, jidStr)
+					return
+				}
+			}
 		}
 	}
 	w.WriteHeader(http.StatusNotFound)
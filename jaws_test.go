@@ -1593,6 +1593,26 @@ func TestCoverage_GenerateHeadAndConvenienceBroadcasts(t *testing.T) {
 	if msg := nextBroadcast(t, jw); msg.What != what.Insert || msg.Data != "0\n<i>a</i>" {
 		t.Fatalf("unexpected insert msg %#v", msg)
 	}
+	jw.Focus(target)
+	if msg := nextBroadcast(t, jw); msg.What != what.Focus || msg.Dest != target {
+		t.Fatalf("unexpected focus msg %#v", msg)
+	}
+	jw.ScrollIntoView(target, ScrollOptions{Inline: "nearest"})
+	if msg := nextBroadcast(t, jw); msg.What != what.Scroll || msg.Data != `{"inline":"nearest"}` {
+		t.Fatalf("unexpected scroll msg %#v", msg)
+	}
+	jw.Select(target, 0, -1)
+	if msg := nextBroadcast(t, jw); msg.What != what.Select || msg.Data != "0 -1" {
+		t.Fatalf("unexpected select msg %#v", msg)
+	}
+	jw.ShowModal(target)
+	if msg := nextBroadcast(t, jw); msg.What != what.ShowModal {
+		t.Fatalf("unexpected show modal msg %#v", msg)
+	}
+	jw.CloseDialog(target)
+	if msg := nextBroadcast(t, jw); msg.What != what.Close {
+		t.Fatalf("unexpected close msg %#v", msg)
+	}
 	jw.Delete(target)
 	if msg := nextBroadcast(t, jw); msg.What != what.Delete {
 		t.Fatalf("unexpected delete msg %#v", msg)
@@ -0,0 +1,77 @@
+package jaws
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Key identifies a browser keyboard event and its modifier state.
+//
+// The bundled client sends only the keys listed in the Element's
+// data-jawskeydown or data-jawskeyup attribute; see [KeyHandler].
+type Key struct {
+	Key     string // Key is the KeyboardEvent.key value, such as "Enter", "a" or " ".
+	Code    string // Code is the KeyboardEvent.code value, such as "Enter", "KeyA" or "Space".
+	Shift   bool   // Shift reports whether the Shift key was held during the event.
+	Control bool   // Control reports whether the Control key was held during the event.
+	Alt     bool   // Alt reports whether the Alt key was held during the event.
+	Meta    bool   // Meta reports whether the Meta key was held during the event.
+	Repeat  bool   // Repeat reports whether the key is being held down and auto-repeating.
+	Up      bool   // Up reports a key release (KeyUp) rather than a key press (KeyDown).
+}
+
+const (
+	keyStateShift = (1 << iota)
+	keyStateControl
+	keyStateAlt
+	keyStateMeta
+	keyStateRepeat
+	keyStateMask = keyStateShift | keyStateControl | keyStateAlt | keyStateMeta | keyStateRepeat
+)
+
+func (k Key) state() (state int) {
+	if k.Shift {
+		state |= keyStateShift
+	}
+	if k.Control {
+		state |= keyStateControl
+	}
+	if k.Alt {
+		state |= keyStateAlt
+	}
+	if k.Meta {
+		state |= keyStateMeta
+	}
+	if k.Repeat {
+		state |= keyStateRepeat
+	}
+	return
+}
+
+// String formats k for the JaWS wire protocol.
+//
+// The format is the modifier state bitmask, the code and the key, separated by
+// single spaces. The key is last so that it may itself be a space. [Key.Up] is
+// carried by the event kind rather than the data.
+func (k Key) String() string {
+	return strconv.Itoa(k.state()) + " " + k.Code + " " + k.Key
+}
+
+func parseKeyData(value string) (k Key, ok bool) {
+	var stateStr, rest string
+	if stateStr, rest, ok = strings.Cut(value, " "); ok {
+		var state int
+		if state, ok = runAtoi(stateStr); ok && state >= 0 && state&^keyStateMask == 0 {
+			k.Shift = (state & keyStateShift) != 0
+			k.Control = (state & keyStateControl) != 0
+			k.Alt = (state & keyStateAlt) != 0
+			k.Meta = (state & keyStateMeta) != 0
+			k.Repeat = (state & keyStateRepeat) != 0
+			k.Code, k.Key, ok = strings.Cut(rest, " ")
+			ok = ok && k.Key != ""
+		} else {
+			ok = false
+		}
+	}
+	return
+}
@@ -0,0 +1,82 @@
+package jaws
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/linkdata/jaws/lib/what"
+)
+
+func TestParseKeyData(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		want   Key
+		wantOK bool
+	}{
+		{"plain", "0 Enter Enter", Key{Key: "Enter", Code: "Enter"}, true},
+		{"modifiers and repeat", "27 KeyS s", Key{Key: "s", Code: "KeyS", Shift: true, Control: true, Meta: true, Repeat: true}, true},
+		{"space key", "4 Space  ", Key{Key: " ", Code: "Space", Alt: true}, true},
+		{"empty code", "0  a", Key{Key: "a"}, true},
+		{"missing key", "0 KeyA", Key{}, false},
+		{"empty key", "0 KeyA ", Key{}, false},
+		{"bad state", "x KeyA a", Key{}, false},
+		{"unknown state bit", "32 KeyA a", Key{}, false},
+		{"negative state", "-1 KeyA a", Key{}, false},
+		{"empty", "", Key{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseKeyData(tt.in)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok {
+				if got != tt.want {
+					t.Errorf("got %+v, want %+v", got, tt.want)
+				}
+				if s := got.String(); s != tt.in {
+					t.Errorf("String() = %q, want %q", s, tt.in)
+				}
+			}
+		})
+	}
+}
+
+type testKeyHandler struct {
+	keys []Key
+	err  error
+}
+
+func (h *testKeyHandler) JawsKey(elem *Element, key Key) error {
+	h.keys = append(h.keys, key)
+	return h.err
+}
+
+func TestRequest_KeyEvents(t *testing.T) {
+	rq := newTestRequest(t)
+	defer rq.Close()
+	h := &testKeyHandler{}
+	elem := rq.NewElement(testDivWidget{inner: "x"})
+	elem.AddHandlers(h)
+	elem.Freeze()
+	if err := rq.callAllEventHandlers(elem.Jid(), what.KeyDown, "2 KeyZ z"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rq.callAllEventHandlers(elem.Jid(), what.KeyUp, "0 Escape Escape"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CallEventHandlers(elem.UI(), elem, what.KeyDown, "garbage"); !errors.Is(err, ErrEventUnhandled) {
+		t.Errorf("malformed key data: %v", err)
+	}
+	want := []Key{{Key: "z", Code: "KeyZ", Control: true}, {Key: "Escape", Code: "Escape", Up: true}}
+	if len(h.keys) != len(want) || h.keys[0] != want[0] || h.keys[1] != want[1] {
+		t.Errorf("got %+v, want %+v", h.keys, want)
+	}
+	if err := CallEventHandlers(elem.UI(), elem, what.Click, "1 2 0 x"); !errors.Is(err, ErrEventUnhandled) {
+		t.Errorf("key handler handled a click: %v", err)
+	}
+	if _, handlers, _ := ParseParams([]any{h}); len(handlers) != 1 {
+		t.Error("ParseParams did not recognize a KeyHandler")
+	}
+}
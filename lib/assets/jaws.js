@@ -13,6 +13,14 @@
 var jaws = null;
 var jawsIdPrefix = 'Jid.';
 var jawsDebug = false;
+// The child being dragged within a data-jawssortable container, and the
+// container's child order when the drag started.
+var jawsDragging = null;
+var jawsDragOrder = '';
+// Number of orders received from the server, sent when resuming.
+var jawsSeq = 0;
+// Milliseconds the server keeps a disconnected request resumable; 0 disables resume.
+var jawsResumeWindow = 0;
 const jawsJidRx = /^[1-9]\d*$/;
 const jawsMaxJid = '9223372036854775807';
 // Milliseconds after WebSocket failure before reconnect probing begins.
@@ -21,6 +29,39 @@ const jawsFailureGracePeriod = 5 * 1000;
 const jawsReconnectTimeout = 10 * 1000;
 // Minimum navigation age for a reconnect-triggered page reload.
 const jawsReconnectReloadMinPageAge = 60 * 1000;
+// Milliseconds between attempts to resume a lost WebSocket.
+const jawsResumeRetryDelay = 1000;
+// WebSocket close code the server uses when a request cannot be resumed.
+const jawsResumeRejected = 4000;
+// Default milliseconds jawsRPC waits for the server reply.
+const jawsRPCTimeout = 30 * 1000;
+// Last jawsRPC call id, and the settle functions of calls awaiting a Reply.
+var jawsRPCSeq = 0;
+var jawsRPCPending = new Map();
+// Milliseconds to wait for more environment changes before reporting them.
+const jawsClientDelay = 250;
+// The last environment report sent, and the timer of a pending one.
+var jawsClientSent = '';
+var jawsClientTimer = null;
+// Inputs with a data-jawsinput debounce or throttle policy and their pending
+// send, as { timer, dirty }; dirty is set while an edit is held back.
+var jawsInputHeld = new WeakMap();
+// Elements marked busy by data-jawsbusy while the server handles their events,
+// by Jid, as { elem, count, disabled }.
+var jawsBusy = new Map();
+// jawsNavPending holds the fetched page while the server is asked to hand the
+// WebSocket over to its Request.
+var jawsNavPending = null;
+// jawsToasts maps the ids of shown toasts to their element and timer.
+var jawsToasts = new Map();
+// jawsToastLocalSeq numbers toasts the server does not track.
+var jawsToastLocalSeq = 0;
+// Functions returning the client values readable with Request.Query, by name.
+var jawsQueries = new Map([
+	['viewport', function () { return { width: window.innerWidth, height: window.innerHeight }; }],
+	['scroll', function () { return { x: window.scrollX, y: window.scrollY }; }],
+	['selection', function () { return window.getSelection ? String(window.getSelection()) : ''; }],
+]);
 
 function jawsIsJid(v) {
 	if (typeof v === 'string' && v.startsWith(jawsIdPrefix)) {
@@ -53,7 +94,7 @@ function jawsIsTrue(v) {
 }
 
 function jawsCanSend() {
-	return jaws instanceof WebSocket && jaws.readyState === 1;
+	return jaws instanceof WebSocket && jaws.readyState === 1 && jawsNavPending === null;
 }
 
 function jawsShouldSet(currentValue, newValue) {
@@ -111,8 +152,64 @@ function jawsBuildClickData(elem, e) {
 	return val;
 }
 
+// jawsClickTarget returns the Element the server resolves a click on elem to.
+function jawsClickTarget(elem) {
+	while (elem != null && !(jawsIsJid(String(elem.id || "")) && !jawsIsInputTag(elem.tagName))) {
+		elem = elem.parentElement || null;
+	}
+	return elem;
+}
+
 function jawsSendClickLike(what, e) {
-	jaws.send(what + "\t\t" + JSON.stringify(jawsBuildClickData(e.target, e)) + "\n");
+	if (jawsBusyStart(jawsClickTarget(e.target))) {
+		jaws.send(what + "\t\t" + JSON.stringify(jawsBuildClickData(e.target, e)) + "\n");
+	}
+}
+
+// jawsBusyStart marks elem busy if it carries data-jawsbusy, until the server
+// acknowledges the event about to be sent for it. It returns false if the
+// event must be dropped because elem is already busy and its policy is "drop"
+// or "disable".
+function jawsBusyStart(elem) {
+	if (elem == null || typeof elem.hasAttribute !== 'function' || !elem.hasAttribute('data-jawsbusy')) {
+		return true;
+	}
+	const policy = elem.getAttribute('data-jawsbusy');
+	let busy = jawsBusy.get(elem.id);
+	if (busy !== undefined) {
+		if (policy === 'drop' || policy === 'disable') {
+			return false;
+		}
+		busy.count++;
+		return true;
+	}
+	busy = { elem: elem, count: 1, disabled: false };
+	if (policy === 'disable' && !elem.disabled) {
+		elem.disabled = true;
+		busy.disabled = true;
+	}
+	elem.setAttribute('aria-busy', 'true');
+	elem.classList.add('jaws-busy');
+	jawsBusy.set(elem.id, busy);
+	return true;
+}
+
+// jawsBusyEnd handles an Ack for the Element with the given id, clearing its
+// busy mark once all of its events are acknowledged. If all is true, every
+// busy mark is cleared, as no Acks can arrive for a lost connection.
+function jawsBusyEnd(id, all) {
+	for (const [busyId, busy] of jawsBusy) {
+		if (all || busyId === id) {
+			if (all || --busy.count <= 0) {
+				jawsBusy.delete(busyId);
+				busy.elem.removeAttribute('aria-busy');
+				busy.elem.classList.remove('jaws-busy');
+				if (busy.disabled) {
+					busy.elem.disabled = false;
+				}
+			}
+		}
+	}
 }
 
 function jawsClickHandler(e) {
@@ -147,18 +244,172 @@ function jawsContextMenuHandler(e) {
 	}
 }
 
-function jawsInputHandler(e) {
+function jawsKeyMatches(list, e) {
+	for (const item of String(list).split(/\s+/)) {
+		const parts = item.split('+');
+		const want = parts.pop();
+		if (want === '' || (want !== e.key && want !== e.code)) {
+			continue;
+		}
+		if (parts.every(mod => {
+			switch (mod.toLowerCase()) {
+				case 'shift': return e.shiftKey;
+				case 'control': case 'ctrl': return e.ctrlKey;
+				case 'alt': return e.altKey;
+				case 'meta': return e.metaKey;
+			}
+			return false;
+		})) {
+			return true;
+		}
+	}
+	return false;
+}
+
+function jawsBuildKeyData(e) {
+	const state = jawsGetKeyState(e) + (e.metaKey ? 8 : 0) + (e.repeat ? 16 : 0);
+	return state + " " + String(e.code || "").replaceAll(/\s/g, '') + " " + String(e.key || "");
+}
+
+function jawsKeyHandler(e) {
 	if (jawsCanSend() && e instanceof Event) {
-		let val;
 		const elem = e.currentTarget;
-		if (!jawsIsJid(elem.id)) {
+		const up = e.type === 'keyup';
+		const list = elem.getAttribute(up ? 'data-jawskeyup' : 'data-jawskeydown');
+		if (!jawsIsJid(elem.id) || list == null || !jawsKeyMatches(list, e)) {
 			return;
 		}
 		e.stopPropagation();
+		e.preventDefault();
+		if (!jawsBusyStart(elem)) {
+			return;
+		}
+		jaws.send((up ? "KeyUp" : "KeyDown") + "\t" + elem.id + "\t" + JSON.stringify(jawsBuildKeyData(e)) + "\n");
+	}
+}
+
+// jawsDOMEventData returns the JSON object describing e sent in a DOMEvent.
+function jawsDOMEventData(elem, e) {
+	const data = { type: e.type };
+	const point = (e.changedTouches && e.changedTouches[0]) || e;
+	if (typeof point.clientX === 'number') {
+		data.x = point.clientX;
+		data.y = point.clientY;
+	}
+	if (typeof e.buttons === 'number') {
+		data.buttons = e.buttons;
+	}
+	if (typeof e.deltaX === 'number') {
+		data.deltaX = e.deltaX;
+		data.deltaY = e.deltaY;
+	}
+	if (e.pointerType) {
+		data.pointerType = String(e.pointerType);
+	}
+	if (e.touches) {
+		data.touches = e.touches.length;
+	}
+	data.shift = !!e.shiftKey;
+	data.control = !!e.ctrlKey;
+	data.alt = !!e.altKey;
+	data.meta = !!e.metaKey;
+	if (e.type === 'scroll') {
+		data.scrollLeft = elem.scrollLeft;
+		data.scrollTop = elem.scrollTop;
+		data.clientWidth = elem.clientWidth;
+		data.clientHeight = elem.clientHeight;
+	}
+	if (e.type === 'submit' && typeof FormData === 'function') {
+		const params = new URLSearchParams();
+		for (const [name, value] of new FormData(elem)) {
+			if (typeof value === 'string') {
+				params.append(name, value);
+			}
+		}
+		data.value = params.toString();
+	}
+	return data;
+}
+
+// jawsDOMEventListener returns a listener forwarding events to the server as
+// DOMEvent messages, rate limited by mode ('throttle' or 'debounce') to one
+// every ms milliseconds. Only the latest of the events held back is sent.
+function jawsDOMEventListener(mode, ms) {
+	let timer = null;
+	let pending = null;
+	const send = function (msg) {
+		if (jawsCanSend() && jawsBusyStart(msg.elem)) {
+			jaws.send("DOMEvent\t" + msg.elem.id + "\t" + msg.data + "\n");
+		}
+	};
+	const flush = function () {
+		timer = null;
+		if (pending !== null) {
+			send(pending);
+			pending = null;
+			if (mode === 'throttle') {
+				timer = setTimeout(flush, ms);
+			}
+		}
+	};
+	return function (e) {
+		const elem = e.currentTarget;
+		if (!(e instanceof Event) || !elem || !jawsIsJid(elem.id)) {
+			return;
+		}
+		if (e.type === 'submit') {
+			e.preventDefault();
+		}
+		const msg = { elem: elem, data: JSON.stringify(JSON.stringify(jawsDOMEventData(elem, e))) };
+		if (!(ms > 0)) {
+			send(msg);
+		} else if (mode === 'debounce') {
+			clearTimeout(timer);
+			pending = msg;
+			timer = setTimeout(flush, ms);
+		} else if (timer === null) {
+			send(msg);
+			timer = setTimeout(flush, ms);
+		} else {
+			pending = msg;
+		}
+	};
+}
+
+// jawsAttachDOMEvents adds listeners for the events listed in elem's
+// data-jawsevents attribute, as "type", "type:throttle=MS" or "type:debounce=MS".
+function jawsAttachDOMEvents(elem) {
+	const list = String(elem.getAttribute('data-jawsevents') || '');
+	for (const spec of list.split(/\s+/)) {
+		const m = /^([a-z]+)(?::(throttle|debounce)=(\d+))?$/.exec(spec);
+		if (m) {
+			elem.addEventListener(m[1], jawsDOMEventListener(m[2], Number(m[3])), { passive: m[1] !== 'submit' });
+		}
+	}
+}
+
+// jawsVirtualReport makes a data-jawsvirtual Element forward a scroll event,
+// reporting its scroll position and size so the server can fill its window.
+function jawsVirtualReport(elem) {
+	if (jawsCanSend() && typeof elem.dispatchEvent === 'function') {
+		elem.dispatchEvent(new Event('scroll'));
+	}
+}
+
+function jawsVirtualReportAll() {
+	document.querySelectorAll('[data-jawsvirtual]').forEach(jawsVirtualReport);
+}
+
+function jawsSendInput(elem) {
+	if (jawsCanSend() && jawsIsJid(elem.id) && jawsBusyStart(elem)) {
+		let val;
 		if (jawsIsCheckable(elem.getAttribute('type'))) {
 			val = elem.checked;
 		} else if (elem.tagName.toLowerCase() === 'option') {
 			val = elem.selected;
+		} else if (elem.tagName.toLowerCase() === 'select' && elem.multiple) {
+			// A multiple select sends the values of all selected options.
+			val = JSON.stringify(Array.from(elem.selectedOptions, opt => opt.value));
 		} else {
 			val = elem.value;
 		}
@@ -166,6 +417,168 @@ function jawsInputHandler(e) {
 	}
 }
 
+// jawsInputRelease ends the hold on elem, sending its value if an edit was held
+// back. A throttled input then holds for another ms milliseconds.
+function jawsInputRelease(elem, ms, throttle) {
+	const held = jawsInputHeld.get(elem);
+	if (held) {
+		clearTimeout(held.timer);
+		jawsInputHeld.delete(elem);
+		if (held.dirty) {
+			jawsSendInput(elem);
+			if (throttle) {
+				jawsInputHeld.set(elem, { dirty: false, timer: setTimeout(function () { jawsInputRelease(elem, ms, true); }, ms) });
+			}
+		}
+	}
+}
+
+// jawsInputHandler sends the value of an edited input, honoring a data-jawsinput
+// policy of "debounce=MS" (send once edits pause for MS milliseconds) or
+// "throttle=MS" (send at most once every MS milliseconds). Held back edits are
+// sent at once on the change event, when the user commits the value.
+function jawsInputHandler(e) {
+	if (jawsCanSend() && e instanceof Event) {
+		const elem = e.currentTarget;
+		if (!jawsIsJid(elem.id)) {
+			return;
+		}
+		e.stopPropagation();
+		const policy = /^(debounce|throttle)=(\d+)$/.exec(String(elem.getAttribute('data-jawsinput') || '').trim());
+		const ms = policy ? Number(policy[2]) : 0;
+		if (!(ms > 0)) {
+			jawsSendInput(elem);
+			return;
+		}
+		const throttle = policy[1] === 'throttle';
+		let held = jawsInputHeld.get(elem);
+		if (e.type === 'change') {
+			if (held && held.dirty) {
+				jawsInputRelease(elem, ms, false);
+			}
+		} else if (throttle && held) {
+			held.dirty = true;
+		} else {
+			if (held) {
+				clearTimeout(held.timer);
+			} else if (throttle) {
+				jawsSendInput(elem);
+			}
+			held = { dirty: !throttle };
+			held.timer = setTimeout(function () { jawsInputRelease(elem, ms, throttle); }, ms);
+			jawsInputHeld.set(elem, held);
+		}
+	}
+}
+
+// jawsSortableChild returns the managed direct child of container holding node.
+function jawsSortableChild(container, node) {
+	while (node && node.parentElement !== container) {
+		node = node.parentElement;
+	}
+	return node && jawsIsJid(node.id) ? node : null;
+}
+
+function jawsSortableOrder(container) {
+	return Array.from(container.children).filter(child => jawsIsJid(child.id)).map(child => child.id).join(' ');
+}
+
+// jawsSortableHandler moves a dragged child of a data-jawssortable container
+// past the siblings it is dragged over, and sends the new order when the drag
+// ends. The server answers with the order it accepted. Children are made
+// draggable when pressed, so those appended later need no attaching, and
+// pressing inside a form control leaves its text selection alone.
+function jawsSortableHandler(e) {
+	const container = e.currentTarget;
+	switch (e.type) {
+		case 'pointerdown':
+			const child = jawsSortableChild(container, e.target);
+			if (child !== null) {
+				child.draggable = !jawsIsInputOrigin(e.target);
+			}
+			return;
+		case 'dragstart':
+			jawsDragging = jawsSortableChild(container, e.target);
+			if (jawsDragging !== null) {
+				jawsDragOrder = jawsSortableOrder(container);
+				e.stopPropagation();
+				if (e.dataTransfer) {
+					e.dataTransfer.effectAllowed = 'move';
+					e.dataTransfer.setData('text/plain', jawsDragging.id);
+				}
+			}
+			return;
+		case 'dragover':
+			if (jawsDragging !== null && jawsDragging.parentElement === container) {
+				e.preventDefault();
+				const over = jawsSortableChild(container, e.target);
+				if (over !== null && over !== jawsDragging) {
+					const children = Array.from(container.children);
+					if (children.indexOf(jawsDragging) < children.indexOf(over)) {
+						container.insertBefore(jawsDragging, over.nextSibling);
+					} else {
+						container.insertBefore(jawsDragging, over);
+					}
+				}
+			}
+			return;
+		case 'drop':
+			if (jawsDragging !== null && jawsDragging.parentElement === container) {
+				e.preventDefault();
+			}
+			return;
+		case 'dragend':
+			if (jawsDragging !== null && jawsDragging.parentElement === container) {
+				const order = jawsSortableOrder(container);
+				if (order !== jawsDragOrder) {
+					if (jawsCanSend() && jawsBusyStart(container)) {
+						jaws.send("Reorder\t" + container.id + "\t" + JSON.stringify(order) + "\n");
+					} else {
+						jawsOrder(jawsDragOrder);
+					}
+				}
+			}
+			jawsDragging = null;
+			jawsDragOrder = '';
+	}
+}
+
+function jawsUploadURL(elem, file) {
+	return '/jaws/' + encodeURIComponent(document.querySelector('meta[name="jawsKey"]').content) +
+		'/upload/' + encodeURIComponent(elem.id) + '?name=' + encodeURIComponent(file.name);
+}
+
+// jawsUploadNext posts files[i] from the file input elem, and the files after it
+// once the server accepts it. The selection is cleared when done, so choosing
+// the same file again uploads it again.
+function jawsUploadNext(elem, files, i) {
+	if (i >= files.length) {
+		elem.value = '';
+		return;
+	}
+	const req = new XMLHttpRequest();
+	req.open("POST", jawsUploadURL(elem, files[i]), true);
+	req.addEventListener('loadend', function () {
+		if (req.status >= 200 && req.status < 300) {
+			jawsUploadNext(elem, files, i + 1);
+		} else {
+			elem.value = '';
+		}
+	}, { once: true });
+	req.send(files[i]);
+}
+
+function jawsUploadHandler(e) {
+	if (jawsCanSend() && e instanceof Event) {
+		const elem = e.currentTarget;
+		if (!jawsIsJid(elem.id)) {
+			return;
+		}
+		e.stopPropagation();
+		jawsUploadNext(elem, Array.from(elem.files || []), 0);
+	}
+}
+
 function jawsForgetName(elem) {
 	const name = elem.dataset && elem.dataset.jawsname;
 	if (!name) {
@@ -243,10 +656,32 @@ function jawsAttach(elem) {
 		}
 		return;
 	}
+	if (elem.hasAttribute('data-jawssortable')) {
+		['pointerdown', 'dragstart', 'dragover', 'drop', 'dragend'].forEach(name => elem.addEventListener(name, jawsSortableHandler, false));
+	}
+	if (elem.hasAttribute('data-jawskeydown')) {
+		elem.addEventListener('keydown', jawsKeyHandler, false);
+	}
+	if (elem.hasAttribute('data-jawskeyup')) {
+		elem.addEventListener('keyup', jawsKeyHandler, false);
+	}
+	if (elem.hasAttribute('data-jawsevents')) {
+		jawsAttachDOMEvents(elem);
+	}
+	if (elem.hasAttribute('data-jawsvirtual')) {
+		jawsVirtualReport(elem);
+	}
+	if (elem.hasAttribute('data-jawsupload')) {
+		elem.addEventListener('change', jawsUploadHandler, false);
+		return;
+	}
 	if (jawsIsInputTag(elem.tagName)) {
+		const policy = elem.hasAttribute('data-jawsinput') ? String(elem.getAttribute('data-jawsinput')).trim() : '';
 		let eventName = 'input';
-		if (String(elem.type).toLowerCase() === "number" && elem.hasAttribute("data-jawsnumber")) {
+		if (policy === 'change' || (String(elem.type).toLowerCase() === "number" && elem.hasAttribute("data-jawsnumber"))) {
 			eventName = 'change';
+		} else if (policy !== '') {
+			elem.addEventListener('change', jawsInputHandler, false);
 		}
 		elem.addEventListener(eventName, jawsInputHandler, false);
 		return;
@@ -277,7 +712,98 @@ function jawsAlert(data) {
 			return;
 		}
 	}
-	console.log("jaws: " + type + ": " + message);
+	jawsToast({ id: 0, level: type, body: message });
+}
+
+function jawsToastContainer() {
+	let container = document.querySelector('[data-jaws-toasts]');
+	if (container === null) {
+		container = document.createElement('div');
+		container.setAttribute('data-jaws-toasts', '');
+		container.className = typeof bootstrap !== 'undefined' ? 'toast-container position-fixed top-0 end-0 p-3' : 'jaws-toasts';
+		document.body.appendChild(container);
+	}
+	return container;
+}
+
+function jawsToastButton(cls, bsCls, label, fn) {
+	const button = document.createElement('button');
+	button.type = 'button';
+	button.className = cls + bsCls;
+	button.textContent = label;
+	button.addEventListener('click', fn);
+	return button;
+}
+
+// jawsToast shows the toast t, or replaces the content of the shown toast with
+// the same id. A zero id is a toast the server does not track, replacing the
+// shown one with the same key if any.
+function jawsToast(t) {
+	const bs = typeof bootstrap !== 'undefined';
+	if (!t.id) {
+		t.id = t.key ? 'key:' + t.key : 'local:' + (++jawsToastLocalSeq);
+	}
+	let toast = jawsToasts.get(t.id);
+	if (toast === undefined) {
+		toast = { elem: document.createElement('div'), timer: null };
+		jawsToasts.set(t.id, toast);
+		jawsToastContainer().appendChild(toast.elem);
+	} else {
+		clearTimeout(toast.timer);
+		toast.timer = null;
+	}
+	const urgent = t.level === 'danger' || t.level === 'warning';
+	const elem = toast.elem;
+	elem.className = 'jaws-toast jaws-toast-' + t.level + (bs ? ' toast show text-bg-' + t.level : '');
+	elem.setAttribute('role', urgent ? 'alert' : 'status');
+	elem.setAttribute('aria-live', urgent ? 'assertive' : 'polite');
+	elem.setAttribute('aria-atomic', 'true');
+	const header = document.createElement('div');
+	header.className = 'jaws-toast-header' + (bs ? ' toast-header' : '');
+	if (t.title) {
+		const title = document.createElement('strong');
+		title.className = 'jaws-toast-title' + (bs ? ' me-auto' : '');
+		title.textContent = t.title;
+		header.appendChild(title);
+	}
+	const close = jawsToastButton('jaws-toast-close', bs ? ' btn-close' : '', bs ? '' : '\u00d7', () => jawsToastClose(t.id, false));
+	close.setAttribute('aria-label', 'Close');
+	header.appendChild(close);
+	const body = document.createElement('div');
+	body.className = 'jaws-toast-body' + (bs ? ' toast-body' : '');
+	body.innerHTML = t.body || '';
+	if (t.action) {
+		const action = jawsToastButton('jaws-toast-action', bs ? ' btn btn-sm btn-light mt-2 d-block' : '', t.action, () => jawsToastClose(t.id, true));
+		body.appendChild(action);
+	}
+	elem.replaceChildren(header, body);
+	if (t.timeout > 0) {
+		toast.timer = setTimeout(() => jawsToastClose(t.id, false), t.timeout);
+	}
+}
+
+// jawsToastClose removes the toast with the given id and reports it dismissed,
+// by its action button if action is true.
+function jawsToastClose(id, action) {
+	const toast = jawsToasts.get(id);
+	if (toast !== undefined) {
+		jawsToasts.delete(id);
+		clearTimeout(toast.timer);
+		toast.elem.remove();
+		if (typeof id === 'number' && jawsCanSend()) {
+			jaws.send("Dismiss\t\t" + JSON.stringify(JSON.stringify({ id: id, action: action })) + "\n");
+		}
+	}
+}
+
+// jawsToastsClear removes all toasts without reporting them, as their ids
+// belong to a Request that is gone.
+function jawsToastsClear() {
+	for (const toast of jawsToasts.values()) {
+		clearTimeout(toast.timer);
+		toast.elem.remove();
+	}
+	jawsToasts.clear();
 }
 
 function jawsList(idlist) {
@@ -320,6 +846,16 @@ function jawsSetValue(elem, str) {
 		}
 		return;
 	}
+	if (tagName === 'select' && elem.multiple) {
+		const values = JSON.parse(str);
+		for (const opt of elem.options) {
+			const selected = values.includes(opt.value);
+			if (opt.selected !== selected) {
+				opt.selected = selected;
+			}
+		}
+		return;
+	}
 	if (elem.value === str) {
 		return;
 	}
@@ -407,8 +943,54 @@ function jawsReconnect() {
 function jawsFailed() {
 	if (jaws instanceof WebSocket) {
 		jaws = new Date();
-		setTimeout(jawsReconnect, jawsFailureGracePeriod);
+		jawsBusyEnd('', true);
+		if (jawsResumeWindow > 0) {
+			jawsResume(jaws);
+		} else {
+			setTimeout(jawsReconnect, jawsFailureGracePeriod);
+		}
+	}
+}
+
+function jawsResumed(ws) {
+	jaws = ws;
+	ws.addEventListener('message', jawsMessage);
+	ws.addEventListener('close', jawsFailed);
+	ws.addEventListener('error', jawsFailed);
+	jawsClientChanged();
+	const elem = document.querySelector('[data-jaws-lost]');
+	if (elem !== null) {
+		elem.remove();
+	}
+}
+
+// jawsResume reconnects the request lost at lostAt, asking the server to replay
+// the orders after the first jawsSeq. It retries until the resume window has
+// passed or the server rejects the resume, then falls back to reloading.
+function jawsResume(lostAt) {
+	if (jaws !== lostAt) {
+		return;
 	}
+	const ws = new WebSocket(jawsSocketURL() + '?resume=' + jawsSeq);
+	let opened = false;
+	ws.addEventListener('open', function () {
+		opened = true;
+		if (jaws === lostAt) {
+			jawsResumed(ws);
+		} else {
+			ws.close();
+		}
+	});
+	ws.addEventListener('close', function (e) {
+		if (opened || jaws !== lostAt) {
+			return;
+		}
+		if (e.code !== jawsResumeRejected && Date.now() - lostAt < jawsResumeWindow) {
+			setTimeout(jawsResume, jawsResumeRetryDelay, lostAt);
+		} else {
+			jawsReconnect();
+		}
+	});
 }
 
 function jawsUnloading() {
@@ -483,6 +1065,7 @@ function jawsMessage(e) {
 	const orders = e.data.split('\n');
 	for (let i = 0; i < orders.length; i++) {
 		if (orders[i]) {
+			jawsSeq++;
 			const parts = orders[i].split('\t');
 			// Isolate each order: the server batches independent element updates
 			// into one frame, so a single failing order (e.g. targeting an element
@@ -496,6 +1079,82 @@ function jawsMessage(e) {
 	}
 }
 
+// jawsRPC calls the Go function registered as name with HandleRPC, passing
+// args as JSON. It returns a Promise resolved with the function result, or
+// rejected with an Error if the function fails, the call cannot be sent or no
+// reply arrives within timeout milliseconds (default jawsRPCTimeout).
+function jawsRPC(name, args, timeout) {
+	return new Promise(function (resolve, reject) {
+		if (typeof name !== 'string' || name === '' || /\s/.test(name)) {
+			reject(new Error("jaws: invalid RPC name: " + name));
+			return;
+		}
+		if (!jawsCanSend()) {
+			reject(new Error("jaws: not connected"));
+			return;
+		}
+		const id = ++jawsRPCSeq;
+		const timer = setTimeout(function () {
+			if (jawsRPCPending.delete(id)) {
+				reject(new Error("jaws: RPC timeout: " + name));
+			}
+		}, timeout === undefined ? jawsRPCTimeout : timeout);
+		jawsRPCPending.set(id, { resolve: resolve, reject: reject, timer: timer });
+		const json = args === undefined ? 'null' : JSON.stringify(args);
+		jaws.send("RPC\t\t" + JSON.stringify(id + " " + name + " " + json) + "\n");
+	});
+}
+
+function jawsReply(reply) {
+	const pending = jawsRPCPending.get(reply.id);
+	if (pending !== undefined) {
+		jawsRPCPending.delete(reply.id);
+		clearTimeout(pending.timer);
+		if (Object.hasOwn(reply, 'error')) {
+			pending.reject(new Error(reply.error));
+		} else {
+			pending.resolve(reply.result);
+		}
+	}
+}
+
+// jawsRegisterQuery makes the value returned by fn, or the value of the Promise
+// it returns, readable as name with Request.Query.
+function jawsRegisterQuery(name, fn) {
+	jawsQueries.set(name, fn);
+}
+
+function jawsAnswer(q) {
+	switch (q.kind) {
+		case 'confirm':
+			return window.confirm(q.message);
+		case 'prompt':
+			return window.prompt(q.message, q.value || '');
+		case 'query':
+			const fn = jawsQueries.get(q.name);
+			if (typeof fn === 'function') {
+				return fn();
+			}
+			throw "jaws: unknown query: " + q.name;
+	}
+	throw "jaws: unknown question: " + q.kind;
+}
+
+// jawsAsk answers a question from the server with an Answer event.
+function jawsAsk(q) {
+	new Promise(function (resolve) {
+		resolve(jawsAnswer(q));
+	}).then(function (result) {
+		return { id: q.id, result: result === undefined ? null : result };
+	}, function (err) {
+		return { id: q.id, error: String(err && err.message ? err.message : err) };
+	}).then(function (answer) {
+		if (jawsCanSend()) {
+			jaws.send("Answer\t\t" + JSON.stringify(JSON.stringify(answer)) + "\n");
+		}
+	});
+}
+
 function jawsWarnSameHTML(id, operation, effect) {
 	if (jawsDebug) {
 		console.warn("jaws: " + operation + " " + id + ": requested HTML matches the current serialized HTML; " + effect);
@@ -556,6 +1215,32 @@ function jawsVar(name, data, operation) {
 	}
 }
 
+// jawsDownload saves the file at url, which the server sends as an attachment.
+function jawsDownload(url) {
+	const a = document.createElement('a');
+	a.href = url;
+	a.download = '';
+	a.style.display = 'none';
+	document.body.appendChild(a);
+	a.click();
+	a.remove();
+}
+
+// jawsSelect focuses elem and selects its text from the start offset up to the
+// end offset in data, or to the end of the text if end is negative.
+function jawsSelect(elem, data) {
+	const offsets = data.split(' ');
+	const start = Number(offsets[0]);
+	let end = Number(offsets[1]);
+	elem.focus();
+	if (typeof elem.setSelectionRange === 'function') {
+		if (end < 0) {
+			end = String(elem.value).length;
+		}
+		elem.setSelectionRange(start, end);
+	}
+}
+
 function jawsPerform(what, id, data) {
 	let path = "";
 	if (what === 'Set' || what === 'Call') {
@@ -579,6 +1264,24 @@ function jawsPerform(what, id, data) {
 		case 'Order':
 			jawsOrder(data);
 			return;
+		case 'Reply':
+			jawsReply(JSON.parse(data));
+			return;
+		case 'Ask':
+			jawsAsk(JSON.parse(data));
+			return;
+		case 'Navigate':
+			jawsNavigated(data);
+			return;
+		case 'Download':
+			jawsDownload(data);
+			return;
+		case 'Toast':
+			jawsToast(JSON.parse(data));
+			return;
+		case 'Clipboard':
+			navigator.clipboard.writeText(data).catch(err => console.error("jaws: clipboard: " + err));
+			return;
 	}
 	if (what === 'Call' && id === '') {
 		jawsVar(path, data, what);
@@ -587,6 +1290,11 @@ function jawsPerform(what, id, data) {
 	if (!jawsIsJid(id)) {
 		throw "jaws: invalid Jid: " + id;
 	}
+	if (what === 'Ack') {
+		// The Element may be gone; its busy mark is still dropped.
+		jawsBusyEnd(id, false);
+		return;
+	}
 	const elem = document.getElementById(id);
 	if (elem === null) {
 		throw "jaws: element not found: " + id;
@@ -603,7 +1311,28 @@ function jawsPerform(what, id, data) {
 			}
 			return;
 		case 'Value':
-			jawsSetValue(elem, data);
+			// An edit held back by a data-jawsinput policy wins; the server
+			// reconciles once it is sent.
+			if (!(jawsInputHeld.get(elem) || {}).dirty) {
+				jawsSetValue(elem, data);
+			}
+			return;
+		case 'Focus':
+			elem.focus();
+			return;
+		case 'Scroll':
+			elem.scrollIntoView(JSON.parse(data));
+			return;
+		case 'Select':
+			jawsSelect(elem, data);
+			return;
+		case 'ShowModal':
+			if (!elem.open) {
+				elem.showModal();
+			}
+			return;
+		case 'Close':
+			elem.close();
 			return;
 		case 'Append':
 			elem.appendChild(jawsAttachChildren(jawsElement(data)));
@@ -664,23 +1393,168 @@ function jawsPerform(what, id, data) {
 	throw "jaws: unknown operation: " + what;
 }
 
+// jawsClientInfo returns the browser environment reported as ClientInfo.
+function jawsClientInfo() {
+	const info = {
+		width: window.innerWidth || 0,
+		height: window.innerHeight || 0,
+		pixelRatio: window.devicePixelRatio || 0,
+		hidden: document.visibilityState === 'hidden',
+	};
+	if (typeof Intl !== 'undefined') {
+		info.timeZone = Intl.DateTimeFormat().resolvedOptions().timeZone;
+	}
+	if (typeof navigator !== 'undefined') {
+		info.languages = Array.from(navigator.languages || [navigator.language]);
+	}
+	if (typeof window.matchMedia === 'function') {
+		info.colorScheme = window.matchMedia('(prefers-color-scheme: dark)').matches ? 'dark' : 'light';
+	}
+	return JSON.stringify(info);
+}
+
+function jawsSendClientInfo() {
+	jawsClientTimer = null;
+	if (jawsCanSend()) {
+		const info = jawsClientInfo();
+		if (info !== jawsClientSent) {
+			jawsClientSent = info;
+			jaws.send("Client\t\t" + JSON.stringify(info) + "\n");
+		}
+	}
+}
+
+function jawsClientChanged() {
+	if (jawsClientTimer === null) {
+		jawsClientTimer = setTimeout(jawsSendClientInfo, jawsClientDelay);
+	}
+}
+
 function jawsPageshow(e) {
 	if (e.persisted) {
 		window.location.reload();
 	}
 }
 
-function jawsConnect() {
-	if (document.querySelector('meta[name="jawsDebug"]') !== null) {
-		jawsDebug = true;
+// jawsNavigate fetches the page at url and asks the server to hand the
+// WebSocket over to its Request, falling back to loading the page normally.
+// If push is true, url is added to the session history once navigated.
+function jawsNavigate(url, push) {
+	if (!jawsCanSend()) {
+		window.location.assign(url);
+		return;
 	}
+	jawsNavPending = { key: '', doc: null, url: url, push: push };
+	const pending = jawsNavPending;
+	fetch(url, { credentials: 'same-origin', headers: { 'Accept': 'text/html' } }).then(resp => {
+		const ctype = resp.headers.get('Content-Type') || '';
+		if (!resp.ok || resp.redirected || !ctype.startsWith('text/html')) {
+			throw new Error(resp.status + ' ' + ctype);
+		}
+		return resp.text();
+	}).then(html => {
+		const doc = new DOMParser().parseFromString(html, 'text/html');
+		const meta = doc.querySelector('meta[name="jawsKey"]');
+		if (meta === null || !meta.content || jawsNavPending !== pending || !(jaws instanceof WebSocket) || jaws.readyState !== 1) {
+			throw new Error('not navigable');
+		}
+		pending.key = meta.content;
+		pending.doc = doc;
+		jaws.send("Navigate\t\t" + JSON.stringify(pending.key) + "\n");
+	}).catch(err => {
+		if (jawsNavPending === pending) {
+			jawsNavPending = null;
+			if (jawsDebug) {
+				console.info("jaws: navigate " + url + ": " + err);
+			}
+			window.location.assign(url);
+		}
+	});
+}
+
+// jawsNavigated handles the server reply to a Navigate request. If the server
+// handed the WebSocket over to the Request with key, the fetched page replaces
+// the current one, otherwise the page is loaded normally.
+function jawsNavigated(key) {
+	const pending = jawsNavPending;
+	jawsNavPending = null;
+	if (pending === null) {
+		return;
+	}
+	if (key === '' || key !== pending.key) {
+		window.location.assign(pending.url);
+		return;
+	}
+	document.querySelector('meta[name="jawsKey"]').content = key;
+	document.title = pending.doc.title;
+	jawsBusyEnd('', true);
+	jawsToastsClear();
+	window.jawsNames = new Map();
+	document.body.replaceWith(document.adoptNode(pending.doc.body));
+	jawsAttachChildren(document.body);
+	if (pending.push) {
+		if (!(history.state && history.state.jawsNav)) {
+			history.replaceState({ jawsNav: true }, '');
+		}
+		history.pushState({ jawsNav: true }, '', pending.url);
+	}
+	window.scrollTo(0, 0);
+	jawsSeq = 0;
+}
+
+// jawsNavClick follows same-origin links inside data-jawsnav Elements with
+// jawsNavigate.
+function jawsNavClick(e) {
+	if (e.defaultPrevented || e.button !== 0 || e.shiftKey || e.ctrlKey || e.altKey || e.metaKey || !(e.target instanceof Element)) {
+		return;
+	}
+	const link = e.target.closest('a[href]');
+	if (link === null || link.closest('[data-jawsnav]') === null || link.hasAttribute('target') || link.hasAttribute('download')) {
+		return;
+	}
+	const url = new URL(link.href, window.location.href);
+	if (url.origin !== window.location.origin || (url.hash !== '' && url.pathname === window.location.pathname && url.search === window.location.search)) {
+		return;
+	}
+	e.preventDefault();
+	jawsNavigate(url.href, true);
+}
+
+function jawsPopstate(e) {
+	if (e.state && e.state.jawsNav) {
+		jawsNavigate(window.location.href, false);
+	}
+}
+
+function jawsSocketURL() {
 	let wsScheme = 'ws://';
 	if (window.location.protocol === 'https:') {
 		wsScheme = 'wss://';
 	}
+	return wsScheme + window.location.host + '/jaws/' + encodeURIComponent(document.querySelector('meta[name="jawsKey"]').content);
+}
+
+function jawsConnect() {
+	if (document.querySelector('meta[name="jawsDebug"]') !== null) {
+		jawsDebug = true;
+	}
+	const resumeMeta = document.querySelector('meta[name="jawsResume"]');
+	if (resumeMeta !== null) {
+		jawsResumeWindow = parseInt(resumeMeta.content, 10) || 0;
+	}
 	window.addEventListener('pagehide', jawsUnloading);
 	window.addEventListener('pageshow', jawsPageshow);
-	jaws = new WebSocket(wsScheme + window.location.host + '/jaws/' + encodeURIComponent(document.querySelector('meta[name="jawsKey"]').content));
+	window.addEventListener('resize', jawsClientChanged);
+	window.addEventListener('resize', jawsVirtualReportAll);
+	window.addEventListener('popstate', jawsPopstate);
+	document.addEventListener('visibilitychange', jawsClientChanged);
+	document.addEventListener('click', jawsNavClick, false);
+	if (typeof window.matchMedia === 'function') {
+		window.matchMedia('(prefers-color-scheme: dark)').addEventListener('change', jawsClientChanged);
+	}
+	jawsClientSent = jawsClientInfo();
+	jaws = new WebSocket(jawsSocketURL() + '?client=' + encodeURIComponent(jawsClientSent));
+	jaws.addEventListener('open', jawsVirtualReportAll);
 	jaws.addEventListener('message', jawsMessage);
 	jaws.addEventListener('close', jawsFailed);
 	jaws.addEventListener('error', jawsFailed);
@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -3939,3 +3941,1082 @@ process.stdout.write(JSON.stringify({
 		t.Error("rejected SAttr in frame was not surfaced via console.error")
 	}
 }
+
+func TestJawsJS_ResumeAfterFailure(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+let now = 100000;
+Date.now = function() { return now; };
+const timers = [];
+setTimeout = function(fn, ms, arg) { timers.push({ fn: fn, ms: ms, arg: arg }); };
+let reconnects = 0;
+jawsReconnect = function() { reconnects++; };
+const sockets = [];
+function FakeSocket(url) {
+	this.url = url;
+	this.listeners = {};
+	sockets.push(this);
+}
+FakeSocket.prototype.addEventListener = function(name, fn) { (this.listeners[name] ||= []).push(fn); };
+FakeSocket.prototype.emit = function(name, ev) { (this.listeners[name] || []).forEach(function(fn) { fn(ev || {}); }); };
+FakeSocket.prototype.close = function() {};
+WebSocket = FakeSocket;
+jawsPerform = function() {};
+
+jawsResumeWindow = 30000;
+jaws = new FakeSocket("first");
+jawsMessage({ data: "Inner\tJid.1\t\"a\"\nInner\tJid.2\t\"b\"\n" });
+jawsFailed();
+const resumeURL = sockets[1].url;
+
+// A failed attempt retries; a rejected one falls back to reconnect probing.
+sockets[1].emit("close", { code: 1006 });
+const retries = timers.length;
+timers[0].fn(timers[0].arg);
+sockets[2].emit("open");
+const resumed = jaws === sockets[2];
+jawsMessage({ data: "Inner\tJid.1\t\"c\"\n" });
+
+jawsFailed();
+sockets[3].emit("close", { code: 4000 });
+
+process.stdout.write(JSON.stringify({
+	resumeURL: resumeURL,
+	retries: retries,
+	resumed: resumed,
+	seq: jawsSeq,
+	reconnects: reconnects
+}));
+`)
+	var got struct {
+		ResumeURL  string `json:"resumeURL"`
+		Retries    int    `json:"retries"`
+		Resumed    bool   `json:"resumed"`
+		Seq        int    `json:"seq"`
+		Reconnects int    `json:"reconnects"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	if got.ResumeURL != "ws://example.test/jaws/123?resume=2" {
+		t.Errorf("resume URL = %q", got.ResumeURL)
+	}
+	if got.Retries != 1 || !got.Resumed || got.Seq != 3 || got.Reconnects != 1 {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestJawsJS_KeyDownSendsOnlyRegisteredKeys(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+function FakeSocket() { this.readyState = 1; this.sent = []; }
+FakeSocket.prototype.send = function(msg) { this.sent.push(msg); };
+WebSocket = FakeSocket;
+jaws = new FakeSocket();
+
+const elem = {
+	id: "Jid.7",
+	tagName: "DIV",
+	getAttribute: function(name) { return name === "data-jawskeydown" ? "Enter  Control+s Space" : null; },
+};
+let prevented = 0;
+function press(type, key, code, mods) {
+	const ev = new Event();
+	ev.type = type;
+	ev.currentTarget = elem;
+	ev.key = key;
+	ev.code = code;
+	ev.shiftKey = !!mods.shift;
+	ev.ctrlKey = !!mods.ctrl;
+	ev.altKey = false;
+	ev.metaKey = !!mods.meta;
+	ev.repeat = !!mods.repeat;
+	ev.stopPropagation = function() {};
+	ev.preventDefault = function() { prevented++; };
+	jawsKeyHandler(ev);
+}
+press("keydown", "Enter", "Enter", {repeat: true});
+press("keydown", "a", "KeyA", {});
+press("keydown", "s", "KeyS", {});
+press("keydown", "s", "KeyS", {ctrl: true, meta: true});
+press("keydown", " ", "Space", {shift: true});
+press("keyup", "Enter", "Enter", {});
+process.stdout.write(JSON.stringify({ sent: jaws.sent, prevented: prevented }));
+`)
+
+	var got struct {
+		Sent      []string `json:"sent"`
+		Prevented int      `json:"prevented"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	want := []string{"16 Enter Enter", "10 KeyS s", "1 Space  "}
+	if len(got.Sent) != len(want) || got.Prevented != len(want) {
+		t.Fatalf("sent %q, prevented %d", got.Sent, got.Prevented)
+	}
+	for i, frame := range got.Sent {
+		msg, ok := wire.Parse([]byte(frame))
+		if !ok {
+			t.Fatalf("key frame must be parseable by wire.Parse, got %q", frame)
+		}
+		if msg.What != what.KeyDown || msg.Jid != 7 || msg.Data != want[i] {
+			t.Errorf("frame %d: got %v %v %q, want KeyDown Jid.7 %q", i, msg.What, msg.Jid, msg.Data, want[i])
+		}
+	}
+}
+
+func TestJawsJS_UploadPostsFilesInOrder(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+function FakeSocket() { this.readyState = 1; }
+WebSocket = FakeSocket;
+jaws = new FakeSocket();
+
+const posts = [];
+XMLHttpRequest = function() { this.listeners = {}; };
+XMLHttpRequest.prototype.open = function(method, url) { this.method = method; this.url = url; };
+XMLHttpRequest.prototype.addEventListener = function(name, fn) { this.listeners[name] = fn; };
+XMLHttpRequest.prototype.send = function(body) {
+	posts.push(this.method + " " + this.url + " " + body.data);
+	this.status = body.data === "bad" ? 413 : 204;
+	this.listeners.loadend();
+};
+
+const elem = {
+	id: "Jid.3",
+	value: "C:\\fakepath\\a b.txt",
+	files: [{ name: "a b.txt", data: "one" }, { name: "x&y", data: "bad" }, { name: "never", data: "three" }],
+};
+const ev = new Event();
+ev.currentTarget = elem;
+ev.stopPropagation = function() {};
+jawsUploadHandler(ev);
+process.stdout.write(JSON.stringify({ posts: posts, value: elem.value }));
+`)
+
+	var got struct {
+		Posts []string `json:"posts"`
+		Value string   `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	want := []string{
+		"POST /jaws/123/upload/Jid.3?name=a%20b.txt one",
+		"POST /jaws/123/upload/Jid.3?name=x%26y bad",
+	}
+	if strings.Join(got.Posts, "\n") != strings.Join(want, "\n") {
+		t.Errorf("posts %q, want %q", got.Posts, want)
+	}
+	if got.Value != "" {
+		t.Errorf("selection not cleared: %q", got.Value)
+	}
+}
+
+func TestJawsJS_SortableSendsReorder(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+function FakeSocket() { this.readyState = 1; this.sent = []; }
+FakeSocket.prototype.send = function(msg) { this.sent.push(msg); };
+WebSocket = FakeSocket;
+jaws = new FakeSocket();
+
+const container = {
+	id: "Jid.1",
+	children: [],
+	insertBefore: function(child, ref) {
+		this.children.splice(this.children.indexOf(child), 1);
+		const at = ref ? this.children.indexOf(ref) : this.children.length;
+		this.children.splice(at, 0, child);
+	},
+};
+function child(id) {
+	const c = { id: id, tagName: "TR", parentElement: container };
+	Object.defineProperty(c, "nextSibling", { get: function() {
+		return container.children[container.children.indexOf(c) + 1] || null;
+	}});
+	container.children.push(c);
+	return c;
+}
+const a = child("Jid.2"), b = child("Jid.3"), c = child("Jid.4");
+const input = { tagName: "INPUT", parentElement: b };
+function fire(type, target) {
+	const ev = new Event();
+	ev.type = type;
+	ev.target = target;
+	ev.currentTarget = container;
+	ev.stopPropagation = function() {};
+	ev.preventDefault = function() {};
+	jawsSortableHandler(ev);
+}
+fire("pointerdown", input);
+const inputDraggable = b.draggable;
+fire("pointerdown", a);
+fire("dragstart", a);
+fire("dragover", b);
+fire("dragover", c);
+fire("drop", c);
+fire("dragend", a);
+const order = container.children.map(x => x.id).join(" ");
+fire("dragstart", b);
+fire("dragend", b);
+process.stdout.write(JSON.stringify({ sent: jaws.sent, order: order, a: a.draggable, input: inputDraggable }));
+`)
+
+	var got struct {
+		Sent  []string `json:"sent"`
+		Order string   `json:"order"`
+		A     bool     `json:"a"`
+		Input bool     `json:"input"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	if !got.A || got.Input {
+		t.Errorf("draggable: child %v, pressed inside input %v", got.A, got.Input)
+	}
+	if got.Order != "Jid.3 Jid.4 Jid.2" {
+		t.Errorf("DOM order %q", got.Order)
+	}
+	if len(got.Sent) != 1 {
+		t.Fatalf("sent %q", got.Sent)
+	}
+	msg, ok := wire.Parse([]byte(got.Sent[0]))
+	if !ok || msg.What != what.Reorder || msg.Jid != 1 || msg.Data != "Jid.3 Jid.4 Jid.2" {
+		t.Errorf("frame %q", got.Sent[0])
+	}
+}
+
+func TestJawsJS_RPCSettlesPromises(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+const timers = [];
+setTimeout = function(fn, ms) { timers.push({ fn: fn, ms: ms }); return timers.length; };
+clearTimeout = function(id) { timers[id - 1].cleared = true; };
+function FakeSocket() { this.readyState = 1; this.sent = []; }
+FakeSocket.prototype.send = function(msg) { this.sent.push(msg); };
+WebSocket = FakeSocket;
+const socket = new FakeSocket();
+jaws = socket;
+const settled = {};
+function track(key, p) {
+	p.then(function(v) { settled[key] = { result: v }; }, function(e) { settled[key] = { error: e.message }; });
+}
+function reply(obj) {
+	jawsMessage({ data: "Reply\t\t" + JSON.stringify(JSON.stringify(obj)) + "\n" });
+}
+track("ok", jawsRPC("sum", [1, 2]));
+track("fail", jawsRPC("boom"));
+track("slow", jawsRPC("slow", {}, 500));
+track("badname", jawsRPC("a b"));
+reply({ id: 1, result: 3 });
+reply({ id: 2, error: "nope" });
+timers[2].fn();
+reply({ id: 3, result: "late" });
+jaws = null;
+track("offline", jawsRPC("sum"));
+setImmediate(function() {
+	process.stdout.write(JSON.stringify({
+		sent: socket.sent,
+		settled: settled,
+		timeouts: timers.map(function(t) { return t.ms; }),
+		cleared: timers.map(function(t) { return !!t.cleared; }),
+		pending: jawsRPCPending.size
+	}));
+});
+`)
+	var got struct {
+		Sent    []string                  `json:"sent"`
+		Settled map[string]map[string]any `json:"settled"`
+		Timeout []int                     `json:"timeouts"`
+		Cleared []bool                    `json:"cleared"`
+		Pending int                       `json:"pending"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	wantSent := []string{
+		"RPC\t\t\"1 sum [1,2]\"\n",
+		"RPC\t\t\"2 boom null\"\n",
+		"RPC\t\t\"3 slow {}\"\n",
+	}
+	if !reflect.DeepEqual(got.Sent, wantSent) {
+		t.Errorf("sent %q", got.Sent)
+	}
+	wantSettled := map[string]map[string]any{
+		"ok":      {"result": float64(3)},
+		"fail":    {"error": "nope"},
+		"slow":    {"error": "jaws: RPC timeout: slow"},
+		"badname": {"error": "jaws: invalid RPC name: a b"},
+		"offline": {"error": "jaws: not connected"},
+	}
+	if !reflect.DeepEqual(got.Settled, wantSettled) {
+		t.Errorf("settled %v", got.Settled)
+	}
+	if !reflect.DeepEqual(got.Timeout, []int{30000, 30000, 500}) || !reflect.DeepEqual(got.Cleared, []bool{true, true, false}) || got.Pending != 0 {
+		t.Errorf("timers %v %v pending %d", got.Timeout, got.Cleared, got.Pending)
+	}
+}
+
+func TestJawsJS_AskSendsAnswers(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+function FakeSocket() { this.readyState = 1; this.sent = []; }
+FakeSocket.prototype.send = function(msg) { this.sent.push(msg); };
+WebSocket = FakeSocket;
+jaws = new FakeSocket();
+window.confirm = function(msg) { return msg === "sure?"; };
+window.prompt = function(msg, value) { return msg === "name?" ? value + "!" : null; };
+window.innerWidth = 800;
+window.innerHeight = 600;
+jawsRegisterQuery("later", function() { return Promise.resolve([1, 2]); });
+jawsRegisterQuery("broken", function() { throw new Error("broken"); });
+function ask(q) {
+	jawsMessage({ data: "Ask\t\t" + JSON.stringify(JSON.stringify(q)) + "\n" });
+}
+ask({ id: 1, kind: "confirm", message: "sure?" });
+ask({ id: 2, kind: "prompt", message: "name?", value: "bob" });
+ask({ id: 3, kind: "prompt", message: "other?" });
+ask({ id: 4, kind: "query", name: "viewport" });
+ask({ id: 5, kind: "query", name: "later" });
+ask({ id: 6, kind: "query", name: "broken" });
+ask({ id: 7, kind: "query", name: "missing" });
+setTimeout(function() {
+	process.stdout.write(JSON.stringify(jaws.sent.map(function(msg) {
+		return JSON.parse(JSON.parse(msg.split("\t")[2]));
+	})));
+}, 0);
+`)
+	var got []map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	want := []map[string]any{
+		{"id": float64(1), "result": true},
+		{"id": float64(2), "result": "bob!"},
+		{"id": float64(3), "result": nil},
+		{"id": float64(4), "result": map[string]any{"width": float64(800), "height": float64(600)}},
+		{"id": float64(6), "error": "broken"},
+		{"id": float64(7), "error": "jaws: unknown query: missing"},
+		{"id": float64(5), "result": []any{float64(1), float64(2)}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("answers %v", got)
+	}
+}
+
+func TestJawsJS_ClientInfoOnConnectAndChange(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+const timers = [];
+setTimeout = function(fn, ms) { timers.push({ fn: fn, ms: ms }); return timers.length; };
+const docListeners = {};
+document.addEventListener = function(name, fn) { (docListeners[name] ||= []).push(fn); };
+document.visibilityState = "visible";
+window.innerWidth = 800;
+window.innerHeight = 600;
+window.devicePixelRatio = 2;
+let dark = true;
+window.matchMedia = function() { return { matches: dark, addEventListener: function() {} }; };
+function FakeSocket(url) { this.url = url; this.readyState = 1; this.sent = []; }
+FakeSocket.prototype.addEventListener = function() {};
+FakeSocket.prototype.send = function(msg) { this.sent.push(msg); };
+WebSocket = FakeSocket;
+jawsConnect();
+const url = new URL(jaws.url);
+const initial = JSON.parse(url.searchParams.get("client"));
+jawsDispatchWindowEvent("resize");
+jawsDispatchWindowEvent("resize");
+const pending = timers.length;
+timers[0].fn();
+window.innerWidth = 1024;
+docListeners.visibilitychange[0]();
+document.visibilityState = "hidden";
+timers[1].fn();
+process.stdout.write(JSON.stringify({
+	initial: initial,
+	pending: pending,
+	delay: timers[0].ms,
+	sent: jaws.sent.map(function(msg) { return JSON.parse(JSON.parse(msg.split("\t")[2])); })
+}));
+`)
+	var got struct {
+		Initial map[string]any   `json:"initial"`
+		Pending int              `json:"pending"`
+		Delay   int              `json:"delay"`
+		Sent    []map[string]any `json:"sent"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	if got.Initial["width"] != float64(800) || got.Initial["pixelRatio"] != float64(2) || got.Initial["colorScheme"] != "dark" || got.Initial["hidden"] != false {
+		t.Errorf("initial %v", got.Initial)
+	}
+	if _, ok := got.Initial["timeZone"].(string); !ok {
+		t.Errorf("timeZone %v", got.Initial["timeZone"])
+	}
+	if got.Pending != 1 || got.Delay != 250 {
+		t.Errorf("pending %d delay %d", got.Pending, got.Delay)
+	}
+	if len(got.Sent) != 1 || got.Sent[0]["width"] != float64(1024) || got.Sent[0]["hidden"] != true {
+		t.Errorf("sent %v", got.Sent)
+	}
+}
+
+func TestJawsJS_DOMEventsForwardedWithRateLimits(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+const timers = [];
+setTimeout = function(fn, ms) { timers.push({ fn: fn, ms: ms }); return timers.length; };
+clearTimeout = function(id) { if (id) { timers[id - 1].fn = function() {}; } };
+function FakeSocket() { this.readyState = 1; this.sent = []; }
+FakeSocket.prototype.send = function(msg) { this.sent.push(msg); };
+WebSocket = FakeSocket;
+jaws = new FakeSocket();
+
+const listeners = {};
+const elem = {
+	id: "Jid.3",
+	tagName: "DIV",
+	scrollTop: 0,
+	getAttribute: function(name) { return name === "data-jawsevents" ? "dblclick wheel:throttle=100  scroll:debounce=50 bad:throttle Bad" : null; },
+	hasAttribute: function(name) { return name === "data-jawsevents"; },
+	addEventListener: function(name, fn, opts) { listeners[name] = { fn: fn, passive: opts.passive }; },
+};
+jawsAttach(elem);
+function fire(type, props) {
+	const ev = new Event();
+	ev.type = type;
+	ev.currentTarget = elem;
+	Object.assign(ev, props);
+	listeners[type].fn(ev);
+}
+fire("dblclick", { clientX: 5, clientY: 6, buttons: 0, shiftKey: true });
+fire("wheel", { deltaX: 0, deltaY: 1 });
+fire("wheel", { deltaX: 0, deltaY: 2 });
+fire("wheel", { deltaX: 0, deltaY: 3 });
+const afterWheel = jaws.sent.length;
+timers[0].fn();
+timers[1].fn();
+elem.scrollTop = 10;
+fire("scroll", {});
+elem.scrollTop = 20;
+fire("scroll", {});
+const afterScroll = jaws.sent.length;
+timers[timers.length - 1].fn();
+process.stdout.write(JSON.stringify({
+	types: Object.keys(listeners).sort(),
+	passive: listeners.wheel.passive,
+	afterWheel: afterWheel,
+	afterScroll: afterScroll,
+	sent: jaws.sent
+}));
+`)
+	var got struct {
+		Types       []string `json:"types"`
+		Passive     bool     `json:"passive"`
+		AfterWheel  int      `json:"afterWheel"`
+		AfterScroll int      `json:"afterScroll"`
+		Sent        []string `json:"sent"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	if strings.Join(got.Types, " ") != "click contextmenu dblclick scroll wheel" || !got.Passive {
+		t.Errorf("listeners %v passive %v", got.Types, got.Passive)
+	}
+	if got.AfterWheel != 2 || got.AfterScroll != 3 || len(got.Sent) != 4 {
+		t.Fatalf("after wheel %d, after scroll %d, sent %q", got.AfterWheel, got.AfterScroll, got.Sent)
+	}
+	want := []map[string]any{
+		{"type": "dblclick", "x": float64(5), "y": float64(6), "shift": true},
+		{"type": "wheel", "deltaY": float64(1)},
+		{"type": "wheel", "deltaY": float64(3)},
+		{"type": "scroll", "scrollTop": float64(20)},
+	}
+	for i, frame := range got.Sent {
+		msg, ok := wire.Parse([]byte(frame))
+		if !ok {
+			t.Fatalf("DOMEvent frame must be parseable by wire.Parse, got %q", frame)
+		}
+		if msg.What != what.DOMEvent || msg.Jid != 3 {
+			t.Errorf("frame %d: got %v %v", i, msg.What, msg.Jid)
+		}
+		var data map[string]any
+		if err := json.Unmarshal([]byte(msg.Data), &data); err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range want[i] {
+			if data[k] != v {
+				t.Errorf("frame %d: %s = %v, want %v", i, k, data[k], v)
+			}
+		}
+	}
+}
+
+func TestJawsJS_VirtualReportsScrollOnAttachAndOpen(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+function FakeSocket() { this.readyState = 1; this.sent = []; }
+FakeSocket.prototype.send = function(msg) { this.sent.push(msg); };
+WebSocket = FakeSocket;
+jaws = new FakeSocket();
+
+const listeners = {};
+const elem = {
+	id: "Jid.4",
+	tagName: "DIV",
+	scrollTop: 40,
+	clientWidth: 300,
+	clientHeight: 200,
+	getAttribute: function(name) { return name === "data-jawsevents" ? "scroll" : null; },
+	hasAttribute: function(name) { return name === "data-jawsevents" || name === "data-jawsvirtual"; },
+	addEventListener: function(name, fn) { listeners[name] = fn; },
+	dispatchEvent: function(ev) { ev.currentTarget = this; listeners[ev.type](ev); },
+};
+Event = function(type) { this.type = type; };
+jawsAttach(elem);
+document.querySelectorAll = function(sel) { return sel === "[data-jawsvirtual]" ? [elem] : []; };
+jawsVirtualReportAll();
+jaws.readyState = 0;
+jawsVirtualReportAll();
+process.stdout.write(JSON.stringify(jaws.sent));
+`)
+	var sent []string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &sent); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("sent %q", sent)
+	}
+	for _, frame := range sent {
+		msg, ok := wire.Parse([]byte(frame))
+		if !ok || msg.What != what.DOMEvent || msg.Jid != 4 {
+			t.Fatalf("frame %q", frame)
+		}
+		var data map[string]any
+		if err := json.Unmarshal([]byte(msg.Data), &data); err != nil {
+			t.Fatal(err)
+		}
+		if data["type"] != "scroll" || data["scrollTop"] != float64(40) || data["clientWidth"] != float64(300) || data["clientHeight"] != float64(200) {
+			t.Errorf("data %v", data)
+		}
+	}
+}
+
+func TestJawsJS_InputPolicies(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+const timers = [];
+setTimeout = function(fn, ms) { timers.push({ fn: fn, ms: ms }); return timers.length; };
+clearTimeout = function(id) { if (id) { timers[id - 1].fn = function() {}; } };
+function FakeSocket() { this.readyState = 1; this.sent = []; }
+FakeSocket.prototype.send = function(msg) { this.sent.push(msg.split("\t")[1] + " " + JSON.parse(msg.split("\t")[2])); };
+WebSocket = FakeSocket;
+jaws = new FakeSocket();
+
+function input(id, policy) {
+	const elem = {
+		id: id,
+		tagName: "INPUT",
+		type: "text",
+		value: "",
+		selectionStart: 0,
+		selectionEnd: 0,
+		listeners: {},
+		getAttribute: function(name) { return name === "data-jawsinput" ? policy : null; },
+		hasAttribute: function(name) { return name === "data-jawsinput"; },
+		addEventListener: function(name, fn) { this.listeners[name] = fn; },
+	};
+	jawsAttach(elem);
+	elems[id] = elem;
+	return elem;
+}
+const elems = {};
+document.getElementById = function(id) { return elems[id] || null; };
+function fire(elem, type, value) {
+	const ev = new Event();
+	ev.type = type;
+	ev.currentTarget = elem;
+	ev.stopPropagation = function() {};
+	elem.value = value;
+	elem.listeners[type](ev);
+}
+const onChange = input("Jid.1", "change");
+const debounced = input("Jid.2", "debounce=300");
+const throttled = input("Jid.3", "throttle=100");
+const events = [Object.keys(onChange.listeners).join(), Object.keys(debounced.listeners).sort().join()];
+
+fire(onChange, "change", "a");
+fire(debounced, "input", "d");
+fire(debounced, "input", "de");
+const debouncedHeld = jaws.sent.length;
+jawsPerform("Value", "Jid.2", JSON.stringify("server"));
+const keptEdit = debounced.value;
+timers[1].fn();
+fire(debounced, "input", "def");
+fire(debounced, "change", "defg");
+timers[2].fn();
+
+fire(throttled, "input", "t");
+fire(throttled, "input", "th");
+fire(throttled, "input", "thr");
+timers[3].fn();
+timers[4].fn();
+jawsPerform("Value", "Jid.3", JSON.stringify("server"));
+process.stdout.write(JSON.stringify({
+	events: events,
+	debouncedHeld: debouncedHeld,
+	keptEdit: keptEdit,
+	throttledValue: throttled.value,
+	sent: jaws.sent
+}));
+`)
+	var got struct {
+		Events         []string `json:"events"`
+		DebouncedHeld  int      `json:"debouncedHeld"`
+		KeptEdit       string   `json:"keptEdit"`
+		ThrottledValue string   `json:"throttledValue"`
+		Sent           []string `json:"sent"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	if strings.Join(got.Events, " ") != "change change,input" {
+		t.Errorf("listeners %q", got.Events)
+	}
+	if got.DebouncedHeld != 1 || got.KeptEdit != "de" || got.ThrottledValue != "server" {
+		t.Errorf("held %d, kept %q, throttled value %q", got.DebouncedHeld, got.KeptEdit, got.ThrottledValue)
+	}
+	want := "Jid.1 a|Jid.2 de|Jid.2 defg|Jid.3 t|Jid.3 thr"
+	if s := strings.Join(got.Sent, "|"); s != want {
+		t.Errorf("sent %q, want %q", s, want)
+	}
+}
+
+func TestJawsJS_BusyMarksUntilAck(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+function FakeSocket() { this.readyState = 1; this.sent = []; }
+FakeSocket.prototype.send = function(msg) { this.sent.push(msg.split("\t")[0]); };
+WebSocket = FakeSocket;
+jaws = new FakeSocket();
+
+function button(id, policy) {
+	const attrs = { "data-jawsbusy": policy };
+	const classes = new Set();
+	return {
+		id: id,
+		tagName: "BUTTON",
+		disabled: false,
+		classes: classes,
+		attrs: attrs,
+		parentElement: null,
+		hasAttribute: function(name) { return name in attrs; },
+		getAttribute: function(name) { return name in attrs ? attrs[name] : null; },
+		setAttribute: function(name, value) { attrs[name] = value; },
+		removeAttribute: function(name) { delete attrs[name]; },
+		classList: { add: function(c) { classes.add(c); }, remove: function(c) { classes.delete(c); } },
+	};
+}
+function click(elem) {
+	const ev = new Event();
+	ev.type = "click";
+	ev.target = elem;
+	ev.clientX = 1;
+	ev.clientY = 2;
+	ev.stopPropagation = function() {};
+	jawsClickHandler(ev);
+}
+function state(elem) {
+	return [elem.attrs["aria-busy"] || "", elem.classes.has("jaws-busy"), elem.disabled].join(",");
+}
+const indicate = button("Jid.1", "");
+const drop = button("Jid.2", "drop");
+const disable = button("Jid.3", "disable");
+const states = [];
+click(indicate);
+click(indicate);
+click(drop);
+click(drop);
+click(disable);
+states.push(state(indicate), state(drop), state(disable));
+jawsPerform("Ack", "Jid.1", JSON.stringify(""));
+states.push(state(indicate));
+jawsPerform("Ack", "Jid.1", JSON.stringify(""));
+jawsPerform("Ack", "Jid.2", JSON.stringify(""));
+jawsPerform("Ack", "Jid.9", JSON.stringify(""));
+states.push(state(indicate), state(drop));
+jawsBusyEnd("", true);
+states.push(state(disable));
+process.stdout.write(JSON.stringify({ states: states, sent: jaws.sent.length }));
+`)
+	var got struct {
+		States []string `json:"states"`
+		Sent   int      `json:"sent"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	want := []string{
+		"true,true,false", "true,true,false", "true,true,true",
+		"true,true,false",
+		",false,false", ",false,false",
+		",false,false",
+	}
+	if strings.Join(got.States, "|") != strings.Join(want, "|") {
+		t.Errorf("states %q, want %q", got.States, want)
+	}
+	if got.Sent != 4 {
+		t.Errorf("sent %d clicks, want 4", got.Sent)
+	}
+}
+
+func TestJawsJS_NavigateHandsOverWebSocket(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+function FakeSocket() { this.readyState = 1; this.sent = []; }
+FakeSocket.prototype.send = function(msg) { this.sent.push(msg); };
+WebSocket = FakeSocket;
+jaws = new FakeSocket();
+
+var keyMeta = { content: "123" };
+document.querySelector = function(selector) { return selector === 'meta[name="jawsKey"]' ? keyMeta : null; };
+document.title = "old";
+document.body = { replaceWith: function(body) { document.body = body; } };
+document.adoptNode = function(node) { return node; };
+window.location.href = "http://example.test/a";
+var assigned = [];
+window.location.assign = function(url) { assigned.push(url); };
+window.scrollTo = function() {};
+var pushed = [];
+global.history = {
+	state: null,
+	replaceState: function(state) { this.state = state; pushed.push("replace"); },
+	pushState: function(state, title, url) { this.state = state; pushed.push(url); },
+};
+var pages = {
+	"/b": { ctype: "text/html; charset=utf-8", key: "456" },
+	"/c": { ctype: "text/html", key: "" },
+	"/d": { ctype: "text/html", key: "789" },
+};
+global.fetch = function(url) {
+	const page = pages[url];
+	return Promise.resolve({
+		ok: true,
+		redirected: false,
+		headers: { get: function() { return page.ctype; } },
+		text: function() { return Promise.resolve(url); },
+	});
+};
+global.DOMParser = function() {};
+DOMParser.prototype.parseFromString = function(url) {
+	const page = pages[url];
+	return {
+		title: "page " + url,
+		body: { querySelectorAll: function() { return { forEach: function() {} }; }, url: url },
+		querySelector: function() { return page.key ? { content: page.key } : null; },
+	};
+};
+function settle() { return new Promise(resolve => setImmediate(resolve)); }
+
+(async function() {
+	jawsNavigate("/b", true);
+	const blocked = jawsCanSend();
+	await settle();
+	jawsPerform("Navigate", "", JSON.stringify("456"));
+	const swapped = [keyMeta.content, document.title, document.body.url, jawsCanSend()].join(",");
+	jawsNavigate("/c", true);
+	await settle();
+	jawsNavigate("/d", false);
+	await settle();
+	jawsPerform("Navigate", "", JSON.stringify(""));
+	process.stdout.write(JSON.stringify({
+		sent: jaws.sent,
+		blocked: blocked,
+		swapped: swapped,
+		pushed: pushed,
+		assigned: assigned,
+		body: document.body.url,
+	}));
+})();
+`)
+	var got struct {
+		Sent     []string `json:"sent"`
+		Blocked  bool     `json:"blocked"`
+		Swapped  string   `json:"swapped"`
+		Pushed   []string `json:"pushed"`
+		Assigned []string `json:"assigned"`
+		Body     string   `json:"body"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	if want := []string{"Navigate\t\t\"456\"\n", "Navigate\t\t\"789\"\n"}; strings.Join(got.Sent, "|") != strings.Join(want, "|") {
+		t.Errorf("sent %q, want %q", got.Sent, want)
+	}
+	if got.Blocked {
+		t.Error("jawsCanSend true while navigating")
+	}
+	if want := "456,page /b,/b,true"; got.Swapped != want {
+		t.Errorf("after navigating %q, want %q", got.Swapped, want)
+	}
+	if want := []string{"replace", "/b"}; strings.Join(got.Pushed, "|") != strings.Join(want, "|") {
+		t.Errorf("history %q, want %q", got.Pushed, want)
+	}
+	if want := []string{"/c", "/d"}; strings.Join(got.Assigned, "|") != strings.Join(want, "|") {
+		t.Errorf("loaded %q, want %q", got.Assigned, want)
+	}
+	if got.Body != "/b" {
+		t.Errorf("body of %q, want /b", got.Body)
+	}
+}
+
+func TestJawsJS_FocusScrollSelectDialog(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+var calls = [];
+var elem = {
+	id: "Jid.1",
+	value: "hello",
+	open: false,
+	focus: function() { calls.push("focus"); },
+	scrollIntoView: function(opts) { calls.push("scroll " + JSON.stringify(opts)); },
+	setSelectionRange: function(start, end) { calls.push("select " + start + "-" + end); },
+	showModal: function() { this.open = true; calls.push("showModal"); },
+	close: function() { this.open = false; calls.push("close"); },
+};
+var plain = { id: "Jid.2", focus: function() { calls.push("focus plain"); } };
+document.getElementById = function(id) { return id === "Jid.1" ? elem : plain; };
+function perform(what, id, data) { jawsPerform(what, id, JSON.stringify(data)); }
+perform("Focus", "Jid.1", "");
+perform("Scroll", "Jid.1", '{"block":"center"}');
+perform("Select", "Jid.1", "1 3");
+perform("Select", "Jid.1", "0 -1");
+perform("Select", "Jid.2", "0 -1");
+perform("ShowModal", "Jid.1", "");
+perform("ShowModal", "Jid.1", "");
+perform("Close", "Jid.1", "");
+process.stdout.write(JSON.stringify(calls));
+`)
+	var got []string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	want := []string{
+		"focus",
+		`scroll {"block":"center"}`,
+		"focus", "select 1-3",
+		"focus", "select 0-5",
+		"focus plain",
+		"showModal",
+		"close",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("calls %q, want %q", got, want)
+	}
+}
+
+func TestJawsJS_DownloadAndClipboard(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+var calls = [];
+document.body = {
+	appendChild: function(a) { calls.push("append"); },
+};
+document.createElement = function(tag) {
+	return {
+		tagName: tag,
+		style: {},
+		click: function() { calls.push("click " + this.href + " " + JSON.stringify(this.download)); },
+		remove: function() { calls.push("remove"); },
+	};
+};
+Object.defineProperty(globalThis, "navigator", { value: { clipboard: {
+	writeText: function(text) { calls.push("copy " + text); return Promise.reject("denied"); },
+} }, configurable: true });
+console.error = function(msg) { calls.push(msg); };
+jawsPerform("Download", "", JSON.stringify("/jaws/.download/abc/def"));
+jawsPerform("Clipboard", "", JSON.stringify("copied\ttext"));
+setImmediate(function() { process.stdout.write(JSON.stringify(calls)); });
+`)
+	var got []string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	want := []string{
+		"append",
+		`click /jaws/.download/abc/def ""`,
+		"remove",
+		"copy copied\ttext",
+		"jaws: clipboard: denied",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("calls %q, want %q", got, want)
+	}
+}
+
+func TestJawsJS_ToastsStackReplaceAndDismiss(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+function FakeSocket() { this.readyState = 1; this.sent = []; }
+FakeSocket.prototype.send = function(msg) { this.sent.push(msg); };
+WebSocket = FakeSocket;
+jaws = new FakeSocket();
+
+function fakeElement(tag) {
+	const elem = {
+		tagName: tag,
+		className: "",
+		attrs: {},
+		children: [],
+		parent: null,
+		listeners: {},
+		textContent: "",
+		innerHTML: "",
+		setAttribute: function(name, value) { this.attrs[name] = value; },
+		addEventListener: function(name, fn) { this.listeners[name] = fn; },
+		appendChild: function(child) { child.parent = this; this.children.push(child); return child; },
+		replaceChildren: function() { this.children = []; for (const c of arguments) { this.appendChild(c); } },
+		remove: function() { if (this.parent) { this.parent.children.splice(this.parent.children.indexOf(this), 1); this.parent = null; } },
+	};
+	return elem;
+}
+document.body = fakeElement("BODY");
+document.createElement = fakeElement;
+document.querySelector = function(selector) {
+	return selector === "[data-jaws-toasts]" && document.body.children.length ? document.body.children[0] : null;
+};
+var timers = [];
+setTimeout = function(fn, ms) { timers.push({ fn: fn, ms: ms }); return timers.length; };
+clearTimeout = function(id) { if (id) { timers[id - 1].fn = null; } };
+
+function perform(t) { jawsPerform("Toast", "", JSON.stringify(JSON.stringify(t))); }
+perform({ id: 1, level: "info", title: "Hello", body: "<b>hi</b>", timeout: 500 });
+perform({ id: 2, level: "danger", body: "bad", action: "Retry" });
+const container = document.body.children[0];
+const first = container.children[0];
+const shape = [container.attrs["data-jaws-toasts"], container.className, container.children.length,
+	first.className, first.attrs.role, first.children[0].children[0].textContent, first.children[1].innerHTML].join("|");
+perform({ id: 1, level: "success", body: "replaced" });
+const replaced = [container.children.length, first.className, first.children[0].children.length, first.children[1].innerHTML, timers[0].fn === null].join("|");
+container.children[1].children[1].children[0].listeners.click();
+first.children[0].children[0].listeners.click();
+process.stdout.write(JSON.stringify({ shape: shape, replaced: replaced, left: container.children.length, sent: jaws.sent }));
+`)
+	var got struct {
+		Shape    string   `json:"shape"`
+		Replaced string   `json:"replaced"`
+		Left     int      `json:"left"`
+		Sent     []string `json:"sent"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	if want := "|jaws-toasts|2|jaws-toast jaws-toast-info|status|Hello|<b>hi</b>"; got.Shape != want {
+		t.Errorf("shape %q, want %q", got.Shape, want)
+	}
+	if want := "2|jaws-toast jaws-toast-success|1|replaced|true"; got.Replaced != want {
+		t.Errorf("replaced %q, want %q", got.Replaced, want)
+	}
+	if got.Left != 0 {
+		t.Errorf("%d toasts left", got.Left)
+	}
+	want := []string{
+		"Dismiss\t\t" + strconv.Quote(`{"id":2,"action":true}`) + "\n",
+		"Dismiss\t\t" + strconv.Quote(`{"id":1,"action":false}`) + "\n",
+	}
+	if strings.Join(got.Sent, "|") != strings.Join(want, "|") {
+		t.Errorf("sent %q, want %q", got.Sent, want)
+	}
+}
+
+func TestJawsJS_AlertAndBroadcastToastsAreLocal(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+function FakeSocket() { this.readyState = 1; this.sent = []; }
+FakeSocket.prototype.send = function(msg) { this.sent.push(msg); };
+WebSocket = FakeSocket;
+jaws = new FakeSocket();
+
+function fakeElement(tag) {
+	return {
+		tagName: tag,
+		className: "",
+		attrs: {},
+		children: [],
+		parent: null,
+		listeners: {},
+		textContent: "",
+		innerHTML: "",
+		setAttribute: function(name, value) { this.attrs[name] = value; },
+		addEventListener: function(name, fn) { this.listeners[name] = fn; },
+		appendChild: function(child) { child.parent = this; this.children.push(child); return child; },
+		replaceChildren: function() { this.children = []; for (const c of arguments) { this.appendChild(c); } },
+		remove: function() { if (this.parent) { this.parent.children.splice(this.parent.children.indexOf(this), 1); this.parent = null; } },
+	};
+}
+document.body = fakeElement("BODY");
+document.createElement = fakeElement;
+document.querySelector = function(selector) {
+	return selector === "[data-jaws-toasts]" && document.body.children.length ? document.body.children[0] : null;
+};
+
+jawsPerform("Alert", "", JSON.stringify("warning\nDisk &lt;full&gt;"));
+function perform(t) { jawsPerform("Toast", "", JSON.stringify(JSON.stringify(t))); }
+perform({ id: 0, level: "info", body: "one", key: "deploy" });
+perform({ id: 0, level: "info", body: "two", key: "deploy" });
+const container = document.body.children[0];
+const bodies = container.children.map(c => c.attrs.role + ":" + c.children[1].innerHTML).join("|");
+while (container.children.length) {
+	container.children[0].children[0].children[0].listeners.click();
+}
+process.stdout.write(JSON.stringify({ bodies: bodies, sent: jaws.sent }));
+`)
+	var got struct {
+		Bodies string   `json:"bodies"`
+		Sent   []string `json:"sent"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	if want := "alert:Disk &lt;full&gt;|status:two"; got.Bodies != want {
+		t.Errorf("bodies %q, want %q", got.Bodies, want)
+	}
+	if len(got.Sent) != 0 {
+		t.Errorf("sent %q for toasts the server does not track", got.Sent)
+	}
+}
+
+func TestJawsJS_MultipleSelectSendsAndSetsSelectedSet(t *testing.T) {
+	raw := runJawsJSSnippet(t, `
+function FakeSocket() { this.readyState = 1; this.sent = []; }
+FakeSocket.prototype.send = function(msg) { this.sent.push(msg); };
+WebSocket = FakeSocket;
+jaws = new FakeSocket();
+
+const options = [
+	{ value: "a", selected: true },
+	{ value: "b", selected: false },
+	{ value: "c", selected: true }
+];
+const select = {
+	id: "Jid.1",
+	tagName: "SELECT",
+	multiple: true,
+	options: options,
+	get selectedOptions() { return options.filter(opt => opt.selected); },
+	hasAttribute: function() { return false; },
+	getAttribute: function() { return null; }
+};
+jawsSendInput(select);
+jawsSetValue(select, JSON.stringify(["b"]));
+process.stdout.write(JSON.stringify({
+	frames: jaws.sent,
+	selected: options.map(opt => opt.selected)
+}));
+`)
+
+	var got struct {
+		Frames   []string `json:"frames"`
+		Selected []bool   `json:"selected"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &got); err != nil {
+		t.Fatalf("failed to parse snippet output %q: %v", raw, err)
+	}
+	if len(got.Frames) != 1 {
+		t.Fatalf("frames = %q, want one frame", got.Frames)
+	}
+	msg, ok := wire.Parse([]byte(got.Frames[0]))
+	if !ok || msg.What != what.Input || msg.Jid != 1 || msg.Data != `["a","c"]` {
+		t.Fatalf("unexpected frame: %+v, parseable %t", msg, ok)
+	}
+	if !slices.Equal(got.Selected, []bool{false, true, false}) {
+		t.Fatalf("selected = %v", got.Selected)
+	}
+}
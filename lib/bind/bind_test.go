@@ -173,6 +173,31 @@ func TestBind_Hook_SuccessHook(t *testing.T) {
 	}
 }
 
+func TestBind_JawsLockerAndSucceeded(t *testing.T) {
+	var mu sync.Mutex
+	var a, b int
+	calls := 0
+	binderA := New(&mu, &a).Success(func() { calls++ })
+	binderB := New(&mu, &b)
+	if binderA.JawsLocker() != binderB.JawsLocker() {
+		t.Error("Binders over the same lock report different lockers")
+	}
+	var other sync.Mutex
+	if New(&other, &b).JawsLocker() == binderB.JawsLocker() {
+		t.Error("Binders over different locks report the same locker")
+	}
+
+	binderA.JawsLocker().Lock()
+	err := binderA.JawsSetLocked(nil, 1)
+	binderA.JawsLocker().Unlock()
+	if err != nil || calls != 0 {
+		t.Fatalf("JawsSetLocked: %v, %d calls", err, calls)
+	}
+	if err = binderA.JawsSucceeded(nil); err != nil || calls != 1 || a != 1 {
+		t.Errorf("JawsSucceeded: %v, %d calls, a=%d", err, calls, a)
+	}
+}
+
 func TestBind_Hook_Success_breaksonerr(t *testing.T) {
 	var mu deadlock.Mutex
 	var val string
@@ -695,6 +720,38 @@ func TestBind_Hook_ContextMenu_binding(t *testing.T) {
 	}
 }
 
+func TestBind_Hook_Key_binding(t *testing.T) {
+	var mu deadlock.Mutex
+	var val string
+
+	var got []jaws.Key
+	bind := New(&mu, &val).
+		Key(func(bind Binder[string], elem *jaws.Element, key jaws.Key) (err error) {
+			return jaws.ErrEventUnhandled
+		}).
+		Key(func(bind Binder[string], elem *jaws.Element, key jaws.Key) (err error) {
+			got = append(got, key)
+			if key.Key != "Enter" {
+				err = jaws.ErrEventUnhandled
+			}
+			return
+		})
+
+	handler, ok := bind.(jaws.KeyHandler)
+	if !ok {
+		t.Fatalf("%T does not implement KeyHandler", bind)
+	}
+	if err := handler.JawsKey(nil, jaws.Key{Key: "Enter", Code: "Enter"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := handler.JawsKey(nil, jaws.Key{Key: "x"}); !errors.Is(err, jaws.ErrEventUnhandled) {
+		t.Errorf("want ErrEventUnhandled got %v", err)
+	}
+	if len(got) != 2 || got[0].Key != "Enter" {
+		t.Error(got)
+	}
+}
+
 func TestBind_Hook_ContextMenu_bindingHook_fallsThroughUnhandled(t *testing.T) {
 	var mu deadlock.Mutex
 	var val string
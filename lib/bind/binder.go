@@ -56,6 +56,14 @@ type ClickedHook[T comparable] func(bind Binder[T], elem *jaws.Element, click ja
 // current Binder (the one whose hook is being invoked), not the previous one.
 type ContextMenuHook[T comparable] func(bind Binder[T], elem *jaws.Element, click jaws.Click) (err error)
 
+// KeyHook is a function to call when a key event is received.
+//
+// The [Binder] locks are not held when the function is called.
+//
+// Like [GetHTMLHook] and unlike [GetHook] and [SetHook], the bind argument is the
+// current Binder (the one whose hook is being invoked), not the previous one.
+type KeyHook[T comparable] func(bind Binder[T], elem *jaws.Element, key jaws.Key) (err error)
+
 // InitialHTMLAttrHook is a function to call when an Element is initially rendered.
 //
 // The lock will be held at this point, preferring RLock over Lock, if available.
@@ -99,6 +107,7 @@ type Binder[T comparable] interface {
 	tag.TagGetter
 	jaws.ClickHandler
 	jaws.ContextMenuHandler
+	jaws.KeyHandler
 	jaws.InitialHTMLAttrHandler
 
 	// JawsGetLocked returns the bound value while the Binder lock is held.
@@ -120,6 +129,15 @@ type Binder[T comparable] interface {
 	// panic unless T is strictly comparable.
 	JawsSetLocked(elem *jaws.Element, value T) (err error)
 
+	// JawsLocker returns the [RWLocker] shared by every Binder in the chain,
+	// so callers can tell which Binders the same lock protects.
+	JawsLocker() RWLocker
+
+	// JawsSucceeded calls this chain's [SuccessHook]s, as [Setter.JawsSet]
+	// does after [Binder.JawsSetLocked] succeeds. Callers that store values
+	// with JawsSetLocked call it once they have released the lock.
+	JawsSucceeded(elem *jaws.Element) (err error)
+
 	// JawsInitialHTMLAttrLocked returns the initial HTML attribute while the
 	// Binder lock is held.
 	//
@@ -200,6 +218,12 @@ type Binder[T comparable] interface {
 	// The [Binder] locks are not held when the function is called.
 	ContextMenu(fn ContextMenuHook[T]) (newbind Binder[T])
 
+	// Key returns a [Binder] that will call fn when
+	// [jaws.KeyHandler.JawsKey] is invoked.
+	//
+	// The [Binder] locks are not held when the function is called.
+	Key(fn KeyHook[T]) (newbind Binder[T])
+
 	// InitialHTMLAttr returns a [Binder] that will call fn when
 	// [jaws.InitialHTMLAttrHandler.JawsInitialHTMLAttr] is invoked.
 	//
@@ -320,6 +344,14 @@ func (b *binder[T]) JawsSet(elem *jaws.Element, value T) (err error) {
 	return
 }
 
+func (b *binder[T]) JawsLocker() RWLocker {
+	return b.RWLocker
+}
+
+func (b *binder[T]) JawsSucceeded(elem *jaws.Element) error {
+	return b.callSuccessHooks(elem)
+}
+
 func (b *binder[T]) JawsGetTag() any {
 	return b.ptr
 }
@@ -352,6 +384,20 @@ func (b *binder[T]) JawsContextMenu(elem *jaws.Element, click jaws.Click) (err e
 	return
 }
 
+func (b *binder[T]) JawsKey(elem *jaws.Element, key jaws.Key) (err error) {
+	err = jaws.ErrEventUnhandled
+	for b != nil {
+		if fn, ok := b.hook.(KeyHook[T]); ok {
+			err = fn(b, elem, key)
+			if !errors.Is(err, jaws.ErrEventUnhandled) {
+				break
+			}
+		}
+		b = b.prev
+	}
+	return
+}
+
 // with returns a new [Binder] chained onto b that applies hook.
 //
 // Every chain constructor shares this single allocation point, so the new binder
@@ -390,6 +436,11 @@ func (b *binder[T]) ContextMenu(fn ContextMenuHook[T]) Binder[T] {
 	return b.with(fn)
 }
 
+// Key implements [Binder.Key].
+func (b *binder[T]) Key(fn KeyHook[T]) Binder[T] {
+	return b.with(fn)
+}
+
 // InitialHTMLAttr implements [Binder.InitialHTMLAttr].
 func (b *binder[T]) InitialHTMLAttr(fn InitialHTMLAttrHook[T]) Binder[T] {
 	return b.with(fn)
@@ -0,0 +1,72 @@
+package bind
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/linkdata/jaws"
+)
+
+// SliceSetter exposes and updates a set of values for a [jaws.Element].
+//
+// It is the counterpart of [Setter] for multiple-selection widgets, since
+// slices are not comparable and cannot be a Setter's value type. The order of
+// the values is not significant to those widgets.
+type SliceSetter[T comparable] interface {
+	// JawsGetSlice returns the current values. The caller may modify the
+	// returned slice.
+	JawsGetSlice(elem *jaws.Element) []T
+	// JawsSetSlice replaces the values with a copy of values. It may return
+	// [jaws.ErrValueUnchanged] to indicate the values were already set.
+	JawsSetSlice(elem *jaws.Element, values []T) (err error)
+	// JawsUpdateSlice replaces the values with the result of calling fn with a
+	// copy of the current values, as one atomic step with respect to the other
+	// methods. It may return [jaws.ErrValueUnchanged] to indicate fn returned
+	// the values already set.
+	JawsUpdateSlice(elem *jaws.Element, fn func(values []T) []T) (err error)
+}
+
+// NewSlice returns a [SliceSetter] with l protecting the slice pointed to by p.
+//
+// If l implements [RWLocker], reads use its read lock. Otherwise reads and
+// writes both use l. The pointer p is also exposed as the UI tag.
+func NewSlice[T comparable](l sync.Locker, p *[]T) SliceSetter[T] {
+	return &sliceBinder[T]{RWLocker: AsRWLocker(l), ptr: p}
+}
+
+type sliceBinder[T comparable] struct {
+	RWLocker
+	ptr *[]T
+}
+
+func (b *sliceBinder[T]) JawsGetSlice(elem *jaws.Element) (values []T) {
+	b.RLock()
+	values = slices.Clone(*b.ptr)
+	b.RUnlock()
+	return
+}
+
+func (b *sliceBinder[T]) JawsSetSlice(elem *jaws.Element, values []T) (err error) {
+	b.Lock()
+	defer b.Unlock()
+	if slices.Equal(*b.ptr, values) {
+		return jaws.ErrValueUnchanged
+	}
+	*b.ptr = slices.Clone(values)
+	return
+}
+
+func (b *sliceBinder[T]) JawsUpdateSlice(elem *jaws.Element, fn func(values []T) []T) (err error) {
+	b.Lock()
+	defer b.Unlock()
+	values := fn(slices.Clone(*b.ptr))
+	if slices.Equal(*b.ptr, values) {
+		return jaws.ErrValueUnchanged
+	}
+	*b.ptr = slices.Clone(values)
+	return
+}
+
+func (b *sliceBinder[T]) JawsGetTag() any {
+	return b.ptr
+}
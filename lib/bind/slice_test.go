@@ -0,0 +1,46 @@
+package bind
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/tag"
+)
+
+func TestNewSlice(t *testing.T) {
+	var mu sync.RWMutex
+	values := []string{"a"}
+	s := NewSlice(&mu, &values)
+
+	got := s.JawsGetSlice(nil)
+	got[0] = "changed"
+	if values[0] != "a" {
+		t.Error("JawsGetSlice returned the backing slice")
+	}
+	in := []string{"b", "c"}
+	if err := s.JawsSetSlice(nil, in); err != nil {
+		t.Fatal(err)
+	}
+	in[0] = "changed"
+	if !slices.Equal(values, []string{"b", "c"}) {
+		t.Errorf("values %q", values)
+	}
+	if err := s.JawsSetSlice(nil, []string{"b", "c"}); !errors.Is(err, jaws.ErrValueUnchanged) {
+		t.Errorf("unchanged set: %v", err)
+	}
+	if err := s.JawsUpdateSlice(nil, func(v []string) []string { return append(v, "d") }); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(values, []string{"b", "c", "d"}) {
+		t.Errorf("updated values %q", values)
+	}
+	if err := s.JawsUpdateSlice(nil, func(v []string) []string { return v }); !errors.Is(err, jaws.ErrValueUnchanged) {
+		t.Errorf("unchanged update: %v", err)
+	}
+	if gotTag := s.(tag.TagGetter).JawsGetTag(); gotTag != &values {
+		t.Errorf("tag %v", gotTag)
+	}
+}
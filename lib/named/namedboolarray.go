@@ -21,7 +21,10 @@ type BoolArray struct {
 	data  []*Bool
 }
 
-var _ SelectHandler = (*BoolArray)(nil)
+var (
+	_ SelectHandler      = (*BoolArray)(nil)
+	_ MultiSelectHandler = (*BoolArray)(nil)
+)
 
 // NewBoolArray returns an empty [BoolArray].
 //
@@ -238,3 +241,71 @@ func (nba *BoolArray) JawsSet(elem *jaws.Element, name string) (err error) {
 	elem.Dirty(nba)
 	return
 }
+
+// JawsGetSlice returns the distinct names of the checked [Bool] values, in
+// array order.
+func (nba *BoolArray) JawsGetSlice(elem *jaws.Element) (names []string) {
+	nba.mu.RLock()
+	names = nba.checkedNamesLocked()
+	nba.mu.RUnlock()
+	return
+}
+
+// JawsSetSlice checks exactly the [Bool] values whose names are in names, and
+// dirties the changed Bool values and nba itself.
+//
+// In single-select mode only the first of names that matches a Bool is
+// checked. It returns [jaws.ErrValueUnchanged] if no checked state changes.
+func (nba *BoolArray) JawsSetSlice(elem *jaws.Element, names []string) (err error) {
+	return nba.JawsUpdateSlice(elem, func([]string) []string { return names })
+}
+
+// JawsUpdateSlice calls fn with the names [BoolArray.JawsGetSlice] returns and
+// stores its result as [BoolArray.JawsSetSlice] does, holding the array's lock
+// throughout.
+func (nba *BoolArray) JawsUpdateSlice(elem *jaws.Element, fn func(names []string) []string) (err error) {
+	var changed []*Bool
+	nba.mu.Lock()
+	names := fn(nba.checkedNamesLocked())
+	if !nba.multi {
+		names = nba.firstMatchLocked(names)
+	}
+	for _, nb := range nba.data {
+		if nb.Set(slices.Contains(names, nb.Name())) {
+			changed = append(changed, nb)
+		}
+	}
+	nba.mu.Unlock()
+	if len(changed) == 0 {
+		return jaws.ErrValueUnchanged
+	}
+	for _, nb := range changed {
+		elem.Dirty(nb)
+	}
+	elem.Dirty(nba)
+	return
+}
+
+// checkedNamesLocked returns the distinct names of the checked [Bool] values.
+// The BoolArray must be locked.
+func (nba *BoolArray) checkedNamesLocked() (names []string) {
+	for _, nb := range nba.data {
+		if nb.Checked() && !slices.Contains(names, nb.Name()) {
+			names = append(names, nb.Name())
+		}
+	}
+	return
+}
+
+// firstMatchLocked returns the first of names that matches a [Bool], if any.
+// The BoolArray must be locked.
+func (nba *BoolArray) firstMatchLocked(names []string) []string {
+	for _, name := range names {
+		for _, nb := range nba.data {
+			if nb.Name() == name {
+				return []string{name}
+			}
+		}
+	}
+	return nil
+}
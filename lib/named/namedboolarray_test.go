@@ -304,3 +304,38 @@ func TestNamedBoolOption_UpdateQueuesLiveSelectedValue(t *testing.T) {
 		}
 	}
 }
+
+func TestBoolArray_SliceSetter(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	e := rq.NewElement(noopUI{})
+
+	nba := NewBoolArray(true).Add("a", "A").Add("b", "B").Add("c", "C").Add("a", "A2")
+	if err := nba.JawsSetSlice(e, []string{"c", "a", "missing"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := nba.JawsGetSlice(e); !slices.Equal(got, []string{"a", "c"}) {
+		t.Errorf("JawsGetSlice=%q want [a c]", got)
+	}
+	if err := nba.JawsSetSlice(e, []string{"a", "c"}); !errors.Is(err, jaws.ErrValueUnchanged) {
+		t.Errorf("unchanged JawsSetSlice: %v", err)
+	}
+	if err := nba.JawsSetSlice(e, nil); err != nil || nba.JawsGetSlice(e) != nil {
+		t.Errorf("clearing: %v, %q", err, nba.JawsGetSlice(e))
+	}
+
+	if err := nba.JawsUpdateSlice(e, func(names []string) []string { return append(names, "b") }); err != nil {
+		t.Fatal(err)
+	}
+	if got := nba.JawsGetSlice(e); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("updated JawsGetSlice=%q want [b]", got)
+	}
+
+	// Single-select keeps only the first matching name.
+	single := NewBoolArray(false).Add("a", "A").Add("b", "B")
+	if err := single.JawsSetSlice(e, []string{"missing", "b", "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := single.JawsGetSlice(e); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("single JawsGetSlice=%q want [b]", got)
+	}
+}
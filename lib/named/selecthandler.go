@@ -14,3 +14,13 @@ type SelectHandler interface {
 	jaws.Container
 	bind.Setter[string]
 }
+
+// MultiSelectHandler renders select options and stores the selection as the
+// set of selected option values.
+//
+// Rendered option values must be non-empty. Values that match no rendered
+// option are ignored. [BoolArray] is the standard implementation.
+type MultiSelectHandler interface {
+	jaws.Container
+	bind.SliceSetter[string]
+}
@@ -0,0 +1,344 @@
+package ui
+
+import (
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+	"github.com/linkdata/jaws/lib/htmlio"
+)
+
+// Suggestion is one entry in the suggestion list of an [Autocomplete].
+type Suggestion[T comparable] struct {
+	Label string // Label is shown in the list, and becomes the input text when chosen.
+	Value T      // Value is stored in the Autocomplete's value setter when chosen.
+}
+
+// Suggester provides the suggestions of an [Autocomplete].
+type Suggester[T comparable] interface {
+	// JawsSuggest returns the suggestions for text, the current input text. It
+	// is called on the event goroutine once the user pauses typing, and when the
+	// user presses ArrowDown with no suggestions shown.
+	JawsSuggest(elem *jaws.Element, text string) []Suggestion[T]
+}
+
+// Autocomplete renders a text input with a list of suggestions from a
+// [Suggester].
+//
+// The input text is bound to a string setter like [Text], and is sent once the
+// user pauses typing. Each edit asks the Suggester for suggestions, which are
+// shown in a listbox below the input. ArrowDown and ArrowUp move through the
+// list, Enter or a click chooses a suggestion and Escape closes the list, as
+// does leaving the input. Enter is only sent to the server while a suggestion
+// is highlighted, so otherwise it submits an enclosing form as usual. Choosing a suggestion sets the input text to its
+// Label and, if the Autocomplete has a value setter, sets that to its Value.
+// Editing the text sets the value setter to the zero T, so it only holds a
+// value the user chose.
+//
+// The list is an HTML ul with the jaws-autocomplete class from jaws.css,
+// rendered right after the input. An Autocomplete value must back at most one
+// live [jaws.Element].
+type Autocomplete[T comparable] struct {
+	InputText
+	suggester Suggester[T]
+	value     bind.Setter[T]
+}
+
+// autocompleteDebounce is how long the user must pause typing before the
+// client sends the text.
+const autocompleteDebounce = 200 * time.Millisecond
+
+// autocompleteKeys are the keys the input sends, with and without a
+// highlighted suggestion.
+const (
+	autocompleteKeys       = "ArrowDown ArrowUp Escape"
+	autocompleteKeysActive = "ArrowDown ArrowUp Enter Escape"
+)
+
+// NewAutocomplete returns an autocomplete input bound to text, with suggestions
+// from suggester. If value is not nil, it receives the Value of the chosen
+// suggestion.
+//
+// For writable use, text must provide the setter-derived dirty target
+// described by [Input].
+func NewAutocomplete[T comparable](text bind.Setter[string], suggester Suggester[T], value bind.Setter[T]) *Autocomplete[T] {
+	return &Autocomplete[T]{InputText: InputText{Setter: text}, suggester: suggester, value: value}
+}
+
+// autocompleteState is the per-Element suggestion list an Autocomplete claims
+// while rendering.
+type autocompleteState struct {
+	elem     *jaws.Element // the input's Element
+	valueTag any           // dirty target of the value setter
+	mu       sync.Mutex
+	list     *jaws.Element
+	labels   []string
+	values   []any
+	active   int // index of the highlighted suggestion, or -1
+	shown    template.HTML
+	open     bool
+	enter    bool // whether the input sends Enter
+}
+
+// autocompleteChooser is implemented by every Autocomplete instantiation.
+type autocompleteChooser interface {
+	choose(elem *jaws.Element, i int) error
+}
+
+// JawsRender renders u as an HTML text input followed by its suggestion list.
+//
+// If elem's widget state is occupied, JawsRender returns
+// [jaws.ErrElementStateClaimed] without rendering.
+func (u *Autocomplete[T]) JawsRender(elem *jaws.Element, w io.Writer, params []any) (err error) {
+	st := &autocompleteState{elem: elem, active: -1}
+	if err = jaws.SetElementState(elem, st); err != nil {
+		return
+	}
+	if u.value != nil {
+		st.valueTag = elem.ApplyGetter(u.value)
+	}
+	list := elem.Request.NewElement(autocompleteList{st: st, chooser: u})
+	st.mu.Lock()
+	st.list = list
+	st.mu.Unlock()
+	// Caller params come first, so the HTML parser keeps theirs on conflict.
+	params = append(params[:len(params):len(params)],
+		template.HTMLAttr(`role="combobox"`),
+		template.HTMLAttr(`aria-autocomplete="list"`),
+		template.HTMLAttr(`aria-expanded="false"`),
+		template.HTMLAttr(string(list.Jid().AppendQuote([]byte("aria-controls=")))), // #nosec G203
+		template.HTMLAttr(`autocomplete="off"`),
+		template.HTMLAttr(`data-jawskeydown="`+autocompleteKeys+`"`),
+		jaws.InputDebounce(autocompleteDebounce),
+		jaws.ForwardDOMEvents("blur:debounce=200"),
+	)
+	if err = u.renderStringInput(elem, w, "text", params...); err == nil {
+		err = list.JawsRender(w, nil)
+	}
+	if err != nil {
+		deleteOwnedElements(elem.Request, st.takeOwnedElements())
+	}
+	return
+}
+
+// JawsInput stores the input text, clears the value setter and updates the
+// suggestions.
+func (u *Autocomplete[T]) JawsInput(elem *jaws.Element, value string) (err error) {
+	if err = u.InputText.JawsInput(elem, value); err == nil {
+		if u.value != nil {
+			var zero T
+			err = applyDirty(autocompleteValueTag(elem), elem, u.value.JawsSet(elem, zero))
+		}
+		u.suggest(elem, value)
+	}
+	return
+}
+
+// JawsKey moves through, chooses from or closes the suggestion list. Enter
+// with no highlighted suggestion is left unhandled.
+func (u *Autocomplete[T]) JawsKey(elem *jaws.Element, key jaws.Key) (err error) {
+	err = jaws.ErrEventUnhandled
+	if st := autocompleteStateOf(elem); st != nil && !key.Up {
+		err = nil
+		switch key.Key {
+		case "ArrowDown", "ArrowUp":
+			// Positions cycle through the suggestions and back to none.
+			st.mu.Lock()
+			n := len(st.labels)
+			pos := st.active + 1
+			if key.Key == "ArrowDown" {
+				pos = (pos + 1) % (n + 1)
+			} else {
+				pos = (pos + n) % (n + 1)
+			}
+			st.active = pos - 1
+			st.mu.Unlock()
+			if n == 0 && key.Key == "ArrowDown" {
+				u.suggest(elem, u.JawsGet(elem))
+			} else {
+				st.dirty(elem)
+			}
+		case "Enter":
+			st.mu.Lock()
+			active := st.active
+			st.mu.Unlock()
+			if active < 0 {
+				err = jaws.ErrEventUnhandled
+			} else {
+				err = u.choose(elem, active)
+			}
+		case "Escape":
+			st.close(elem)
+		default:
+			err = jaws.ErrEventUnhandled
+		}
+	}
+	return
+}
+
+// JawsDOMEvent closes the suggestion list when the input loses focus.
+func (u *Autocomplete[T]) JawsDOMEvent(elem *jaws.Element, ev jaws.DOMEvent) (err error) {
+	err = jaws.ErrEventUnhandled
+	if st := autocompleteStateOf(elem); st != nil && ev.Type == "blur" {
+		st.close(elem)
+		err = nil
+	}
+	return
+}
+
+// suggest replaces the suggestions with those for text.
+func (u *Autocomplete[T]) suggest(elem *jaws.Element, text string) {
+	if st := autocompleteStateOf(elem); st != nil {
+		var labels []string
+		var values []any
+		for _, s := range u.suggester.JawsSuggest(elem, text) {
+			labels = append(labels, s.Label)
+			values = append(values, s.Value)
+		}
+		st.mu.Lock()
+		st.labels, st.values, st.active = labels, values, -1
+		st.mu.Unlock()
+		st.dirty(elem)
+	}
+}
+
+// choose commits suggestion i, if it exists, and closes the list.
+func (u *Autocomplete[T]) choose(elem *jaws.Element, i int) (err error) {
+	if st := autocompleteStateOf(elem); st != nil {
+		var label string
+		var value any
+		st.mu.Lock()
+		ok := i >= 0 && i < len(st.labels)
+		if ok {
+			label, value = st.labels[i], st.values[i]
+		}
+		st.mu.Unlock()
+		st.close(elem)
+		if ok {
+			if err = u.maybeDirty(elem, u.Setter.JawsSet(elem, label)); err == nil && u.value != nil {
+				v, _ := value.(T) // a nil interface Value is stored as nil
+				err = applyDirty(st.valueTag, elem, u.value.JawsSet(elem, v))
+			}
+		}
+	}
+	return
+}
+
+func autocompleteStateOf(elem *jaws.Element) (st *autocompleteState) {
+	st, _ = jaws.ElementState(elem).(*autocompleteState)
+	return
+}
+
+// autocompleteValueTag returns the value setter's dirty target for elem.
+func autocompleteValueTag(elem *jaws.Element) (tag any) {
+	if st := autocompleteStateOf(elem); st != nil {
+		tag = st.valueTag
+	}
+	return
+}
+
+// close removes the suggestions.
+func (st *autocompleteState) close(elem *jaws.Element) {
+	st.mu.Lock()
+	st.labels, st.values, st.active = nil, nil, -1
+	st.mu.Unlock()
+	st.dirty(elem)
+}
+
+// dirty marks the list dirty so it shows the current suggestions.
+func (st *autocompleteState) dirty(elem *jaws.Element) {
+	st.mu.Lock()
+	list := st.list
+	st.mu.Unlock()
+	if list != nil {
+		elem.Dirty(list)
+	}
+}
+
+// takeOwnedElements returns the list's Element and forgets it, transferring
+// responsibility for unregistering it to the caller.
+func (st *autocompleteState) takeOwnedElements() (owned []*jaws.Element) {
+	st.mu.Lock()
+	if st.list != nil {
+		owned = append(owned, st.list)
+		st.list = nil
+	}
+	st.mu.Unlock()
+	return
+}
+
+// autocompleteList is the suggestion list of an Autocomplete.
+type autocompleteList struct {
+	st      *autocompleteState
+	chooser autocompleteChooser
+}
+
+func (u autocompleteList) html() (html template.HTML, open, enter bool) {
+	var sb strings.Builder
+	u.st.mu.Lock()
+	defer u.st.mu.Unlock()
+	for i, label := range u.st.labels {
+		sb.WriteString(`<li role="option" name="` + strconv.Itoa(i) + `"`)
+		if i == u.st.active {
+			sb.WriteString(` class="active" aria-selected="true"`)
+		}
+		sb.WriteString(">" + template.HTMLEscapeString(label) + "</li>")
+	}
+	return template.HTML(sb.String()), len(u.st.labels) > 0, u.st.active >= 0 // #nosec G203
+}
+
+func (u autocompleteList) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	attrs := append(elem.ApplyParams(params), `role="listbox"`, `class="jaws-autocomplete"`, "hidden")
+	return htmlio.WriteHTMLInner(w, elem.Jid(), "ul", "", "", attrs...)
+}
+
+// JawsUpdate shows the current suggestions, hiding the list if there are none,
+// and has the input send Enter only while a suggestion is highlighted.
+func (u autocompleteList) JawsUpdate(elem *jaws.Element) {
+	html, open, enter := u.html()
+	u.st.mu.Lock()
+	htmlChanged := u.st.shown != html
+	openChanged := u.st.open != open
+	enterChanged := u.st.enter != enter
+	u.st.shown, u.st.open, u.st.enter = html, open, enter
+	u.st.mu.Unlock()
+	if htmlChanged {
+		elem.SetInner(html)
+	}
+	if openChanged {
+		if open {
+			elem.RemoveAttr("hidden")
+		} else {
+			elem.SetAttr("hidden", "")
+		}
+		u.st.elem.SetAttr("aria-expanded", strconv.FormatBool(open))
+	}
+	if enterChanged {
+		keys := autocompleteKeys
+		if enter {
+			keys = autocompleteKeysActive
+		}
+		u.st.elem.SetAttr("data-jawskeydown", keys)
+	}
+}
+
+// JawsClick chooses the clicked suggestion.
+func (u autocompleteList) JawsClick(elem *jaws.Element, click jaws.Click) (err error) {
+	err = jaws.ErrEventUnhandled
+	if i, e := strconv.Atoi(click.Name); e == nil {
+		err = u.chooser.choose(u.st.elem, i)
+	}
+	return
+}
+
+// Autocomplete renders an autocomplete text input bound to text, with
+// suggestions from suggester. Use [NewAutocomplete] with [RequestWriter.NewUI]
+// to also receive the chosen suggestion's typed Value.
+func (rw RequestWriter) Autocomplete(text any, suggester Suggester[string], params ...any) error {
+	return rw.NewUI(NewAutocomplete(bind.MakeSetter[string](text), suggester, nil), params...)
+}
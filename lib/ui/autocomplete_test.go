@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/what"
+)
+
+type testSuggester []string
+
+func (s testSuggester) JawsSuggest(elem *jaws.Element, text string) (suggestions []Suggestion[int]) {
+	if text != "" {
+		for i, label := range s {
+			if strings.HasPrefix(label, text) {
+				suggestions = append(suggestions, Suggestion[int]{Label: label, Value: i + 1})
+			}
+		}
+	}
+	return
+}
+
+func TestAutocomplete(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	text := newTestSetter("")
+	value := newTestSetter(7)
+	fruits := testSuggester{"apple", "apricot", "banana", "a<b>"}
+	elem, got := renderUI(t, rq, NewAutocomplete[int](text, fruits, value), `placeholder="Fruit"`)
+	mustMatch(t, `^<input id="Jid\.[0-9]+" type="text" placeholder="Fruit" role="combobox" aria-autocomplete="list" aria-expanded="false" aria-controls="Jid\.([0-9]+)" autocomplete="off" data-jawskeydown="ArrowDown ArrowUp Escape" data-jawsinput="debounce=200" data-jawsevents="blur:debounce=200">`+
+		`<ul id="Jid\.[0-9]+" role="listbox" class="jaws-autocomplete" hidden></ul>$`, got)
+
+	st := jaws.ElementState(elem).(*autocompleteState)
+	list := st.list
+	event := func(wht what.What, data string) error {
+		t.Helper()
+		target := elem
+		if wht == what.Click {
+			target = list
+		}
+		err := jaws.CallEventHandlers(target.UI(), target, wht, data)
+		list.JawsUpdate()
+		return err
+	}
+	key := func(name string) {
+		t.Helper()
+		if err := event(what.KeyDown, "0 "+name+" "+name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Typing clears the value and shows matching suggestions.
+	if err := event(what.Input, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if text.Get() != "a" || value.Get() != 0 {
+		t.Errorf("text %q value %d", text.Get(), value.Get())
+	}
+	if !st.open || st.shown != `<li role="option" name="0">apple</li><li role="option" name="1">apricot</li><li role="option" name="2">a&lt;b&gt;</li>` {
+		t.Errorf("open %v shown %q", st.open, st.shown)
+	}
+
+	// Arrow keys move the highlight, wrapping through no highlight.
+	key("ArrowDown")
+	key("ArrowDown")
+	if st.active != 1 || !strings.Contains(string(st.shown), `<li role="option" name="1" class="active" aria-selected="true">apricot</li>`) {
+		t.Errorf("active %d shown %q", st.active, st.shown)
+	}
+	key("ArrowUp")
+	key("ArrowUp")
+	key("ArrowUp")
+	if st.active != 2 {
+		t.Errorf("active %d after wrapping up", st.active)
+	}
+
+	// Enter chooses the highlighted suggestion and closes the list.
+	key("ArrowUp")
+	if !st.enter {
+		t.Error("Enter not sent with a highlighted suggestion")
+	}
+	key("Enter")
+	if st.enter {
+		t.Error("Enter sent with the list closed")
+	}
+	if text.Get() != "apricot" || value.Get() != 2 || st.open || len(st.labels) != 0 {
+		t.Errorf("text %q value %d open %v", text.Get(), value.Get(), st.open)
+	}
+
+	// ArrowDown reopens the list for the current text; Escape closes it.
+	if err := event(what.Input, "ban"); err != nil {
+		t.Fatal(err)
+	}
+	key("Escape")
+	if st.open {
+		t.Error("open after Escape")
+	}
+	key("ArrowDown")
+	if !st.open || len(st.labels) != 1 {
+		t.Errorf("ArrowDown did not reopen: %q", st.shown)
+	}
+	if err := event(what.DOMEvent, `{"type":"blur"}`); err != nil || st.open {
+		t.Errorf("blur: %v, open %v", err, st.open)
+	}
+
+	// Clicking a suggestion chooses it.
+	if err := event(what.Input, "ap"); err != nil {
+		t.Fatal(err)
+	}
+	if err := event(what.Click, "0 0 0 0"); err != nil {
+		t.Fatal(err)
+	}
+	if text.Get() != "apple" || value.Get() != 1 {
+		t.Errorf("clicked: text %q value %d", text.Get(), value.Get())
+	}
+	if err := event(what.Click, "0 0 0 x"); err != jaws.ErrEventUnhandled {
+		t.Errorf("click outside suggestions: %v", err)
+	}
+	if err := event(what.KeyDown, "0 Enter Enter"); err != jaws.ErrEventUnhandled {
+		t.Errorf("Enter without a highlighted suggestion: %v", err)
+	}
+	if err := event(what.KeyDown, "0 KeyA a"); err != jaws.ErrEventUnhandled {
+		t.Errorf("other key: %v", err)
+	}
+
+	deleteOwnedElements(rq, []*jaws.Element{elem})
+	if !list.Deleted() {
+		t.Error("list not deleted")
+	}
+}
+
+func TestAutocomplete_ConcurrentRender(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	ac := NewAutocomplete[int](newTestSetter(""), testSuggester{"apple"}, newTestSetter(0))
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			elem := rq.NewElement(ac)
+			if err := elem.JawsRender(io.Discard, nil); err != nil {
+				t.Error(err)
+			}
+			if autocompleteValueTag(elem) == nil {
+				t.Error("no value tag")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestAutocomplete_RequestWriter(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	var sb strings.Builder
+	rw := RequestWriter{Request: rq, Writer: &sb}
+	if err := rw.Autocomplete(newTestSetter("x"), testStringSuggester{}); err != nil {
+		t.Fatal(err)
+	}
+	mustMatch(t, `^<input id="Jid\.[0-9]+" type="text" value="x" role="combobox".*</ul>$`, sb.String())
+}
+
+type testStringSuggester struct{}
+
+func (testStringSuggester) JawsSuggest(elem *jaws.Element, text string) []Suggestion[string] {
+	return []Suggestion[string]{{Label: text, Value: text}}
+}
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+	"github.com/linkdata/jaws/lib/named"
+)
+
+// CheckboxElement renders the input and label elements for one checkbox option.
+//
+// Like [RadioElement], the underlying [jaws.Element] values are created lazily
+// on the first call to [CheckboxElement.Checkbox] or [CheckboxElement.Label].
+// Call each at most once, and render Label only when Checkbox is also rendered.
+type CheckboxElement struct {
+	st *checkboxState
+}
+
+// checkboxOption is the bound state and label of one checkbox in a group.
+type checkboxOption struct {
+	value bind.Setter[bool]
+	label bind.HTMLGetter
+}
+
+// checkboxOptioner is implemented by the non-BoolArray sources of a
+// CheckboxGroup.
+type checkboxOptioner interface {
+	checkboxOptions() []checkboxOption
+}
+
+// checkboxState is the lazily-populated state behind a CheckboxElement, touched
+// only on the rendering goroutine like radioState.
+type checkboxState struct {
+	rw       RequestWriter
+	opt      checkboxOption
+	checkbox *jaws.Element
+	label    *jaws.Element
+}
+
+// checkboxElem returns the checkbox Element, creating it on first use. See
+// radioState.radioElem.
+func (st *checkboxState) checkboxElem() *jaws.Element {
+	if st.checkbox == nil {
+		st.checkbox = st.rw.Request.NewElement(NewCheckbox(st.opt.value))
+		st.rw.trackElement(st.checkbox)
+	}
+	return st.checkbox
+}
+
+// Checkbox renders an HTML input element of type checkbox.
+//
+// Render errors are reported through [jaws.Jaws.MustLog], which panics when
+// no [jaws.Jaws.Logger] is configured.
+func (ce CheckboxElement) Checkbox(params ...any) template.HTML {
+	checkbox := ce.st.checkboxElem()
+	var sb strings.Builder
+	checkbox.Jaws.MustLog(checkbox.JawsRender(&sb, params))
+	return template.HTML(sb.String()) // #nosec G203
+}
+
+// Label renders an HTML label element.
+//
+// The generated for= attribute referencing the checkbox's id takes precedence
+// over any for= passed in params.
+//
+// Render errors are reported through [jaws.Jaws.MustLog], which panics when
+// no [jaws.Jaws.Logger] is configured.
+func (ce CheckboxElement) Label(params ...any) template.HTML {
+	checkbox := ce.st.checkboxElem()
+	if ce.st.label == nil {
+		ce.st.label = ce.st.rw.Request.NewElement(NewLabel(ce.st.opt.label))
+		ce.st.rw.trackElement(ce.st.label)
+	}
+	var sb strings.Builder
+	forAttr := string(checkbox.Jid().AppendQuote([]byte("for=")))
+	ce.st.label.Jaws.MustLog(ce.st.label.JawsRender(&sb, append([]any{forAttr}, params...)))
+	return template.HTML(sb.String()) // #nosec G203
+}
+
+// CheckboxGroup returns a [CheckboxElement] for each option in options.
+//
+// options is either a multi-select [named.BoolArray], whose checkboxes bind its
+// [named.Bool] values, or a [Choices], whose checkboxes add and remove their
+// Value in its bound slice. Each checkbox sends its own checked state, and
+// every widget bound to the same set updates when it changes. CheckboxGroup
+// panics for any other type.
+//
+// Call CheckboxGroup from the [Template] that renders the returned
+// [CheckboxElement] values, as for [RequestWriter.RadioGroup].
+func (rw RequestWriter) CheckboxGroup(options any) (cel []CheckboxElement) {
+	var opts []checkboxOption
+	switch v := options.(type) {
+	case *named.BoolArray:
+		v.ReadLocked(func(nbl []*named.Bool) {
+			for _, nb := range nbl {
+				opts = append(opts, checkboxOption{value: nb, label: nb})
+			}
+		})
+	case checkboxOptioner:
+		opts = v.checkboxOptions()
+	default:
+		panic(fmt.Errorf("expected *named.BoolArray or *ui.Choices, not %T", options))
+	}
+	for _, opt := range opts {
+		cel = append(cel, CheckboxElement{st: &checkboxState{rw: rw, opt: opt}})
+	}
+	return
+}
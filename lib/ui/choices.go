@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"html/template"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+	"github.com/linkdata/jaws/lib/htmlio"
+	"github.com/linkdata/jaws/lib/named"
+)
+
+// Choice is one option of a [Choices].
+type Choice[T comparable] struct {
+	Value T             // Value is in the bound slice while the option is selected.
+	Label template.HTML // Label is trusted HTML shown for the option.
+}
+
+// Choices offers a fixed list of options for a set of values bound to a
+// [bind.SliceSetter], for use with [MultiSelect] and
+// [RequestWriter.CheckboxGroup].
+//
+// An option is selected while its Value is in the bound slice. A MultiSelect
+// edit stores the selected Values in option order, dropping bound values that
+// match no option, while a checkbox edit adds or removes only its own Value.
+// The bound slice's tag is the dirty target of every widget using the Choices.
+type Choices[T comparable] struct {
+	value   bind.SliceSetter[T]
+	choices []Choice[T]
+}
+
+var _ named.MultiSelectHandler = (*Choices[int])(nil)
+
+// NewChoices returns a Choices offering choices for the values in value.
+func NewChoices[T comparable](value bind.SliceSetter[T], choices ...Choice[T]) *Choices[T] {
+	return &Choices[T]{value: value, choices: slices.Clone(choices)}
+}
+
+// JawsContains returns the option widgets for a select backed by c. Option
+// values are the option indexes.
+func (c *Choices[T]) JawsContains(elem *jaws.Element) (contents []jaws.UI) {
+	for i := range c.choices {
+		contents = append(contents, choiceOption[T]{c: c, i: i})
+	}
+	return
+}
+
+// JawsGetSlice returns the option values of the selected options.
+func (c *Choices[T]) JawsGetSlice(elem *jaws.Element) (names []string) {
+	return c.namesOf(c.value.JawsGetSlice(elem))
+}
+
+// JawsSetSlice stores the Values of the options named by names.
+func (c *Choices[T]) JawsSetSlice(elem *jaws.Element, names []string) error {
+	return c.value.JawsSetSlice(elem, c.valuesOf(names))
+}
+
+// JawsUpdateSlice calls fn with the option values of the selected options and
+// stores the Values of the options its result names, using the bound slice's
+// JawsUpdateSlice.
+func (c *Choices[T]) JawsUpdateSlice(elem *jaws.Element, fn func(names []string) []string) error {
+	return c.value.JawsUpdateSlice(elem, func(values []T) []T {
+		return c.valuesOf(fn(c.namesOf(values)))
+	})
+}
+
+// namesOf returns the option values of the options whose Value is in values.
+func (c *Choices[T]) namesOf(values []T) (names []string) {
+	for i, ch := range c.choices {
+		if slices.Contains(values, ch.Value) {
+			names = append(names, strconv.Itoa(i))
+		}
+	}
+	return
+}
+
+// valuesOf returns the Values of the options named by names.
+func (c *Choices[T]) valuesOf(names []string) (values []T) {
+	for i, ch := range c.choices {
+		if slices.Contains(names, strconv.Itoa(i)) {
+			values = append(values, ch.Value)
+		}
+	}
+	return
+}
+
+// JawsGetTag returns the bound slice setter, so its tag is c's tag.
+func (c *Choices[T]) JawsGetTag() any {
+	return c.value
+}
+
+func (c *Choices[T]) checkboxOptions() (opts []checkboxOption) {
+	for i := range c.choices {
+		cb := choiceBool[T]{c: c, i: i}
+		opts = append(opts, checkboxOption{value: cb, label: cb})
+	}
+	return
+}
+
+// choiceOption is the option element for one Choice. It holds no Element state
+// and may back multiple live Elements.
+type choiceOption[T comparable] struct {
+	c *Choices[T]
+	i int
+}
+
+func (u choiceOption[T]) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	// As for named.RenderBoolOption, the canonical value comes first.
+	attrs := slices.Insert(elem.ApplyParams(params), 0, htmlio.Attr("value", strconv.Itoa(u.i)))
+	if (choiceBool[T])(u).JawsGet(elem) {
+		attrs = append(attrs, "selected")
+	}
+	return htmlio.WriteHTMLInner(w, elem.Jid(), "option", "", u.c.choices[u.i].Label, attrs...)
+}
+
+// JawsUpdate does nothing, since the owning MultiSelect sends the selection.
+func (u choiceOption[T]) JawsUpdate(elem *jaws.Element) {}
+
+// choiceBool binds whether one Choice is selected.
+type choiceBool[T comparable] struct {
+	c *Choices[T]
+	i int
+}
+
+func (u choiceBool[T]) JawsGet(elem *jaws.Element) bool {
+	return slices.Contains(u.c.value.JawsGetSlice(elem), u.c.choices[u.i].Value)
+}
+
+// JawsSet adds or removes the Choice's Value in one JawsUpdateSlice call on the
+// bound slice, so concurrent edits of other Choices are not lost.
+func (u choiceBool[T]) JawsSet(elem *jaws.Element, checked bool) error {
+	value := u.c.choices[u.i].Value
+	return u.c.value.JawsUpdateSlice(elem, func(values []T) []T {
+		if slices.Contains(values, value) == checked {
+			return values
+		}
+		if checked {
+			return append(values, value)
+		}
+		return slices.DeleteFunc(values, func(v T) bool { return v == value })
+	})
+}
+
+func (u choiceBool[T]) JawsGetHTML(elem *jaws.Element) template.HTML {
+	return u.c.choices[u.i].Label
+}
+
+func (u choiceBool[T]) JawsGetTag() any {
+	return u.c.value
+}
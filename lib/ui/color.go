@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+	"github.com/linkdata/jaws/lib/htmlio"
+)
+
+// RGB is an sRGB color, the value of a [Color] input.
+type RGB struct {
+	R, G, B uint8
+}
+
+// ErrInvalidRGB is returned by [ParseRGB] for text that is not a "#rrggbb" color.
+var ErrInvalidRGB = errors.New("invalid RGB color")
+
+// String returns c in the "#rrggbb" form used by HTML color inputs.
+func (c RGB) String() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// ParseRGB parses a "#rrggbb" color, accepting either letter case.
+func ParseRGB(text string) (c RGB, err error) {
+	err = fmt.Errorf("%w: %q", ErrInvalidRGB, text)
+	if len(text) == 7 && text[0] == '#' {
+		if n, e := strconv.ParseUint(text[1:], 16, 32); e == nil {
+			c = RGB{R: uint8(n >> 16), G: uint8(n >> 8), B: uint8(n)} // #nosec G115
+			err = nil
+		}
+	}
+	return
+}
+
+// Color renders an HTML color input bound to an [RGB] setter.
+//
+// A Color value must back at most one live [jaws.Element]. Construct distinct
+// Color values over the same setter to render one bound value more than once.
+//
+// Malformed browser text is rejected without calling the setter or returning an
+// error, and the canonical value is restored by updating only the originating
+// Element.
+type Color struct {
+	Input
+	bind.Setter[RGB]
+}
+
+// NewColor returns a color input widget bound to g.
+//
+// For writable use, g must provide the setter-derived dirty target described by [Input].
+func NewColor(g bind.Setter[RGB]) *Color { return &Color{Setter: g} }
+
+// JawsRender renders ui as an HTML color input.
+func (u *Color) JawsRender(elem *jaws.Element, w io.Writer, params []any) (err error) {
+	getterAttrs := u.applyGetterAttrs(elem, u.Setter)
+	attrs := append(elem.ApplyParams(params), getterAttrs...)
+	s := u.JawsGet(elem).String()
+	u.Last.Store(s)
+	err = htmlio.WriteHTMLInput(w, elem.Jid(), "color", s, attrs)
+	return
+}
+
+// JawsUpdate updates the input value when the bound color changes.
+func (u *Color) JawsUpdate(elem *jaws.Element) {
+	if s := u.JawsGet(elem).String(); u.Last.Swap(s) != s {
+		elem.SetValue(s)
+	}
+}
+
+// JawsInput settles a browser-side color edit.
+func (u *Color) JawsInput(elem *jaws.Element, text string) (err error) {
+	c, parseErr := ParseRGB(text)
+	// Rejected text is never produced by RGB.String, so the next update
+	// restores the value, as does an accepted uppercase spelling.
+	u.Last.Store(text)
+	if parseErr != nil {
+		elem.Dirty(elem)
+		return
+	}
+	err = u.Setter.JawsSet(elem, c)
+	if errors.Is(err, jaws.ErrValueUnchanged) {
+		elem.Dirty(elem)
+		err = nil
+		return
+	}
+	err = u.maybeDirty(elem, err)
+	return
+}
+
+// Color renders an HTML color input.
+func (rw RequestWriter) Color(value any, params ...any) error {
+	return rw.NewUI(NewColor(bind.MakeSetter[RGB](value)), params...)
+}
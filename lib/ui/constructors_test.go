@@ -22,11 +22,13 @@ func TestConstructors(t *testing.T) {
 	checked := false
 	num := 0.0
 	when := time.Now()
+	color := RGB{}
 
 	textSetter := bind.New(&mu, &txt)
 	boolSetter := bind.New(&mu, &checked)
 	numSetter := bind.New(&mu, &num)
 	timeSetter := bind.New(&mu, &when)
+	colorSetter := bind.New(&mu, &color)
 
 	htmlGetter := bind.MakeHTMLGetter("x")
 	imgGetter := bind.StringGetterFunc(func(*jaws.Element) string { return "img" })
@@ -37,23 +39,33 @@ func TestConstructors(t *testing.T) {
 		NewA(htmlGetter),
 		NewButton(htmlGetter),
 		NewCheckbox(boolSetter),
+		NewColor(colorSetter),
 		NewContainer("div", &tc),
 		NewDate(timeSetter),
+		NewDateTimeLocal(timeSetter, nil),
 		NewDiv(htmlGetter),
+		NewEmail(textSetter),
 		NewImg(imgGetter),
 		NewLabel(htmlGetter),
 		NewLi(htmlGetter),
+		NewMonth(timeSetter, time.UTC),
+		NewMultiSelect(nba),
 		NewNumber(numSetter),
 		NewPassword(textSetter),
 		NewRadio(boolSetter),
 		NewRange(numSetter),
+		NewSearch(textSetter),
 		NewSelect(nba),
 		NewSpan(htmlGetter),
 		NewTbody(&tc),
 		NewTd(htmlGetter),
+		NewTel(textSetter),
 		NewText(textSetter),
 		NewTextarea(textSetter),
+		NewTime(timeSetter, nil),
 		NewTr(htmlGetter),
+		NewURL(textSetter),
+		NewWeek(timeSetter, nil),
 	}
 	for i, ui := range all {
 		if ui == nil {
@@ -49,6 +49,23 @@ func (u Container) JawsRender(elem *jaws.Element, w io.Writer, params []any) err
 	return u.render(elem, w, params, nil)
 }
 
+// JawsOrder handles the user reordering u's children.
+//
+// If u's child provider implements [jaws.OrderHandler], the Container renders a
+// data-jawssortable attribute so the bundled client lets the user drag its
+// children, and JawsOrder passes the new order on to the provider, leaving out
+// any UI values that are not current children of elem. Whatever the provider
+// returns, elem is then marked dirty so the next update sends the provider's
+// order to the browser, correcting the dragged order if it was not accepted.
+func (u Container) JawsOrder(elem *jaws.Element, children []jaws.UI) (err error) {
+	err = jaws.ErrEventUnhandled
+	if h, ok := u.children.(jaws.OrderHandler); ok {
+		err = h.JawsOrder(elem, markReordered(elem, children))
+		elem.Dirty(elem)
+	}
+	return
+}
+
 // JawsUpdate reconciles u's direct children.
 //
 // If elem's widget state cannot be used, JawsUpdate reports
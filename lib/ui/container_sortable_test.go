@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/jawstest"
+	"github.com/linkdata/jaws/lib/what"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+type testSortableContainer struct {
+	mu       sync.Mutex
+	contents []jaws.UI
+	got      []jaws.UI
+	err      error
+}
+
+func (tc *testSortableContainer) JawsContains(*jaws.Element) []jaws.UI {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return append([]jaws.UI(nil), tc.contents...)
+}
+
+func (tc *testSortableContainer) JawsOrder(_ *jaws.Element, children []jaws.UI) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.got = children
+	if tc.err == nil {
+		tc.contents = children
+	}
+	return tc.err
+}
+
+func TestContainer_SortableReorder(t *testing.T) {
+	jw, err := jaws.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(jw.Close)
+	go jw.Serve()
+
+	tr := jawstest.NewTestRequest(jw, nil)
+	if tr == nil {
+		t.Fatal("expected test request")
+	}
+	defer tr.Close()
+	<-tr.ReadyCh
+
+	span1 := NewSpan(testHTMLGetter("span1"))
+	span2 := NewSpan(testHTMLGetter("span2"))
+	span3 := NewSpan(testHTMLGetter("span3"))
+	outsider := tr.NewElement(NewSpan(testHTMLGetter("outsider")))
+	tc := &testSortableContainer{contents: []jaws.UI{span1, span2, span3}}
+	elem := tr.NewElement(NewTbody(tc))
+	var sb strings.Builder
+	if err := elem.JawsRender(&sb, nil); err != nil {
+		t.Fatal(err)
+	}
+	mustMatch(t, `^<tbody id="Jid\.[0-9]+" data-jawssortable>`, sb.String())
+	jids := containerElements(t, elem)
+
+	waitOrder := func(want string) {
+		t.Helper()
+		deadline := time.After(3 * time.Second)
+		for {
+			select {
+			case msg := <-tr.OutCh:
+				if msg.What == what.Order {
+					if msg.Data != want {
+						t.Fatalf("Order %q, want %q", msg.Data, want)
+					}
+					return
+				}
+			case <-deadline:
+				t.Fatalf("no Order %q", want)
+			}
+		}
+	}
+
+	dragged := jids[2].Jid().String() + " " + outsider.Jid().String() + " " + jids[0].Jid().String() + " " + jids[1].Jid().String()
+	tr.InCh <- wire.WsMsg{Jid: elem.Jid(), What: what.Reorder, Data: dragged}
+	waitOrder(jids[2].Jid().String() + " " + jids[0].Jid().String() + " " + jids[1].Jid().String())
+	tc.mu.Lock()
+	if len(tc.got) != 3 || tc.got[0] != span3 || tc.got[1] != span1 || tc.got[2] != span2 {
+		t.Errorf("handler got %v", tc.got)
+	}
+	tc.err = errors.New("locked")
+	tc.mu.Unlock()
+
+	// A rejected order is sent back even though the server order is unchanged.
+	tr.InCh <- wire.WsMsg{Jid: elem.Jid(), What: what.Reorder, Data: jids[0].Jid().String() + " " + jids[1].Jid().String() + " " + jids[2].Jid().String()}
+	waitOrder(jids[2].Jid().String() + " " + jids[0].Jid().String() + " " + jids[1].Jid().String())
+
+	if err := NewContainer("div", &testContainer{}).JawsOrder(elem, nil); !errors.Is(err, jaws.ErrEventUnhandled) {
+		t.Errorf("want ErrEventUnhandled got %v", err)
+	}
+}
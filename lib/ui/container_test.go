@@ -192,6 +192,58 @@ func TestContainer_AppendsSelectBeforeSettingInitialValue(t *testing.T) {
 	}
 }
 
+// focusOnRender is a UI that focuses its Element when rendered.
+type focusOnRender struct{ *Span }
+
+func (u *focusOnRender) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	elem.Focus()
+	return u.Span.JawsRender(elem, w, params)
+}
+
+func TestContainer_AppendsChildBeforeFocusFromItsRender(t *testing.T) {
+	jw, err := jaws.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(jw.Close)
+	go jw.Serve()
+
+	tr := jawstest.NewTestRequest(jw, nil)
+	t.Cleanup(func() {
+		tr.Close()
+		<-tr.DoneCh
+	})
+	<-tr.ReadyCh
+
+	outerHandler := &testContainer{}
+	outerElem := tr.NewElement(NewContainer("div", outerHandler))
+	var sb strings.Builder
+	if err := outerElem.JawsRender(&sb, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	outerHandler.contents = []jaws.UI{&focusOnRender{Span: NewSpan(testHTMLGetter("row"))}}
+	tr.BcastCh <- wire.Message{Dest: outerHandler, What: what.Update}
+
+	sawAppend := false
+	for {
+		select {
+		case msg := <-tr.OutCh:
+			switch msg.What {
+			case what.Append:
+				sawAppend = true
+			case what.Focus:
+				if !sawAppend {
+					t.Fatal("child Focus was sent before its containing Append")
+				}
+				return
+			}
+		case <-time.After(time.Second):
+			t.Fatal("no Focus received")
+		}
+	}
+}
+
 func TestContainerUpdateDuplicates(t *testing.T) {
 	_, rq := newCoreRequest(t)
 	span1 := NewSpan(testHTMLGetter("span1"))
@@ -17,6 +17,9 @@ type containerState struct {
 	// rendering is true from the successful state claim until JawsRender finishes.
 	// It keeps an update from using a published but incomplete state.
 	rendering bool
+	// reordered is set when the browser reported a child order of its own,
+	// so the next update sends the server order even if it is unchanged.
+	reordered bool
 	dirtyTag  any
 	contents  []*jaws.Element
 }
@@ -130,6 +133,9 @@ func (u Container) render(elem *jaws.Element, w io.Writer, params []any, complet
 	dirtyTag = elem.ApplyGetter(u.children)
 	getterAttrs := elem.ApplyInitialHTMLAttr(u.children)
 	attrs := append(elem.ApplyParams(params), getterAttrs...)
+	if _, ok := u.children.(jaws.OrderHandler); ok {
+		attrs = append(attrs, "data-jawssortable")
+	}
 	b := elem.Jid().AppendStartTagAttr(nil, u.outerHTMLTag)
 	b = htmlio.AppendAttrs(b, attrs)
 	b = append(b, '>')
@@ -180,6 +186,7 @@ func (u Container) update(elem *jaws.Element) (updated bool) {
 	}
 
 	wantContents := u.children.JawsContains(elem)
+	reordered := st.takeReordered()
 	toAppend, toRemove, alreadyDeleted, oldOrder, newOrder := st.reconcile(elem, wantContents)
 
 	// A deleted old child is already absent from the registry, but anything it
@@ -228,13 +235,45 @@ func (u Container) update(elem *jaws.Element) (updated bool) {
 		pendingAppend = pendingAppend[1:]
 	}
 
-	if !slices.Equal(oldOrder, newOrder) {
+	if reordered || !slices.Equal(oldOrder, newOrder) {
 		elem.Order(newOrder)
 	}
 	updated = true
 	return
 }
 
+// takeReordered reports and clears whether the browser reordered the children
+// since the last update.
+func (st *containerState) takeReordered() (reordered bool) {
+	st.mu.Lock()
+	reordered, st.reordered = st.reordered, false
+	st.mu.Unlock()
+	return
+}
+
+// markReordered records that the browser reordered elem's children, returning
+// the UI values among children that are current children of elem, in order.
+func markReordered(elem *jaws.Element, children []jaws.UI) (known []jaws.UI) {
+	if st, ok := jaws.ElementState(elem).(*containerState); ok && st != nil {
+		st.mu.Lock()
+		defer st.mu.Unlock()
+		if !st.rendering {
+			st.reordered = true
+			counts := make(map[jaws.UI]int, len(st.contents))
+			for _, childElem := range st.contents {
+				counts[childElem.UI()]++
+			}
+			for _, childUI := range children {
+				if counts[childUI] > 0 {
+					counts[childUI]--
+					known = append(known, childUI)
+				}
+			}
+		}
+	}
+	return
+}
+
 // cancelUnusableChildren terminates the Request and reports true if any child cannot
 // be used as a container pool key: nil, not comparable at runtime, or not equal to
 // itself (a value holding NaN). It aborts on the first such child.
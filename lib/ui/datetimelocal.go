@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"io"
+	"time"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+)
+
+// DateTimeLocal renders an HTML datetime-local input bound to a time value setter.
+//
+// A DateTimeLocal value must back at most one live [jaws.Element]. Construct
+// distinct DateTimeLocal values over the same setter to render one bound value
+// more than once.
+//
+// A browser edit sets the bound value to the picked date and time in the
+// widget's location. See [InputTime].
+type DateTimeLocal struct{ InputTime }
+
+// NewDateTimeLocal returns a datetime-local input widget bound to g, whose
+// browser text is in location loc. If loc is nil, the bound value's own
+// location is used.
+//
+// For writable use, g must provide the setter-derived dirty target described by [Input].
+func NewDateTimeLocal(g bind.Setter[time.Time], loc *time.Location) *DateTimeLocal {
+	return &DateTimeLocal{InputTime{Setter: g, kind: timeKindDateTimeLocal, loc: loc}}
+}
+
+// JawsRender renders ui as an HTML datetime-local input.
+func (u *DateTimeLocal) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	return u.renderTimeInput(elem, w, params...)
+}
+
+// DateTimeLocal renders an HTML datetime-local input in the bound value's own location.
+func (rw RequestWriter) DateTimeLocal(value any, params ...any) error {
+	return rw.NewUI(NewDateTimeLocal(bind.MakeSetter[time.Time](value), nil), params...)
+}
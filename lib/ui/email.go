@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"io"
+	"net/mail"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+)
+
+// Email renders an HTML email input bound to a string setter.
+//
+// An Email value must back at most one live [jaws.Element]. Construct distinct
+// Email values over the same setter to render one bound value more than once.
+//
+// Edits are sent on the browser's change event, as for [jaws.InputOnChange],
+// unless the render parameters choose another send policy. The setter only
+// receives an empty string or a bare address such as "user@example.com"; see
+// [ValidEmail]. Other browser text is rejected without calling the setter or
+// returning an error, and the canonical value is restored by updating only
+// the originating Element.
+type Email struct{ InputText }
+
+// NewEmail returns an email input widget bound to g.
+//
+// For writable use, g must provide the setter-derived dirty target described by [Input].
+func NewEmail(g bind.Setter[string]) *Email { return &Email{InputText{Setter: g}} }
+
+// ValidEmail reports whether text is a bare RFC 5322 address, without a
+// display name or angle brackets.
+func ValidEmail(text string) bool {
+	addr, err := mail.ParseAddress(text)
+	return err == nil && addr.Name == "" && addr.Address == text
+}
+
+// JawsRender renders ui as an HTML email input.
+func (u *Email) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	// Caller params come first, so the HTML parser keeps theirs on conflict.
+	return u.renderStringInput(elem, w, "email", append(params[:len(params):len(params)], jaws.InputOnChange)...)
+}
+
+// JawsInput stores a browser-side email input value if it is empty or valid.
+func (u *Email) JawsInput(elem *jaws.Element, value string) error {
+	return u.validatedInput(elem, value, func(s string) bool { return s == "" || ValidEmail(s) })
+}
+
+// Email renders an HTML email input.
+func (rw RequestWriter) Email(value any, params ...any) error {
+	return rw.NewUI(NewEmail(bind.MakeSetter[string](value)), params...)
+}
@@ -0,0 +1,8 @@
+package ui
+
+import "errors"
+
+// ErrFormInvalid is returned by [Form.Submit] and [Form.Validate] when a field
+// validator or a check failed. The individual errors are available from
+// [FormField.Err] and [Form.Err].
+var ErrFormInvalid = errors.New("form has validation errors")
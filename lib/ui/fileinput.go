@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+	"github.com/linkdata/jaws/lib/htmlio"
+)
+
+// FileInput renders an HTML file input whose selected files are uploaded to a
+// callback.
+//
+// When the user selects files, the bundled client uploads them one at a time
+// and the callback receives each as a [jaws.Upload]; see [jaws.UploadHandler].
+// Pass a "multiple" or "accept" attribute as a render param to allow several
+// files or restrict their types.
+//
+// Upload progress is shown with the HTML getter returned by
+// [FileInput.ProgressHTML], which is dirtied as the current file is read:
+//
+//	{{$.FileInput .Dot.Upload `accept="image/*"`}} {{$.Span .Dot.Upload.ProgressHTML}}
+//
+// The progress state is that of the most recent upload, so construct one
+// FileInput per [jaws.Request] (or per [jaws.Session]). Its methods are safe for
+// concurrent use.
+type FileInput struct {
+	fn       func(elem *jaws.Element, upload jaws.Upload) error
+	maxSize  int64
+	mu       sync.Mutex
+	name     string
+	received int64
+	total    int64
+	err      error
+}
+
+var _ jaws.UploadHandler = (*FileInput)(nil)
+
+// fileProgress renders the upload progress of a FileInput and is the tag
+// dirtied when that progress changes.
+type fileProgress struct{ u *FileInput }
+
+var _ bind.HTMLGetter = fileProgress{}
+
+// JawsGetHTML returns a progress element and the escaped file name while or
+// after a file is uploaded, the escaped error text if the upload failed, or an
+// empty string if no file has been uploaded.
+func (fp fileProgress) JawsGetHTML(*jaws.Element) (s template.HTML) {
+	name, percent, err := fp.u.Progress()
+	if err != nil {
+		s = template.HTML(html.EscapeString(err.Error())) // #nosec G203
+	} else if name != "" {
+		s = template.HTML(`<progress max="100" value="` + strconv.Itoa(percent) + `"></progress> ` + html.EscapeString(name)) // #nosec G203
+	}
+	return
+}
+
+// NewFileInput returns a file input widget that calls fn for every uploaded
+// file. Files larger than maxSize bytes are rejected before fn is called,
+// unless maxSize is zero or less.
+func NewFileInput(fn func(elem *jaws.Element, upload jaws.Upload) error, maxSize int64) *FileInput {
+	return &FileInput{fn: fn, maxSize: maxSize}
+}
+
+// JawsRender renders ui as an HTML file input.
+func (u *FileInput) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	attrs := append([]template.HTMLAttr{"data-jawsupload"}, elem.ApplyParams(params)...)
+	return htmlio.WriteHTMLInput(w, elem.Jid(), "file", "", attrs)
+}
+
+// JawsUpdate does nothing, since a browser does not allow setting the files of
+// a file input.
+func (u *FileInput) JawsUpdate(*jaws.Element) {}
+
+// JawsUpload rejects upload with [jaws.ErrUploadTooLarge] if it exceeds the
+// size limit, and otherwise passes it to the callback while tracking progress.
+func (u *FileInput) JawsUpload(elem *jaws.Element, upload jaws.Upload) (err error) {
+	err = jaws.ErrEventUnhandled
+	if u.fn != nil {
+		u.start(elem, upload)
+		if u.maxSize > 0 && upload.Size > u.maxSize {
+			err = fmt.Errorf("%w: %q is %d bytes, the limit is %d", jaws.ErrUploadTooLarge, upload.Name, upload.Size, u.maxSize)
+		} else {
+			upload.Reader = &fileInputReader{u: u, elem: elem, r: upload.Reader}
+			err = u.fn(elem, upload)
+		}
+		u.finish(elem, err)
+	}
+	return
+}
+
+// Progress returns the name of the file being or last uploaded, the percentage
+// of it read by the callback and the error the upload failed with, if any.
+func (u *FileInput) Progress() (name string, percent int, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.name, u.percentLocked(), u.err
+}
+
+// ProgressHTML returns an HTML getter for the upload progress.
+func (u *FileInput) ProgressHTML() bind.HTMLGetter {
+	return fileProgress{u: u}
+}
+
+func (u *FileInput) percentLocked() (percent int) {
+	if u.total > 0 {
+		percent = int(min(u.received, u.total) * 100 / u.total)
+	} else if u.name != "" {
+		percent = 100
+	}
+	return
+}
+
+func (u *FileInput) start(elem *jaws.Element, upload jaws.Upload) {
+	u.mu.Lock()
+	u.name = upload.Name
+	u.received = 0
+	u.total = upload.Size
+	u.err = nil
+	u.mu.Unlock()
+	elem.Dirty(fileProgress{u: u})
+}
+
+func (u *FileInput) finish(elem *jaws.Element, err error) {
+	u.mu.Lock()
+	if err == nil {
+		u.received = u.total
+	}
+	u.err = err
+	u.mu.Unlock()
+	elem.Dirty(fileProgress{u: u})
+}
+
+// advance records n more bytes read and dirties the progress when the
+// displayed percentage changes.
+func (u *FileInput) advance(elem *jaws.Element, n int64) {
+	u.mu.Lock()
+	before := u.percentLocked()
+	u.received += n
+	changed := u.percentLocked() != before
+	u.mu.Unlock()
+	if changed {
+		elem.Dirty(fileProgress{u: u})
+	}
+}
+
+// fileInputReader counts the bytes read from an upload.
+type fileInputReader struct {
+	u    *FileInput
+	elem *jaws.Element
+	r    io.Reader
+}
+
+func (fr *fileInputReader) Read(p []byte) (n int, err error) {
+	n, err = fr.r.Read(p)
+	if n > 0 {
+		fr.u.advance(fr.elem, int64(n))
+	}
+	return
+}
+
+// FileInput renders an HTML file input that uploads the selected files to u.
+func (rw RequestWriter) FileInput(u *FileInput, params ...any) error {
+	return rw.NewUI(u, params...)
+}
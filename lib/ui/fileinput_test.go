@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws"
+)
+
+func TestFileInput_RenderAndUpload(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	var got string
+	fi := NewFileInput(func(elem *jaws.Element, upload jaws.Upload) (err error) {
+		var b []byte
+		if b, err = io.ReadAll(upload); err == nil {
+			got = upload.Name + ":" + string(b)
+		}
+		return
+	}, 8)
+
+	elem, html := renderUI(t, rq, fi, `accept="text/*"`)
+	mustMatch(t, `^<input id="Jid\.[0-9]+" type="file" data-jawsupload accept="text/\*">$`, html)
+	if s := fi.ProgressHTML().JawsGetHTML(elem); s != "" {
+		t.Errorf("idle progress %q", s)
+	}
+
+	if err := fi.JawsUpload(elem, jaws.Upload{Name: "<a>", Size: 5, Reader: strings.NewReader("hello")}); err != nil {
+		t.Fatal(err)
+	}
+	if got != "<a>:hello" {
+		t.Errorf("callback got %q", got)
+	}
+	if name, percent, err := fi.Progress(); name != "<a>" || percent != 100 || err != nil {
+		t.Errorf("progress %q %d %v", name, percent, err)
+	}
+	if s := string(fi.ProgressHTML().JawsGetHTML(elem)); s != `<progress max="100" value="100"></progress> &lt;a&gt;` {
+		t.Errorf("progress HTML %q", s)
+	}
+
+	err := fi.JawsUpload(elem, jaws.Upload{Name: "big", Size: 9, Reader: strings.NewReader("123456789")})
+	if !errors.Is(err, jaws.ErrUploadTooLarge) || got != "<a>:hello" {
+		t.Fatalf("want ErrUploadTooLarge got %v (%q)", err, got)
+	}
+	if s := string(fi.ProgressHTML().JawsGetHTML(elem)); !strings.Contains(s, "upload too large") {
+		t.Errorf("error HTML %q", s)
+	}
+
+	var percents []int
+	fi = NewFileInput(func(elem *jaws.Element, upload jaws.Upload) error {
+		buf := make([]byte, 2)
+		for {
+			if _, err := upload.Read(buf); err != nil {
+				return nil
+			}
+			_, percent, _ := fi.Progress()
+			percents = append(percents, percent)
+		}
+	}, 0)
+	if err := fi.JawsUpload(elem, jaws.Upload{Name: "p", Size: 4, Reader: strings.NewReader("1234")}); err != nil {
+		t.Fatal(err)
+	}
+	if len(percents) != 2 || percents[0] != 50 || percents[1] != 100 {
+		t.Errorf("percents %v", percents)
+	}
+
+	if err := NewFileInput(nil, 0).JawsUpload(elem, jaws.Upload{}); !errors.Is(err, jaws.ErrEventUnhandled) {
+		t.Errorf("want ErrEventUnhandled got %v", err)
+	}
+}
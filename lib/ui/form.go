@@ -0,0 +1,560 @@
+package ui
+
+import (
+	"errors"
+	"html"
+	"html/template"
+	"reflect"
+	"sync"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+	"github.com/linkdata/jaws/lib/named"
+)
+
+// Form buffers edits to a group of bound values until they are submitted.
+//
+// Fields are added with [NewFormField] or [NewFormSelect]. A field is a
+// [bind.Setter] that input widgets render and edit like any other setter, but
+// browser edits only update the field's pending value and run its validators.
+// [Form.Submit] validates every field, then runs the checks added with
+// [Form.Check], and only when all pass writes the pending values to the bound
+// setters in the order the fields were added. Fields bound to [bind.Binder]
+// values that share a lock are written together under one hold of it, so
+// readers of that lock see either none or all of their new values.
+//
+// Validation errors are shown with the HTML getters returned by
+// [FormField.ErrorHTML] and [Form.ErrorHTML], which are dirtied when their text
+// changes:
+//
+//	{{$.Text .Dot.Name}} {{$.Span .Dot.Name.ErrorHTML `class="invalid"`}}
+//	{{$.Span .Dot.Form.ErrorHTML}}
+//	{{$.Button "Save" .Dot.Form}}
+//
+// A Form holds one user's pending edits, so construct one per [jaws.Request]
+// (or per [jaws.Session]) over shared, synchronized setters. Its methods are safe
+// for concurrent use.
+type Form struct {
+	submitMu sync.Mutex // serializes Submit and Reset
+	mu       sync.Mutex // protects pending values and errors
+	fields   []IsFormField
+	checks   []formCheck
+	err      error
+}
+
+// IsFormField is implemented by the fields of a [Form].
+type IsFormField interface {
+	// Err returns the field's current validation error.
+	Err() error
+	// ErrorHTML returns an HTML getter for the field's validation error text.
+	ErrorHTML() bind.HTMLGetter
+	// Modified reports whether the field has a pending edit.
+	Modified() bool
+
+	formField() *formFieldBase
+	validate() error
+	locker() bind.RWLocker
+	commit(elem *jaws.Element) (undo func() error, err error)
+	commitLocked(elem *jaws.Element) (restore func() error, err error)
+	succeeded(elem *jaws.Element) error
+	clear(elem *jaws.Element)
+}
+
+type formCheck struct {
+	fn     func() error
+	fields []IsFormField
+}
+
+type formFieldBase struct {
+	form *Form
+	err  error
+}
+
+// formError renders an error of a Form or one of its fields and is the tag
+// dirtied when that error changes.
+type formError struct {
+	form *Form
+	errp *error
+}
+
+var _ bind.HTMLGetter = formError{}
+
+// JawsGetHTML returns the escaped error text, or an empty string if there is no error.
+func (fe formError) JawsGetHTML(*jaws.Element) (s template.HTML) {
+	if err := fe.form.getErr(fe.errp); err != nil {
+		s = template.HTML(html.EscapeString(err.Error())) // #nosec G203
+	}
+	return
+}
+
+// NewForm returns an empty Form.
+func NewForm() *Form {
+	return &Form{}
+}
+
+// Check adds a cross-field validator and returns f.
+//
+// Checks run on [Form.Submit] in the order they were added, after every field
+// validator has passed, and may read pending values with [FormField.Value]. An
+// error is shown on each of fields, or on the Form when no fields are given.
+func (f *Form) Check(fn func() error, fields ...IsFormField) *Form {
+	f.mu.Lock()
+	f.checks = append(f.checks, formCheck{fn: fn, fields: fields})
+	f.mu.Unlock()
+	return f
+}
+
+// Err returns the Form's own error: failed checks not attributed to a field, or
+// the error from the last failed commit.
+func (f *Form) Err() error {
+	return f.getErr(&f.err)
+}
+
+// ErrorHTML returns an HTML getter for the text of [Form.Err].
+func (f *Form) ErrorHTML() bind.HTMLGetter {
+	return formError{form: f, errp: &f.err}
+}
+
+// Modified reports whether any field has a pending edit.
+func (f *Form) Modified() (yes bool) {
+	for _, fld := range f.snapshot() {
+		if yes = fld.Modified(); yes {
+			break
+		}
+	}
+	return
+}
+
+// Validate runs the field validators and checks without committing.
+//
+// It updates the shown errors and returns [ErrFormInvalid] if any failed.
+func (f *Form) Validate(elem *jaws.Element) (err error) {
+	f.submitMu.Lock()
+	defer f.submitMu.Unlock()
+	return f.validateLocked(elem)
+}
+
+// Submit validates the Form and, if it is valid, writes every pending value to
+// its bound setter.
+//
+// Fields bound to [bind.Binder] values sharing a lock are written with
+// [bind.Binder.JawsSetLocked] under one hold of that lock, at the position of
+// the first of them, and their success hooks run once it is released. Other
+// fields are written with [bind.Setter.JawsSet].
+//
+// If validation fails it returns [ErrFormInvalid] without writing anything. If a
+// setter fails, the values already written are restored in reverse order, the
+// error is shown as the Form error and returned, and the pending values are kept.
+// A value is restored only if it is still the one Submit wrote, so a change
+// made by someone else in the meantime is kept. After a successful commit the
+// pending values are discarded and the fields and their setters are dirtied.
+func (f *Form) Submit(elem *jaws.Element) (err error) {
+	f.submitMu.Lock()
+	defer f.submitMu.Unlock()
+	if err = f.validateLocked(elem); err == nil {
+		fields := f.snapshot()
+		var undos []func() error
+		for _, unit := range commitUnits(fields) {
+			var undo func() error
+			if undo, err = unit.commit(elem); err != nil {
+				break
+			}
+			if undo != nil {
+				undos = append(undos, undo)
+			}
+		}
+		if err != nil {
+			errs := []error{err}
+			for i := len(undos) - 1; i >= 0; i-- {
+				errs = append(errs, undos[i]())
+			}
+			err = errors.Join(errs...)
+			f.setErr(elem, &f.err, err)
+			return
+		}
+		for _, fld := range fields {
+			fld.clear(elem)
+		}
+	}
+	return
+}
+
+// Reset discards all pending values and shown errors.
+func (f *Form) Reset(elem *jaws.Element) {
+	f.submitMu.Lock()
+	defer f.submitMu.Unlock()
+	for _, fld := range f.snapshot() {
+		fld.clear(elem)
+		f.setErr(elem, &fld.formField().err, nil)
+	}
+	f.setErr(elem, &f.err, nil)
+}
+
+// JawsClick submits the Form, so it can be passed as a handler parameter to a
+// button. An invalid Form is not an error here, since its errors are shown
+// with the error getters.
+func (f *Form) JawsClick(elem *jaws.Element, _ jaws.Click) (err error) {
+	if err = f.Submit(elem); errors.Is(err, ErrFormInvalid) {
+		err = nil
+	}
+	return
+}
+
+// commitUnit is a group of fields Submit writes together: the fields bound to
+// Binders sharing locker, or a single field with a nil locker.
+type commitUnit struct {
+	locker bind.RWLocker
+	fields []IsFormField
+}
+
+// commitUnits groups fields by their lock, ordering the groups by their first
+// field.
+func commitUnits(fields []IsFormField) (units []commitUnit) {
+	index := make(map[bind.RWLocker]int)
+	for _, fld := range fields {
+		l := fld.locker()
+		if l != nil {
+			if i, ok := index[l]; ok {
+				units[i].fields = append(units[i].fields, fld)
+				continue
+			}
+			index[l] = len(units)
+		}
+		units = append(units, commitUnit{locker: l, fields: []IsFormField{fld}})
+	}
+	return
+}
+
+// commit writes the pending values of u's fields, returning a function that
+// restores them if a later unit fails.
+func (u commitUnit) commit(elem *jaws.Element) (undo func() error, err error) {
+	if u.locker == nil {
+		return u.fields[0].commit(elem)
+	}
+	var restores []func() error
+	var written []IsFormField
+	if restores, written, err = u.commitLocked(elem); err == nil {
+		undo = func() error {
+			u.locker.Lock()
+			defer u.locker.Unlock()
+			return restoreAll(restores)
+		}
+		for _, fld := range written {
+			if err = fld.succeeded(elem); err != nil {
+				err = errors.Join(err, undo())
+				undo = nil
+				break
+			}
+		}
+	}
+	return
+}
+
+// commitLocked writes the pending values of u's fields while holding
+// u.locker. If a setter fails or panics, the values already written are
+// restored before the lock is released.
+func (u commitUnit) commitLocked(elem *jaws.Element) (restores []func() error, written []IsFormField, err error) {
+	u.locker.Lock()
+	done := false
+	defer func() {
+		if !done {
+			if e := restoreAll(restores); err != nil {
+				err = errors.Join(err, e)
+			}
+		}
+		u.locker.Unlock()
+	}()
+	for _, fld := range u.fields {
+		var r func() error
+		if r, err = fld.commitLocked(elem); err != nil {
+			return
+		}
+		if r != nil {
+			restores = append(restores, r)
+			written = append(written, fld)
+		}
+	}
+	done = true
+	return
+}
+
+// restoreAll calls restores in reverse order, joining their errors.
+func restoreAll(restores []func() error) error {
+	errs := make([]error, 0, len(restores))
+	for i := len(restores) - 1; i >= 0; i-- {
+		errs = append(errs, restores[i]())
+	}
+	return errors.Join(errs...)
+}
+
+func (f *Form) snapshot() (fields []IsFormField) {
+	f.mu.Lock()
+	fields = append(fields, f.fields...)
+	f.mu.Unlock()
+	return
+}
+
+func (f *Form) validateLocked(elem *jaws.Element) (err error) {
+	f.mu.Lock()
+	fields := append([]IsFormField(nil), f.fields...)
+	checks := append([]formCheck(nil), f.checks...)
+	f.mu.Unlock()
+
+	fieldErrs := make(map[*formFieldBase][]error)
+	var formErrs []error
+	for _, fld := range fields {
+		if e := fld.validate(); e != nil {
+			fieldErrs[fld.formField()] = append(fieldErrs[fld.formField()], e)
+		}
+	}
+	if len(fieldErrs) == 0 {
+		for _, chk := range checks {
+			if e := chk.fn(); e != nil {
+				if len(chk.fields) == 0 {
+					formErrs = append(formErrs, e)
+				}
+				for _, fld := range chk.fields {
+					fieldErrs[fld.formField()] = append(fieldErrs[fld.formField()], e)
+				}
+			}
+		}
+	}
+	for _, fld := range fields {
+		f.setErr(elem, &fld.formField().err, errors.Join(fieldErrs[fld.formField()]...))
+	}
+	f.setErr(elem, &f.err, errors.Join(formErrs...))
+	if len(fieldErrs) > 0 || len(formErrs) > 0 {
+		err = ErrFormInvalid
+	}
+	return
+}
+
+func (f *Form) getErr(errp *error) (err error) {
+	f.mu.Lock()
+	err = *errp
+	f.mu.Unlock()
+	return
+}
+
+// setErr stores err in *errp and dirties its error getter if the text changed.
+func (f *Form) setErr(elem *jaws.Element, errp *error, err error) {
+	f.mu.Lock()
+	changed := errorText(*errp) != errorText(err)
+	*errp = err
+	f.mu.Unlock()
+	if changed && elem != nil {
+		elem.Dirty(formError{form: f, errp: errp})
+	}
+}
+
+func errorText(err error) (s string) {
+	if err != nil {
+		s = err.Error()
+	}
+	return
+}
+
+// FormField is a [bind.Setter] that buffers edits for a [Form].
+//
+// Until the Form is submitted, JawsGet returns the pending value if there is
+// one and the bound setter's value otherwise. The FormField pointer is its
+// dirty tag.
+type FormField[T comparable] struct {
+	formFieldBase
+	target     bind.Setter[T]
+	validators []func(T) error
+	pending    T
+	edited     bool
+}
+
+var (
+	_ bind.Setter[string] = &FormField[string]{}
+	_ IsFormField         = &FormField[string]{}
+)
+
+// NewFormField adds a field bound to target to form and returns it.
+//
+// The validators run in order on every edit and on [Form.Submit]; the first
+// error is shown as the field's error.
+func NewFormField[T comparable](form *Form, target bind.Setter[T], validators ...func(T) error) (ff *FormField[T]) {
+	ff = &FormField[T]{
+		formFieldBase: formFieldBase{form: form},
+		target:        target,
+		validators:    validators,
+	}
+	form.mu.Lock()
+	form.fields = append(form.fields, ff)
+	form.mu.Unlock()
+	return
+}
+
+// JawsGet returns the pending value, or the bound value if there is none.
+func (ff *FormField[T]) JawsGet(elem *jaws.Element) (value T) {
+	ff.form.mu.Lock()
+	value, edited := ff.pending, ff.edited
+	ff.form.mu.Unlock()
+	if !edited {
+		value = ff.target.JawsGet(elem)
+	}
+	return
+}
+
+// JawsSet stores value as the pending value and shows the result of the field
+// validators. It returns [jaws.ErrValueUnchanged] if value was already pending.
+//
+// A failed validator does not make JawsSet fail; the value stays pending and
+// the error is shown with [FormField.ErrorHTML].
+func (ff *FormField[T]) JawsSet(elem *jaws.Element, value T) (err error) {
+	err = jaws.ErrValueUnchanged
+	ff.form.mu.Lock()
+	if !ff.edited || ff.pending != value {
+		ff.pending, ff.edited = value, true
+		err = nil
+	}
+	ff.form.mu.Unlock()
+	if err == nil {
+		ff.form.setErr(elem, &ff.err, ff.check(value))
+	}
+	return
+}
+
+// JawsGetTag returns ff.
+func (ff *FormField[T]) JawsGetTag() any {
+	return ff
+}
+
+// Value returns the pending value, or the bound value read with a nil
+// [jaws.Element] if there is none.
+func (ff *FormField[T]) Value() T {
+	return ff.JawsGet(nil)
+}
+
+// Err returns the field's current validation error.
+func (ff *FormField[T]) Err() error {
+	return ff.form.getErr(&ff.err)
+}
+
+// ErrorHTML returns an HTML getter for the text of [FormField.Err].
+func (ff *FormField[T]) ErrorHTML() bind.HTMLGetter {
+	return formError{form: ff.form, errp: &ff.err}
+}
+
+// Modified reports whether the field has a pending edit.
+func (ff *FormField[T]) Modified() (yes bool) {
+	ff.form.mu.Lock()
+	yes = ff.edited
+	ff.form.mu.Unlock()
+	return
+}
+
+func (ff *FormField[T]) formField() *formFieldBase {
+	return &ff.formFieldBase
+}
+
+func (ff *FormField[T]) check(value T) (err error) {
+	for _, fn := range ff.validators {
+		if err = fn(value); err != nil {
+			break
+		}
+	}
+	return
+}
+
+func (ff *FormField[T]) validate() error {
+	return ff.check(ff.Value())
+}
+
+func (ff *FormField[T]) commit(elem *jaws.Element) (undo func() error, err error) {
+	ff.form.mu.Lock()
+	value, edited := ff.pending, ff.edited
+	ff.form.mu.Unlock()
+	if edited {
+		prev := ff.target.JawsGet(elem)
+		if err = ff.target.JawsSet(elem, value); err == nil {
+			undo = func() (err error) {
+				if ff.target.JawsGet(elem) == value {
+					if err = ff.target.JawsSet(elem, prev); errors.Is(err, jaws.ErrValueUnchanged) {
+						err = nil
+					}
+				}
+				return
+			}
+		} else if errors.Is(err, jaws.ErrValueUnchanged) {
+			err = nil
+		}
+	}
+	return
+}
+
+// locker returns the lock of the bound Binder, or nil if the target is not a
+// Binder or its lock cannot be used as a map key.
+func (ff *FormField[T]) locker() (l bind.RWLocker) {
+	if b, ok := ff.target.(bind.Binder[T]); ok {
+		if l = b.JawsLocker(); l != nil && !reflect.ValueOf(l).Comparable() {
+			l = nil
+		}
+	}
+	return
+}
+
+// commitLocked is commit for a Binder target whose lock the caller holds. The
+// returned restore function must also be called with the lock held.
+func (ff *FormField[T]) commitLocked(elem *jaws.Element) (restore func() error, err error) {
+	ff.form.mu.Lock()
+	value, edited := ff.pending, ff.edited
+	ff.form.mu.Unlock()
+	if edited {
+		b := ff.target.(bind.Binder[T])
+		prev := b.JawsGetLocked(elem)
+		if err = b.JawsSetLocked(elem, value); err == nil {
+			restore = func() (err error) {
+				if b.JawsGetLocked(elem) == value {
+					if err = b.JawsSetLocked(elem, prev); errors.Is(err, jaws.ErrValueUnchanged) {
+						err = nil
+					}
+				}
+				return
+			}
+		} else if errors.Is(err, jaws.ErrValueUnchanged) {
+			err = nil
+		}
+	}
+	return
+}
+
+// succeeded runs the success hooks of a Binder target after commitLocked
+// wrote to it.
+func (ff *FormField[T]) succeeded(elem *jaws.Element) error {
+	return ff.target.(bind.Binder[T]).JawsSucceeded(elem)
+}
+
+func (ff *FormField[T]) clear(elem *jaws.Element) {
+	ff.form.mu.Lock()
+	edited := ff.edited
+	var blank T
+	ff.pending, ff.edited = blank, false
+	ff.form.mu.Unlock()
+	if edited && elem != nil {
+		elem.Dirty(ff, ff.target)
+	}
+}
+
+// FormSelect is a [named.SelectHandler] whose selection is buffered by a [Form].
+//
+// The options are those of the wrapped handler, and the committed selection is
+// written to it on [Form.Submit].
+type FormSelect struct {
+	*FormField[string]
+	jaws.Container
+}
+
+var _ named.SelectHandler = FormSelect{}
+
+// NewFormSelect adds a field for the selection of handler to form and returns
+// it as a handler for [Select].
+func NewFormSelect(form *Form, handler named.SelectHandler, validators ...func(string) error) FormSelect {
+	return FormSelect{
+		FormField: NewFormField[string](form, handler, validators...),
+		Container: handler,
+	}
+}
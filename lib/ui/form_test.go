@@ -0,0 +1,314 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+	"github.com/linkdata/jaws/lib/named"
+	"github.com/linkdata/jaws/lib/what"
+)
+
+var errFormTestRequired = errors.New("required")
+
+func formTestRequired(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return errFormTestRequired
+	}
+	return nil
+}
+
+func TestForm_BuffersValidatesAndCommits(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	var mu sync.Mutex
+	name, password, confirm := "alice", "", ""
+	nameSetter := bind.New(&mu, &name)
+
+	form := NewForm()
+	nameField := NewFormField(form, nameSetter, formTestRequired)
+	passField := NewFormField(form, bind.New(&mu, &password), formTestRequired)
+	confirmField := NewFormField(form, bind.New(&mu, &confirm))
+	errMismatch := errors.New("passwords differ")
+	form.Check(func() error {
+		if passField.Value() != confirmField.Value() {
+			return errMismatch
+		}
+		return nil
+	}, confirmField)
+
+	text := NewText(nameField)
+	textElem, got := renderUI(t, rq, text)
+	mustMatch(t, `^<input id="Jid\.[0-9]+" type="text" value="alice">$`, got)
+	errSpan := NewSpan(nameField.ErrorHTML())
+	errElem, got := renderUI(t, rq, errSpan)
+	mustMatch(t, `^<span id="Jid\.[0-9]+"></span>$`, got)
+	if !errElem.HasTag(nameField.ErrorHTML()) {
+		t.Error("error span not tagged with its error getter")
+	}
+
+	if err := text.JawsInput(textElem, " "); err != nil {
+		t.Fatal(err)
+	}
+	if name != "alice" {
+		t.Fatalf("edit reached the setter: %q", name)
+	}
+	if !errors.Is(nameField.Err(), errFormTestRequired) {
+		t.Errorf("field error %v", nameField.Err())
+	}
+	if got := string(nameField.ErrorHTML().JawsGetHTML(errElem)); got != "required" {
+		t.Errorf("error HTML %q", got)
+	}
+	if !form.Modified() || !nameField.Modified() || passField.Modified() {
+		t.Error("unexpected Modified state")
+	}
+
+	if err := form.Submit(textElem); !errors.Is(err, ErrFormInvalid) {
+		t.Fatalf("want ErrFormInvalid got %v", err)
+	}
+	if !errors.Is(passField.Err(), errFormTestRequired) {
+		t.Errorf("unedited field not validated: %v", passField.Err())
+	}
+
+	if err := text.JawsInput(textElem, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if nameField.Err() != nil {
+		t.Errorf("error not cleared: %v", nameField.Err())
+	}
+	if err := passField.JawsSet(textElem, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := passField.JawsSet(textElem, "secret"); !errors.Is(err, jaws.ErrValueUnchanged) {
+		t.Errorf("want ErrValueUnchanged got %v", err)
+	}
+	if err := confirmField.JawsSet(textElem, "secert"); err != nil {
+		t.Fatal(err)
+	}
+	if err := form.Submit(textElem); !errors.Is(err, ErrFormInvalid) {
+		t.Fatalf("want ErrFormInvalid got %v", err)
+	}
+	if !errors.Is(confirmField.Err(), errMismatch) || form.Err() != nil {
+		t.Errorf("check error on %v / %v", confirmField.Err(), form.Err())
+	}
+	if name != "alice" || password != "" {
+		t.Fatal("invalid form committed")
+	}
+
+	if err := confirmField.JawsSet(textElem, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := form.Submit(textElem); err != nil {
+		t.Fatal(err)
+	}
+	if name != "bob" || password != "secret" || confirm != "secret" {
+		t.Errorf("committed %q %q %q", name, password, confirm)
+	}
+	if form.Modified() || confirmField.Err() != nil {
+		t.Error("pending state kept after commit")
+	}
+	name = "carol"
+	if nameField.Value() != "carol" {
+		t.Errorf("field does not read through after commit: %q", nameField.Value())
+	}
+}
+
+func TestForm_RollsBackFailedCommit(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	first := newTestSetter("a")
+	second := newTestSetter(1)
+	third := newTestSetter(true)
+	form := NewForm()
+	f1 := NewFormField[string](form, first)
+	f2 := NewFormField[int](form, second)
+	f3 := NewFormField[bool](form, third)
+	elem := rq.NewElement(NewSpan(form.ErrorHTML()))
+
+	_ = f1.JawsSet(elem, "b")
+	_ = f2.JawsSet(elem, 2)
+	_ = f3.JawsSet(elem, false)
+	errBoom := errors.New("boom")
+	third.SetErr(errBoom)
+	if err := form.Submit(elem); !errors.Is(err, errBoom) {
+		t.Fatalf("want boom got %v", err)
+	}
+	if first.Get() != "a" || second.Get() != 1 {
+		t.Errorf("not rolled back: %q %d", first.Get(), second.Get())
+	}
+	if !errors.Is(form.Err(), errBoom) || !f1.Modified() {
+		t.Errorf("form error %v, modified %v", form.Err(), f1.Modified())
+	}
+
+	third.SetErr(nil)
+	if err := form.JawsClick(elem, jaws.Click{}); err != nil {
+		t.Fatal(err)
+	}
+	if first.Get() != "b" || second.Get() != 2 || third.Get() {
+		t.Errorf("not committed: %q %d %v", first.Get(), second.Get(), third.Get())
+	}
+}
+
+// formTestLocker is a sync.Mutex that counts how often it is locked.
+type formTestLocker struct {
+	sync.Mutex
+	locks int
+}
+
+func (l *formTestLocker) Lock() {
+	l.Mutex.Lock()
+	l.locks++
+}
+
+func TestForm_CommitsSharedLockAtomically(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	var lk formTestLocker
+	a, b := 0, 0
+	var aLocks, bLocks int
+	var hookLocked bool
+	errBoom := errors.New("boom")
+	binderA := bind.New(&lk, &a).SetLocked(func(prev bind.Binder[int], elem *jaws.Element, v int) error {
+		aLocks = lk.locks
+		return prev.JawsSetLocked(elem, v)
+	}).Success(func() {
+		if lk.TryLock() {
+			lk.Unlock()
+		} else {
+			hookLocked = true
+		}
+	})
+	binderB := bind.New(&lk, &b).SetLocked(func(prev bind.Binder[int], elem *jaws.Element, v int) error {
+		bLocks = lk.locks
+		if v == 3 {
+			return errBoom
+		}
+		return prev.JawsSetLocked(elem, v)
+	})
+	var otherMu sync.Mutex
+	other, failOther := "x", false
+	binderOther := bind.New(&otherMu, &other).SetLocked(func(prev bind.Binder[string], elem *jaws.Element, v string) error {
+		if failOther {
+			// Someone else changes a before the Form restores it.
+			lk.Lock()
+			a = 9
+			lk.Unlock()
+			return errBoom
+		}
+		return prev.JawsSetLocked(elem, v)
+	})
+	form := NewForm()
+	fa := NewFormField(form, binderA)
+	fo := NewFormField(form, binderOther)
+	fb := NewFormField(form, binderB)
+	elem := rq.NewElement(NewSpan(form.ErrorHTML()))
+
+	_ = fa.JawsSet(elem, 1)
+	_ = fo.JawsSet(elem, "y")
+	_ = fb.JawsSet(elem, 2)
+	if err := form.Submit(elem); err != nil {
+		t.Fatal(err)
+	}
+	if a != 1 || b != 2 || other != "y" {
+		t.Errorf("committed %d %d %q", a, b, other)
+	}
+	if aLocks != bLocks {
+		t.Errorf("fields sharing a lock written under %d and %d", aLocks, bLocks)
+	}
+	if hookLocked {
+		t.Error("success hook ran with the lock held")
+	}
+
+	// A failure restores the fields already written under the shared lock.
+	_ = fa.JawsSet(elem, 5)
+	_ = fb.JawsSet(elem, 3)
+	if err := form.Submit(elem); !errors.Is(err, errBoom) {
+		t.Fatalf("want boom got %v", err)
+	}
+	if a != 1 || b != 2 {
+		t.Errorf("not restored: %d %d", a, b)
+	}
+
+	// A later failure restores a written value only if it is still current.
+	_ = fb.JawsSet(elem, 4)
+	_ = fo.JawsSet(elem, "z")
+	failOther = true
+	if err := form.Submit(elem); !errors.Is(err, errBoom) {
+		t.Fatalf("want boom got %v", err)
+	}
+	if a != 9 || b != 2 || other != "y" {
+		t.Errorf("after failed commit: %d %d %q", a, b, other)
+	}
+}
+
+func TestForm_CommitPanicReleasesSharedLock(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	var mu sync.Mutex
+	a, b := 0, 0
+	binderA := bind.New(&mu, &a)
+	binderB := bind.New(&mu, &b).SetLocked(func(prev bind.Binder[int], elem *jaws.Element, v int) error {
+		panic("bang")
+	})
+	form := NewForm()
+	fa := NewFormField(form, binderA)
+	fb := NewFormField(form, binderB)
+	elem := rq.NewElement(NewSpan(form.ErrorHTML()))
+
+	_ = fa.JawsSet(elem, 1)
+	_ = fb.JawsSet(elem, 2)
+	func() {
+		defer func() {
+			if x := recover(); x != "bang" {
+				t.Errorf("want panic bang got %v", x)
+			}
+		}()
+		_ = form.Submit(elem)
+	}()
+	if !mu.TryLock() {
+		t.Fatal("shared lock still held after panic")
+	}
+	mu.Unlock()
+	if a != 0 || b != 0 {
+		t.Errorf("not restored: %d %d", a, b)
+	}
+}
+
+func TestForm_ResetAndSelect(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	nba := named.NewBoolArray(false).Add("red", "Red").Add("blue", "Blue")
+	nba.Set("red", true)
+	form := NewForm()
+	errNoBlue := errors.New("no blue")
+	color := NewFormSelect(form, nba, func(s string) error {
+		if s == "blue" {
+			return errNoBlue
+		}
+		return nil
+	})
+	form.Check(func() error { return errors.New("never") }, color)
+
+	sel := NewSelect(color)
+	elem, got := renderUI(t, rq, sel)
+	if !strings.Contains(got, `<select id="`) || !strings.Contains(got, "Blue") {
+		t.Fatalf("unexpected render %q", got)
+	}
+	if err := jaws.CallEventHandlers(sel, elem, what.Input, "blue"); err != nil {
+		t.Fatal(err)
+	}
+	if nba.Get() != "red" || color.Value() != "blue" {
+		t.Errorf("selection %q pending %q", nba.Get(), color.Value())
+	}
+	if !errors.Is(color.Err(), errNoBlue) {
+		t.Errorf("select error %v", color.Err())
+	}
+	if err := form.JawsClick(elem, jaws.Click{}); err != nil {
+		t.Fatal(err)
+	}
+	form.Reset(elem)
+	if form.Modified() || color.Err() != nil || color.Value() != "red" {
+		t.Errorf("after reset: modified %v err %v value %q", form.Modified(), color.Err(), color.Value())
+	}
+	if err := form.Validate(elem); !errors.Is(err, ErrFormInvalid) || color.Err() == nil {
+		t.Errorf("check not attributed to select: %v %v", err, color.Err())
+	}
+}
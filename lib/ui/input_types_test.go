@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws"
+)
+
+func TestInputTimeWidgets(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	loc := time.FixedZone("UTC+2", 2*3600)
+	at := time.Date(2026, time.March, 5, 9, 30, 0, 0, loc)
+
+	tests := []struct {
+		name     string
+		make     func(src *testSetter[time.Time]) jaws.UI
+		rendered string
+		edit     string
+		want     time.Time
+		canon    string
+	}{
+		{"time", func(src *testSetter[time.Time]) jaws.UI { return NewTime(src, nil) },
+			`type="time" value="09:30"`, "17:45:30.5",
+			time.Date(2026, time.March, 5, 17, 45, 30, 5e8, loc), "17:45:30.5"},
+		{"time in UTC", func(src *testSetter[time.Time]) jaws.UI { return NewTime(src, time.UTC) },
+			`type="time" value="07:30"`, "23:15",
+			time.Date(2026, time.March, 5, 23, 15, 0, 0, time.UTC), "23:15"},
+		{"datetime-local", func(src *testSetter[time.Time]) jaws.UI { return NewDateTimeLocal(src, nil) },
+			`type="datetime-local" value="2026-03-05T09:30"`, "2027-12-31T23:59:00",
+			time.Date(2027, time.December, 31, 23, 59, 0, 0, loc), "2027-12-31T23:59"},
+		{"month", func(src *testSetter[time.Time]) jaws.UI { return NewMonth(src, nil) },
+			`type="month" value="2026-03"`, "2024-02",
+			time.Date(2024, time.February, 1, 0, 0, 0, 0, loc), "2024-02"},
+		{"week", func(src *testSetter[time.Time]) jaws.UI { return NewWeek(src, nil) },
+			`type="week" value="2026-W10"`, "2021-W01",
+			time.Date(2021, time.January, 4, 0, 0, 0, 0, loc), "2021-W01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newTestSetter(at)
+			ui := tt.make(src)
+			elem, got := renderUI(t, rq, ui)
+			mustMatch(t, `^<input id="Jid\.[0-9]+" `+tt.rendered+`>$`, got)
+			in := ui.(jaws.InputHandler)
+			if err := in.JawsInput(elem, tt.edit); err != nil {
+				t.Fatal(err)
+			}
+			if got := src.Get(); !got.Equal(tt.want) || got.Location() != tt.want.Location() {
+				t.Errorf("edit %q set %v, want %v", tt.edit, got, tt.want)
+			}
+			inputTime := embeddedInputTime(ui)
+			if s := inputTime.str(src.Get()); s != tt.canon {
+				t.Errorf("formatted %q, want %q", s, tt.canon)
+			}
+
+			// Malformed text leaves the value and queues a correction.
+			if err := in.JawsInput(elem, "bad"); err != nil {
+				t.Fatal(err)
+			}
+			if got := src.Get(); !got.Equal(tt.want) {
+				t.Errorf("rejected edit changed value to %v", got)
+			}
+
+			// Clearing the input sets the zero time, which renders empty.
+			if err := in.JawsInput(elem, ""); err != nil || !src.Get().IsZero() {
+				t.Errorf("clear: %v, %v", err, src.Get())
+			}
+			if s := inputTime.str(time.Time{}); s != "" {
+				t.Errorf("zero formatted %q", s)
+			}
+		})
+	}
+}
+
+func TestTime_MidnightOnZeroValue(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	src := newTestSetter(time.Time{})
+	ui := NewTime(src, time.UTC)
+	elem, got := renderUI(t, rq, ui)
+	mustMatch(t, `^<input id="Jid\.[0-9]+" type="time">$`, got)
+	if err := ui.JawsInput(elem, "00:00"); err != nil {
+		t.Fatal(err)
+	}
+	if v := src.Get(); v.IsZero() || !v.Equal(time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("picked midnight set %v", v)
+	}
+	if s := ui.str(src.Get()); s != "00:00" {
+		t.Errorf("formatted %q", s)
+	}
+}
+
+func embeddedInputTime(ui jaws.UI) *InputTime {
+	switch u := ui.(type) {
+	case *Time:
+		return &u.InputTime
+	case *DateTimeLocal:
+		return &u.InputTime
+	case *Month:
+		return &u.InputTime
+	case *Week:
+		return &u.InputTime
+	}
+	return nil
+}
+
+func TestParseTimeInput(t *testing.T) {
+	for _, tt := range []struct {
+		kind timeKind
+		text string
+		ok   bool
+	}{
+		{timeKindTime, "24:00", false},
+		{timeKindTime, "12:3", false},
+		{timeKindDateTimeLocal, "2026-02-30T10:00", false},
+		{timeKindDateTimeLocal, "2026-02-28 10:00", false},
+		{timeKindMonth, "2026-13", false},
+		{timeKindWeek, "2025-W53", false},
+		{timeKindWeek, "2020-W53", true},
+		{timeKindWeek, "2026-W00", false},
+		{timeKindWeek, "+202-W01", false},
+		{timeKindWeek, "0000-W01", false},
+	} {
+		if _, ok := parseTimeInput(tt.kind, tt.text, time.Time{}, time.UTC); ok != tt.ok {
+			t.Errorf("%s %q: ok %v", timeKindHTMLType[tt.kind], tt.text, ok)
+		}
+	}
+	// ISO week years differ from calendar years around New Year.
+	v := time.Date(2027, time.January, 1, 12, 0, 0, 0, time.UTC)
+	if s := formatTimeInput(timeKindWeek, v, time.UTC); s != "2026-W53" {
+		t.Errorf("week %q", s)
+	}
+	if w, ok := parseTimeInput(timeKindWeek, "2026-W53", time.Time{}, time.UTC); !ok || !w.Equal(time.Date(2026, time.December, 28, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("parsed %v %v", w, ok)
+	}
+}
+
+func TestColor(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	src := newTestSetter(RGB{R: 0x12, G: 0xab, B: 0})
+	color := NewColor(src)
+	elem, got := renderUI(t, rq, color)
+	mustMatch(t, `^<input id="Jid\.[0-9]+" type="color" value="#12ab00">$`, got)
+
+	if err := color.JawsInput(elem, "#FF8000"); err != nil {
+		t.Fatal(err)
+	}
+	if src.Get() != (RGB{R: 0xff, G: 0x80}) {
+		t.Errorf("color %v", src.Get())
+	}
+	for _, bad := range []string{"", "ff8000", "#ff800", "#ff800g", "#+ff800"} {
+		if err := color.JawsInput(elem, bad); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ParseRGB(bad); !errors.Is(err, ErrInvalidRGB) {
+			t.Errorf("ParseRGB(%q): %v", bad, err)
+		}
+	}
+	if src.Get().String() != "#ff8000" {
+		t.Errorf("rejected edit changed color to %v", src.Get())
+	}
+	if err := color.JawsInput(elem, "#ff8000"); err != nil {
+		t.Errorf("unchanged color: %v", err)
+	}
+	src.Set(RGB{B: 1})
+	color.JawsUpdate(elem)
+	if color.Last.Load() != "#000001" {
+		t.Errorf("last %v", color.Last.Load())
+	}
+}
+
+func TestInputTextTypes(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	for _, tt := range []struct {
+		make     func(*testSetter[string]) jaws.UI
+		rendered string
+		valid    []string
+		invalid  []string
+	}{
+		{func(s *testSetter[string]) jaws.UI { return NewEmail(s) },
+			`type="email" value="x" data-jawsinput="change"`,
+			[]string{"user@example.com", ""},
+			[]string{"user", "Bob <bob@example.com>", "a@b c"}},
+		{func(s *testSetter[string]) jaws.UI { return NewURL(s) },
+			`type="url" value="x" data-jawsinput="change"`,
+			[]string{"https://example.com/a?b", "mailto:user@example.com", "file:///tmp", ""},
+			[]string{"example.com", "/path", "http:", "http://a b"}},
+		{func(s *testSetter[string]) jaws.UI { return NewTel(s) },
+			`type="tel" value="x"`, []string{"+1 555"}, nil},
+		{func(s *testSetter[string]) jaws.UI { return NewSearch(s) },
+			`type="search" value="x"`, []string{"query"}, nil},
+	} {
+		src := newTestSetter("x")
+		ui := tt.make(src)
+		elem, got := renderUI(t, rq, ui)
+		mustMatch(t, `^<input id="Jid\.[0-9]+" `+tt.rendered+`>$`, got)
+		in := ui.(jaws.InputHandler)
+		for _, s := range tt.valid {
+			if err := in.JawsInput(elem, s); err != nil || src.Get() != s {
+				t.Errorf("%s: valid %q: %v, got %q", got, s, err, src.Get())
+			}
+		}
+		src.Set("kept")
+		for _, s := range tt.invalid {
+			if err := in.JawsInput(elem, s); err != nil || src.Get() != "kept" {
+				t.Errorf("%s: invalid %q: %v, got %q", got, s, err, src.Get())
+			}
+		}
+	}
+
+	// Caller params take precedence over the change send policy.
+	_, got := renderUI(t, rq, NewURL(newTestSetter("")), jaws.InputDebounce(time.Second))
+	mustMatch(t, `^<input id="Jid\.[0-9]+" type="url" data-jawsinput="debounce=1000" data-jawsinput="change">$`, got)
+}
@@ -92,6 +92,18 @@ func (u *InputText) JawsInput(elem *jaws.Element, value string) (err error) {
 	return
 }
 
+// validatedInput stores a browser-side string input value if valid accepts it.
+// Rejected values are validation, not handler errors: Last keeps the rejected
+// text, so the update that exact dirtying schedules restores the bound value.
+func (u *InputText) validatedInput(elem *jaws.Element, value string, valid func(string) bool) (err error) {
+	if !valid(value) {
+		u.Last.Store(value)
+		elem.Dirty(elem)
+		return
+	}
+	return u.JawsInput(elem, value)
+}
+
 // InputBool is the reusable base for boolean input widgets.
 //
 // A widget embedding InputBool must back at most one live [jaws.Element].
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"io"
+	"time"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+)
+
+// Month renders an HTML month input bound to a time value setter.
+//
+// A Month value must back at most one live [jaws.Element]. Construct distinct Month
+// values over the same setter to render one bound value more than once.
+//
+// A browser edit sets the bound value to midnight on the first day of the
+// picked month in the widget's location. See [InputTime].
+type Month struct{ InputTime }
+
+// NewMonth returns a month input widget bound to g, whose browser text is in
+// location loc. If loc is nil, the bound value's own location is used.
+//
+// For writable use, g must provide the setter-derived dirty target described by [Input].
+func NewMonth(g bind.Setter[time.Time], loc *time.Location) *Month {
+	return &Month{InputTime{Setter: g, kind: timeKindMonth, loc: loc}}
+}
+
+// JawsRender renders ui as an HTML month input.
+func (u *Month) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	return u.renderTimeInput(elem, w, params...)
+}
+
+// Month renders an HTML month input in the bound value's own location.
+func (rw RequestWriter) Month(value any, params ...any) error {
+	return rw.NewUI(NewMonth(bind.MakeSetter[time.Time](value), nil), params...)
+}
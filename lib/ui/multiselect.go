@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/named"
+)
+
+// MultiSelect renders a multiple-selection HTML select element.
+//
+// Its handler supplies the options and represents the selection as the set of
+// selected option values. Option values must be non-empty. A multi-select
+// [named.BoolArray] is the standard handler; use [NewChoices] to bind a
+// [bind.SliceSetter] of any comparable type.
+//
+// The bundled client sends the full selected set on every change, as a JSON
+// array of option values, and the server sends the full set back after
+// reconciling the options.
+//
+// Handler identity and multiplicity requirements are those of [Select]. Use
+// MultiSelect as a value; taking its address changes identity and is
+// unsupported.
+type MultiSelect struct {
+	handler named.MultiSelectHandler
+}
+
+var (
+	_ jaws.UI           = MultiSelect{}
+	_ jaws.InputHandler = MultiSelect{}
+)
+
+// NewMultiSelect returns a multiple-selection MultiSelect backed by handler.
+func NewMultiSelect(handler named.MultiSelectHandler) MultiSelect {
+	return MultiSelect{handler: handler}
+}
+
+// JawsRender renders u as an HTML select element with the multiple attribute.
+//
+// On success, it queues the selected values after the options.
+func (u MultiSelect) JawsRender(elem *jaws.Element, w io.Writer, params []any) (err error) {
+	params = append(params[:len(params):len(params)], template.HTMLAttr("multiple"))
+	err = u.container().render(elem, w, params, func() { u.applyValue(elem) })
+	return
+}
+
+// JawsUpdate reconciles the child options and then queues the selected values.
+//
+// State contention suppresses both operations.
+func (u MultiSelect) JawsUpdate(elem *jaws.Element) {
+	if u.container().update(elem) {
+		u.applyValue(elem)
+	}
+}
+
+func (u MultiSelect) applyValue(elem *jaws.Element) {
+	values := u.handler.JawsGetSlice(elem)
+	if values == nil {
+		values = []string{}
+	}
+	b, _ := json.Marshal(values) // a []string always marshals
+	elem.SetValue(string(b))
+}
+
+func (u MultiSelect) container() Container {
+	return NewContainer("select", u.handler)
+}
+
+// JawsInput stores the browser-side set of selected option values.
+//
+// The value is a JSON array of strings; anything else is a malformed client
+// frame and returns the decoding error. A nil-interface handler is a no-op.
+func (u MultiSelect) JawsInput(elem *jaws.Element, value string) (err error) {
+	if u.handler != nil {
+		var values []string
+		if err = json.Unmarshal([]byte(value), &values); err == nil {
+			err = applyDirty(containerDirtyTag(elem), elem, u.handler.JawsSetSlice(elem, values))
+		}
+	}
+	return
+}
+
+// MultiSelect renders a multiple-selection HTML select element.
+//
+// See [MultiSelect] for handler requirements.
+func (rw RequestWriter) MultiSelect(handler named.MultiSelectHandler, params ...any) error {
+	return rw.NewUI(NewMultiSelect(handler), params...)
+}
@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/linkdata/jaws/lib/bind"
+	"github.com/linkdata/jaws/lib/named"
+)
+
+func TestMultiSelect_BoolArray(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	nba := named.NewBoolArray(true).Add("a", "A").Add("b", "B").Add("c", "C")
+	nba.Set("b", true)
+	ms := NewMultiSelect(nba)
+	elem, got := renderUI(t, rq, ms, `size="3"`)
+	mustMatch(t, `^<select id="Jid\.[0-9]+" size="3" multiple><option id="Jid\.[0-9]+" value="a">A</option>`+
+		`<option id="Jid\.[0-9]+" value="b" selected>B</option><option id="Jid\.[0-9]+" value="c">C</option></select>$`, got)
+
+	if err := ms.JawsInput(elem, `["a","c"]`); err != nil {
+		t.Fatal(err)
+	}
+	if got := nba.JawsGetSlice(elem); !slices.Equal(got, []string{"a", "c"}) {
+		t.Errorf("selected %q", got)
+	}
+	if err := ms.JawsInput(elem, `["a","c"]`); err != nil {
+		t.Errorf("unchanged selection: %v", err)
+	}
+	if err := ms.JawsInput(elem, `a`); err == nil {
+		t.Error("expected error for malformed selection")
+	}
+	ms.JawsUpdate(elem)
+	if err := NewMultiSelect(nil).JawsInput(elem, `[]`); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMultiSelect_Choices(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	var mu sync.Mutex
+	sizes := []int{30, 99}
+	choices := NewChoices(bind.NewSlice(&mu, &sizes),
+		Choice[int]{Value: 10, Label: "S"},
+		Choice[int]{Value: 20, Label: "M"},
+		Choice[int]{Value: 30, Label: "<b>L</b>"},
+	)
+	elem, got := renderUI(t, rq, NewMultiSelect(choices))
+	mustMatch(t, `^<select id="Jid\.[0-9]+" multiple><option id="Jid\.[0-9]+" value="0">S</option>`+
+		`<option id="Jid\.[0-9]+" value="1">M</option><option id="Jid\.[0-9]+" value="2" selected><b>L</b></option></select>$`, got)
+
+	if err := NewMultiSelect(choices).JawsInput(elem, `["2","0","7"]`); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(sizes, []int{10, 30}) {
+		t.Errorf("sizes %v", sizes)
+	}
+}
+
+func TestCheckboxGroup_ConcurrentToggles(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	elem := rq.NewElement(NewSpan(nil))
+	var mu sync.Mutex
+	var values []int
+	var list []Choice[int]
+	for i := range 64 {
+		list = append(list, Choice[int]{Value: i})
+	}
+	choices := NewChoices(bind.NewSlice(&mu, &values), list...)
+	var wg sync.WaitGroup
+	for i := range list {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := (choiceBool[int]{c: choices, i: i}).JawsSet(elem, true); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(values) != len(list) {
+		t.Errorf("%d of %d toggles kept", len(values), len(list))
+	}
+}
+
+func TestCheckboxGroup(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	rw := RequestWriter{Request: rq}
+
+	nba := named.NewBoolArray(true).Add("a", "A").Add("b", "B")
+	cel := rw.CheckboxGroup(nba)
+	if len(cel) != 2 {
+		t.Fatalf("got %d checkboxes", len(cel))
+	}
+	if got := string(cel[1].Checkbox("cbattr")); got != `<input id="Jid.1" type="checkbox" cbattr>` {
+		t.Errorf("checkbox %q", got)
+	}
+	if got := string(cel[1].Label()); got != `<label id="Jid.2" for="Jid.1">B</label>` {
+		t.Errorf("label %q", got)
+	}
+	cb := rq.GetElementByJid(1)
+	if err := cb.UI().(*Checkbox).JawsInput(cb, "true"); err != nil {
+		t.Fatal(err)
+	}
+	if !nba.IsChecked("b") {
+		t.Error("b not checked")
+	}
+
+	var mu sync.Mutex
+	colors := []string{"red"}
+	choices := NewChoices(bind.NewSlice(&mu, &colors), Choice[string]{"red", "Red"}, Choice[string]{"blue", "Blue"})
+	cel = rw.CheckboxGroup(choices)
+	got := string(cel[0].Checkbox()) + string(cel[0].Label()) + string(cel[1].Checkbox())
+	mustMatch(t, `^<input id="Jid\.3" type="checkbox" checked><label id="Jid\.4" for="Jid\.3">Red</label><input id="Jid\.5" type="checkbox">$`, got)
+	red, blue := rq.GetElementByJid(3), rq.GetElementByJid(5)
+	if err := blue.UI().(*Checkbox).JawsInput(blue, "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := red.UI().(*Checkbox).JawsInput(red, "false"); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(colors, []string{"blue"}) {
+		t.Errorf("colors %q", colors)
+	}
+	if err := red.UI().(*Checkbox).JawsInput(red, "false"); err != nil {
+		t.Errorf("unchanged checkbox: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	rw.CheckboxGroup("nope")
+}
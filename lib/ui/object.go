@@ -24,6 +24,12 @@ type ObjectClickedHook func(obj Object, elem *jaws.Element, click jaws.Click) (e
 // semantics.
 type ObjectContextMenuHook func(obj Object, elem *jaws.Element, click jaws.Click) (err error)
 
+// ObjectKeyHook handles a key event for an [Object].
+//
+// obj is the chain node containing the hook. See [Object] for composition
+// semantics.
+type ObjectKeyHook func(obj Object, elem *jaws.Element, key jaws.Key) (err error)
+
 // ObjectInitialHTMLAttrHook provides attributes when an [Object] is initially rendered.
 //
 // obj is the chain node containing the hook. See [Object] for composition
@@ -36,11 +42,11 @@ type ObjectInitialHTMLAttrHook = func(obj Object, elem *jaws.Element) (s templat
 
 // Object is a chainable UI object.
 //
-// Each call to [Object.Clicked], [Object.ContextMenu], or
+// Each call to [Object.Clicked], [Object.ContextMenu], [Object.Key], or
 // [Object.InitialHTMLAttr] returns a new chain node wrapping its receiver. Calls
-// to [jaws.ClickHandler.JawsClick] and
-// [jaws.ContextMenuHandler.JawsContextMenu] run the corresponding hooks from
-// newest to oldest. Dispatch continues while the result matches
+// to [jaws.ClickHandler.JawsClick], [jaws.ContextMenuHandler.JawsContextMenu]
+// and [jaws.KeyHandler.JawsKey] run the corresponding hooks from newest to
+// oldest. Dispatch continues while the result matches
 // [jaws.ErrEventUnhandled] according to [errors.Is], including when wrapped,
 // and stops at the first other result. If no hook handles the event, the invoked
 // method returns an error matching [jaws.ErrEventUnhandled]. Each hook receives
@@ -61,6 +67,7 @@ type Object interface {
 	tag.TagGetter
 	jaws.ClickHandler
 	jaws.ContextMenuHandler
+	jaws.KeyHandler
 	jaws.InitialHTMLAttrHandler
 
 	// Clicked adds fn as the newest click hook and returns the resulting [Object].
@@ -70,6 +77,9 @@ type Object interface {
 	// resulting [Object].
 	ContextMenu(fn ObjectContextMenuHook) (newobj Object)
 
+	// Key adds fn as the newest key hook and returns the resulting [Object].
+	Key(fn ObjectKeyHook) (newobj Object)
+
 	// InitialHTMLAttr adds fn as the newest initial-attribute hook and returns the
 	// resulting [Object].
 	InitialHTMLAttr(fn ObjectInitialHTMLAttrHook) (newobj Object)
@@ -79,7 +89,7 @@ var _ Object = &object{}
 
 type object struct {
 	prev *object
-	// The defined hook types distinguish click from context-menu callbacks.
+	// The defined hook types distinguish click, context-menu and key callbacks.
 	handler any
 }
 
@@ -97,6 +107,13 @@ func (obj *object) ContextMenu(fn ObjectContextMenuHook) Object {
 	}
 }
 
+func (obj *object) Key(fn ObjectKeyHook) Object {
+	return &object{
+		prev:    obj,
+		handler: fn,
+	}
+}
+
 func (obj *object) InitialHTMLAttr(fn ObjectInitialHTMLAttrHook) Object {
 	return &object{
 		prev:    obj,
@@ -141,6 +158,19 @@ func (obj *object) JawsContextMenu(elem *jaws.Element, click jaws.Click) (err er
 	return
 }
 
+func (obj *object) JawsKey(elem *jaws.Element, key jaws.Key) (err error) {
+	err = jaws.ErrEventUnhandled
+	for obj != nil {
+		if fn, ok := obj.handler.(ObjectKeyHook); ok {
+			if err = fn(obj, elem, key); !errors.Is(err, jaws.ErrEventUnhandled) {
+				break
+			}
+		}
+		obj = obj.prev
+	}
+	return
+}
+
 func (obj *object) JawsInitialHTMLAttr(elem *jaws.Element) (attr template.HTMLAttr) {
 	for obj != nil {
 		if fn, ok := obj.handler.(ObjectInitialHTMLAttrHook); ok {
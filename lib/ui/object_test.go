@@ -160,6 +160,28 @@ func TestObject_ContextMenu_FallthroughOrder(t *testing.T) {
 	}
 }
 
+func TestObject_Key_FallthroughOrder(t *testing.T) {
+	var keys []string
+	obj := New("x").
+		Key(func(_ Object, _ *jaws.Element, key jaws.Key) error {
+			keys = append(keys, "old:"+key.Key)
+			return nil
+		}).
+		Key(func(_ Object, _ *jaws.Element, key jaws.Key) error {
+			keys = append(keys, "new:"+key.Key)
+			return jaws.ErrEventUnhandled
+		})
+	if err := obj.JawsKey(nil, jaws.Key{Key: "Escape"}); err != nil {
+		t.Fatalf("want nil got %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "new:Escape" || keys[1] != "old:Escape" {
+		t.Fatalf("unexpected order %v", keys)
+	}
+	if err := New("x").JawsKey(nil, jaws.Key{Key: "a"}); err != jaws.ErrEventUnhandled {
+		t.Fatalf("want ErrEventUnhandled got %v", err)
+	}
+}
+
 func TestObject_EventUnhandledCanBeWrapped(t *testing.T) {
 	older := fmt.Errorf("older: %w", jaws.ErrEventUnhandled)
 	newer := fmt.Errorf("newer: %w", jaws.ErrEventUnhandled)
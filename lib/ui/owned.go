@@ -30,6 +30,18 @@ func appendOwnedBy(dst []*jaws.Element, elem *jaws.Element) []*jaws.Element {
 		if st != nil {
 			owned = st.takeOwnedElements()
 		}
+	case *tableState:
+		if st != nil {
+			owned = st.takeOwnedElements()
+		}
+	case *virtualState:
+		if st != nil {
+			owned = st.takeOwnedElements()
+		}
+	case *autocompleteState:
+		if st != nil {
+			owned = st.takeOwnedElements()
+		}
 	}
 	return appendOwnedElements(dst, owned)
 }
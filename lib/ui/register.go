@@ -61,7 +61,7 @@ func (rw RequestWriter) Register(updater jaws.Updater, params ...any) jid.Jid {
 	// The registerUI Element's UI is not the updater, so events reach the
 	// updater only through the element's handler list, not the elem.UI() fallback.
 	switch updater.(type) {
-	case jaws.InputHandler, jaws.ClickHandler, jaws.ContextMenuHandler:
+	case jaws.InputHandler, jaws.ClickHandler, jaws.ContextMenuHandler, jaws.KeyHandler, jaws.OrderHandler, jaws.DOMEventHandler, jaws.UploadHandler:
 		elem.AddHandlers(updater)
 	}
 	elem.ApplyParams(params)
@@ -98,23 +98,33 @@ func TestRequestWriter_MethodsAndWidgetHelpers(t *testing.T) {
 		func() error { return rw.A("a") },
 		func() error { return rw.Button("b") },
 		func() error { return rw.Checkbox(true) },
+		func() error { return rw.Color(RGB{R: 1}) },
 		func() error { return rw.Container("section", tc) },
 		func() error { return rw.Date(date) },
+		func() error { return rw.DateTimeLocal(date) },
 		func() error { return rw.Div("d") },
+		func() error { return rw.Email("a@b.c") },
 		func() error { return rw.Img("img.png") },
 		func() error { return rw.Label("l") },
 		func() error { return rw.Li("li") },
+		func() error { return rw.Month(date) },
+		func() error { return rw.MultiSelect(named.NewBoolArray(true).Add("m", "M")) },
 		func() error { return rw.Number(1.2) },
 		func() error { return rw.Password("pw") },
 		func() error { return rw.Radio(false) },
 		func() error { return rw.Range(2.3) },
+		func() error { return rw.Search("q") },
 		func() error { return rw.Select(sh) },
 		func() error { return rw.Span("sp") },
 		func() error { return rw.Tbody(tc) },
 		func() error { return rw.Td("td") },
+		func() error { return rw.Tel("555") },
 		func() error { return rw.Text("txt") },
 		func() error { return rw.Textarea("ta") },
+		func() error { return rw.Time(date) },
 		func() error { return rw.Tr("tr") },
+		func() error { return rw.URL("https://example.com") },
+		func() error { return rw.Week(date) },
 	}
 	for i, call := range calls {
 		if err := call(); err != nil {
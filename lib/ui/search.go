@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"io"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+)
+
+// Search renders an HTML search input bound to a string setter.
+//
+// A Search value must back at most one live [jaws.Element]. Construct distinct
+// Search values over the same setter to render one bound value more than once.
+// Pass [jaws.InputDebounce] as a render parameter to search once the user
+// pauses typing.
+type Search struct{ InputText }
+
+// NewSearch returns a search input widget bound to g.
+//
+// For writable use, g must provide the setter-derived dirty target described by [Input].
+func NewSearch(g bind.Setter[string]) *Search { return &Search{InputText{Setter: g}} }
+
+// JawsRender renders ui as an HTML search input.
+func (u *Search) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	return u.renderStringInput(elem, w, "search", params...)
+}
+
+// Search renders an HTML search input.
+func (rw RequestWriter) Search(value any, params ...any) error {
+	return rw.NewUI(NewSearch(bind.MakeSetter[string](value)), params...)
+}
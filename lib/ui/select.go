@@ -26,7 +26,7 @@ import (
 //
 // A typed-nil handler is called normally and must tolerate its nil receiver.
 //
-// Select supports one selected option; a multiple select is unsupported.
+// Select supports one selected option; use [MultiSelect] for a multiple select.
 // A completed native form reset changes browser state without an input/change
 // event, so it does not update the Go binding. Reset the authoritative selection
 // from a JaWS-handled button with type="button", then dirty its tag.
@@ -89,7 +89,8 @@ func (u Select) JawsInput(elem *jaws.Element, value string) (err error) {
 // Select renders a single-selection HTML select element.
 //
 // HTML attribute params are applied to the select element, but the multiple
-// attribute is unsupported because Select stores one selected option value.
+// attribute is unsupported because Select stores one selected option value;
+// use [RequestWriter.MultiSelect] instead.
 // See [Select] for handler requirements and native reset semantics.
 func (rw RequestWriter) Select(handler named.SelectHandler, params ...any) error {
 	return rw.NewUI(NewSelect(handler), params...)
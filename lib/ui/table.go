@@ -0,0 +1,420 @@
+package ui
+
+import (
+	"html/template"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/htmlio"
+)
+
+// TableSource supplies the columns and rows of a [Table].
+//
+// Filtering, sorting and paging are done by the source, typically in a database
+// query, so the Table only ever holds the rows of the page it shows. The elem
+// argument is the Table's [jaws.Element]. A source shared between Requests must
+// be safe for concurrent use.
+type TableSource interface {
+	// JawsTableColumns returns the table's columns. It is called once, when the
+	// Table renders.
+	JawsTableColumns(elem *jaws.Element) []TableColumn
+	// JawsTableCount returns the number of rows matching filter.
+	JawsTableCount(elem *jaws.Element, filter string) int
+	// JawsTableRows returns the rows of the page described by query. Rows beyond
+	// query.Limit are ignored.
+	JawsTableRows(elem *jaws.Element, query TableQuery) []TableRow
+}
+
+// TableColumn describes one column of a [Table].
+type TableColumn struct {
+	Title   string // Title is the header text.
+	SortKey string // SortKey is passed in TableQuery.SortKey when sorting by the column; if empty, the column is not sortable.
+}
+
+// TableQuery describes the page of rows a [Table] shows.
+type TableQuery struct {
+	Filter     string // Filter is the text in the filter input with surrounding whitespace removed.
+	SortKey    string // SortKey is the SortKey of the sort column, or empty if unsorted.
+	Descending bool   // Descending reports whether to sort in descending order.
+	Offset     int    // Offset is the index of the page's first row among the matching rows.
+	Limit      int    // Limit is the page size.
+}
+
+// TableRow is one row of a [Table].
+//
+// Key identifies the row, such as by a database ID, and must be comparable and
+// equal to itself; an unusable Key terminates the Request like an unusable
+// [Container] child. A row is re-rendered only when its Key or Cells change.
+type TableRow struct {
+	Key   any
+	Cells []template.HTML // Cells are the row's cells as trusted HTML, in column order.
+}
+
+// Table renders a data grid over a [TableSource] with a filter input, sortable
+// column headers and page controls.
+//
+// The rows are a [Tbody] reconciled like a [Container] child list: rows whose
+// Key and Cells are unchanged keep their Elements and DOM nodes, and only new or
+// changed rows are sent to the browser. Filter, sort order and page are kept in
+// each Table Element's state, so equal Table values may back multiple live
+// Elements with independent views. Mark the source dirty to refresh the rows
+// after its data changes.
+//
+// The Table's Element is the HTML table, so render parameters such as
+// class="table" apply to it, except [TableFilterParams], which apply to the
+// filter input. The filter input and header row are in the thead, and the page
+// controls in the tfoot. The filter input sends its text once typing pauses. Use Table as a value; taking its address
+// changes identity and is unsupported.
+type Table struct {
+	source   TableSource
+	pageSize int
+}
+
+var _ jaws.UI = Table{}
+
+// TableFilterParams are render parameters for the filter input of a [Table],
+// such as its placeholder and aria-label. Pass them among the Table's own
+// render parameters.
+type TableFilterParams []any
+
+// tableFilterDebounce is how long the user must pause typing before the client
+// sends the filter text.
+const tableFilterDebounce = 200 * time.Millisecond
+
+// NewTable returns a Table showing pageSize rows from source per page, or 25
+// rows if pageSize is not positive.
+func NewTable(source TableSource, pageSize int) Table {
+	if pageSize < 1 {
+		pageSize = 25
+	}
+	return Table{source: source, pageSize: pageSize}
+}
+
+// tableState is the per-Element view state a Table claims while rendering.
+type tableState struct {
+	source   TableSource
+	pageSize int
+	elem     *jaws.Element // the Table's Element
+	columns  []TableColumn
+	mu       sync.Mutex
+	filter   string
+	sortCol  int // index into columns, or -1 if unsorted
+	desc     bool
+	page     int
+	count    int           // matching rows as of the last rows fetch
+	header   template.HTML // last rendered header cells
+	pager    template.HTML // last rendered page controls
+	owned    []*jaws.Element
+}
+
+// JawsRender renders u as an HTML table.
+//
+// If elem's widget state is occupied, JawsRender returns
+// [jaws.ErrElementStateClaimed] without rendering.
+func (u Table) JawsRender(elem *jaws.Element, w io.Writer, params []any) (err error) {
+	st := &tableState{source: u.source, pageSize: u.pageSize, elem: elem, sortCol: -1}
+	if err = jaws.SetElementState(elem, st); err != nil {
+		return
+	}
+	elem.ApplyGetter(u.source)
+	elem.Tag(st)
+	st.columns = u.source.JawsTableColumns(elem)
+	var filterParams []any
+	params = slices.DeleteFunc(slices.Clone(params), func(p any) (ok bool) {
+		var fp TableFilterParams
+		if fp, ok = p.(TableFilterParams); ok {
+			filterParams = append(filterParams, fp...)
+		}
+		return
+	})
+	attrs := elem.ApplyParams(params)
+	colspan := template.HTMLAttr(`colspan="` + strconv.Itoa(max(len(st.columns), 1)) + `"`)
+
+	filter := elem.Request.NewElement(tableFilter{st})
+	header := elem.Request.NewElement(tableHeader{st})
+	body := elem.Request.NewElement(NewTbody(tableRows{st}))
+	pager := elem.Request.NewElement(tablePager{st})
+	st.mu.Lock()
+	st.owned = []*jaws.Element{filter, header, body, pager}
+	st.mu.Unlock()
+
+	b := elem.Jid().AppendStartTagAttr(nil, "table")
+	b = htmlio.AppendAttrs(b, attrs)
+	b = append(b, "><thead><tr><th "...)
+	b = append(b, colspan...)
+	b = append(b, '>')
+	if _, err = w.Write(b); err == nil {
+		if err = filter.JawsRender(w, filterParams); err == nil {
+			if _, err = io.WriteString(w, "</th></tr>"); err == nil {
+				if err = header.JawsRender(w, nil); err == nil {
+					if _, err = io.WriteString(w, "</thead>"); err == nil {
+						if err = body.JawsRender(w, nil); err == nil {
+							if _, err = io.WriteString(w, "<tfoot><tr>"); err == nil {
+								if err = pager.JawsRender(w, []any{colspan}); err == nil {
+									_, err = io.WriteString(w, "</tr></tfoot></table>")
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	if err != nil {
+		deleteOwnedElements(elem.Request, st.takeOwnedElements())
+	}
+	return
+}
+
+// JawsUpdate refreshes the header, rows and page controls of u.
+func (u Table) JawsUpdate(elem *jaws.Element) {
+	if st, ok := jaws.ElementState(elem).(*tableState); ok && st != nil {
+		st.mu.Lock()
+		parts := append([]*jaws.Element(nil), st.owned...)
+		st.mu.Unlock()
+		// The rows update before the page controls, which show their count.
+		for _, part := range parts {
+			part.JawsUpdate()
+		}
+	}
+}
+
+// takeOwnedElements returns the Table's part Elements and forgets them,
+// transferring responsibility for unregistering them to the caller.
+func (st *tableState) takeOwnedElements() (owned []*jaws.Element) {
+	st.mu.Lock()
+	owned, st.owned = st.owned, nil
+	st.mu.Unlock()
+	return
+}
+
+// changed marks the Table dirty after its view changed.
+func (st *tableState) changed() {
+	st.elem.Dirty(st)
+}
+
+// query returns the query for the current page, refreshing the matching row
+// count and moving to the last page if the current one no longer exists.
+func (st *tableState) query() (q TableQuery) {
+	st.mu.Lock()
+	filter := st.filter
+	st.mu.Unlock()
+	count := st.source.JawsTableCount(st.elem, filter)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.count = max(count, 0)
+	st.page = max(min(st.page, st.pagesLocked()-1), 0)
+	q = TableQuery{Filter: st.filter, Offset: st.page * st.pageSize, Limit: st.pageSize}
+	if st.sortCol >= 0 {
+		q.SortKey = st.columns[st.sortCol].SortKey
+		q.Descending = st.desc
+	}
+	return
+}
+
+func (st *tableState) pagesLocked() int {
+	return max((st.count+st.pageSize-1)/st.pageSize, 1)
+}
+
+// swapLocked stores html in *last and reports whether it differed.
+func swapLocked(last *template.HTML, html template.HTML) (changed bool) {
+	changed = *last != html
+	*last = html
+	return
+}
+
+// tableFilter is the filter input of a Table.
+type tableFilter struct{ st *tableState }
+
+func (u tableFilter) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	u.st.mu.Lock()
+	filter := u.st.filter
+	u.st.mu.Unlock()
+	// Caller params come first, so the HTML parser keeps theirs on conflict.
+	params = append(params[:len(params):len(params)], jaws.InputDebounce(tableFilterDebounce))
+	attrs := elem.ApplyParams(params)
+	return htmlio.WriteHTMLInput(w, elem.Jid(), "search", filter, attrs)
+}
+
+// JawsUpdate does nothing; the browser owns the filter text.
+func (u tableFilter) JawsUpdate(elem *jaws.Element) {}
+
+// JawsInput filters the rows and returns to the first page.
+func (u tableFilter) JawsInput(elem *jaws.Element, value string) (err error) {
+	value = strings.TrimSpace(value)
+	u.st.mu.Lock()
+	changed := u.st.filter != value
+	if changed {
+		u.st.filter = value
+		u.st.page = 0
+	}
+	u.st.mu.Unlock()
+	if changed {
+		u.st.changed()
+	}
+	return
+}
+
+// tableHeader is the header row of a Table.
+type tableHeader struct{ st *tableState }
+
+func (u tableHeader) html() template.HTML {
+	var sb strings.Builder
+	u.st.mu.Lock()
+	defer u.st.mu.Unlock()
+	for i, col := range u.st.columns {
+		title := template.HTMLEscapeString(col.Title)
+		if col.SortKey == "" {
+			sb.WriteString("<th>" + title + "</th>")
+			continue
+		}
+		sort, arrow := "none", ""
+		if i == u.st.sortCol {
+			sort, arrow = "ascending", " &#9650;"
+			if u.st.desc {
+				sort, arrow = "descending", " &#9660;"
+			}
+		}
+		sb.WriteString(`<th aria-sort="` + sort + `"><button type="button" class="jaws-table-sort" name="` +
+			strconv.Itoa(i) + `">` + title + arrow + "</button></th>")
+	}
+	return template.HTML(sb.String()) // #nosec G203
+}
+
+func (u tableHeader) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	html := u.html()
+	u.st.mu.Lock()
+	u.st.header = html
+	u.st.mu.Unlock()
+	return htmlio.WriteHTMLInner(w, elem.Jid(), "tr", "", html, elem.ApplyParams(params)...)
+}
+
+func (u tableHeader) JawsUpdate(elem *jaws.Element) {
+	html := u.html()
+	u.st.mu.Lock()
+	changed := swapLocked(&u.st.header, html)
+	u.st.mu.Unlock()
+	if changed {
+		elem.SetInner(html)
+	}
+}
+
+// JawsClick sorts by the clicked column, reversing the order if it already is
+// the sort column, and returns to the first page.
+func (u tableHeader) JawsClick(elem *jaws.Element, click jaws.Click) (err error) {
+	err = jaws.ErrEventUnhandled
+	if i, e := strconv.Atoi(click.Name); e == nil && i >= 0 && i < len(u.st.columns) && u.st.columns[i].SortKey != "" {
+		u.st.mu.Lock()
+		u.st.desc = i == u.st.sortCol && !u.st.desc
+		u.st.sortCol = i
+		u.st.page = 0
+		u.st.mu.Unlock()
+		u.st.changed()
+		err = nil
+	}
+	return
+}
+
+// tableRows provides the rows of a Table's Tbody.
+type tableRows struct{ st *tableState }
+
+func (u tableRows) JawsContains(elem *jaws.Element) (contents []jaws.UI) {
+	q := u.st.query()
+	rows := u.st.source.JawsTableRows(u.st.elem, q)
+	rows = rows[:min(len(rows), q.Limit)]
+	for _, row := range rows {
+		var sb strings.Builder
+		for _, cell := range row.Cells {
+			sb.WriteString("<td>" + string(cell) + "</td>")
+		}
+		contents = append(contents, tableRow{key: row.Key, cells: template.HTML(sb.String())}) // #nosec G203
+	}
+	return
+}
+
+// tableRow is one row of a Table. Rows compare equal when their key and
+// cells are, which lets the Tbody retain unchanged rows.
+type tableRow struct {
+	key   any
+	cells template.HTML
+}
+
+func (u tableRow) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	return htmlio.WriteHTMLInner(w, elem.Jid(), "tr", "", u.cells, elem.ApplyParams(params)...)
+}
+
+// JawsUpdate does nothing; a changed row is a different tableRow.
+func (u tableRow) JawsUpdate(elem *jaws.Element) {}
+
+// tablePager is the page controls of a Table.
+type tablePager struct{ st *tableState }
+
+func (u tablePager) html() template.HTML {
+	u.st.mu.Lock()
+	page, pages := u.st.page+1, u.st.pagesLocked()
+	u.st.mu.Unlock()
+	button := func(name, label, text string, disabled bool) string {
+		s := `<button type="button" class="jaws-table-page" name="` + name + `" aria-label="` + label + `"`
+		if disabled {
+			s += " disabled"
+		}
+		return s + ">" + text + "</button>"
+	}
+	return template.HTML(button("first", "First page", "&laquo;", page == 1) + // #nosec G203
+		button("prev", "Previous page", "&lsaquo;", page == 1) +
+		" <span>Page " + strconv.Itoa(page) + " of " + strconv.Itoa(pages) + "</span> " +
+		button("next", "Next page", "&rsaquo;", page == pages) +
+		button("last", "Last page", "&raquo;", page == pages))
+}
+
+func (u tablePager) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	html := u.html()
+	u.st.mu.Lock()
+	u.st.pager = html
+	u.st.mu.Unlock()
+	return htmlio.WriteHTMLInner(w, elem.Jid(), "td", "", html, elem.ApplyParams(params)...)
+}
+
+func (u tablePager) JawsUpdate(elem *jaws.Element) {
+	html := u.html()
+	u.st.mu.Lock()
+	changed := swapLocked(&u.st.pager, html)
+	u.st.mu.Unlock()
+	if changed {
+		elem.SetInner(html)
+	}
+}
+
+// JawsClick moves to the first, previous, next or last page.
+func (u tablePager) JawsClick(elem *jaws.Element, click jaws.Click) (err error) {
+	u.st.mu.Lock()
+	page, last := u.st.page, u.st.pagesLocked()-1
+	switch click.Name {
+	case "first":
+		u.st.page = 0
+	case "prev":
+		u.st.page = max(page-1, 0)
+	case "next":
+		u.st.page = min(page+1, last)
+	case "last":
+		u.st.page = last
+	default:
+		err = jaws.ErrEventUnhandled
+	}
+	changed := u.st.page != page
+	u.st.mu.Unlock()
+	if changed {
+		u.st.changed()
+	}
+	return
+}
+
+// Table renders a data grid over source showing pageSize rows per page.
+func (rw RequestWriter) Table(source TableSource, pageSize int, params ...any) error {
+	return rw.NewUI(NewTable(source, pageSize), params...)
+}
@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"cmp"
+	"html/template"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/what"
+)
+
+type testTablePerson struct {
+	id   int
+	name string
+	age  int
+}
+
+type testTableSource struct {
+	mu     sync.Mutex
+	people []testTablePerson
+	last   TableQuery
+}
+
+func (s *testTableSource) JawsTableColumns(elem *jaws.Element) []TableColumn {
+	return []TableColumn{{Title: "Name", SortKey: "name"}, {Title: "Age", SortKey: "age"}, {Title: "<Notes>"}}
+}
+
+func (s *testTableSource) matching(filter string) (people []testTablePerson) {
+	for _, p := range s.people {
+		if strings.Contains(p.name, filter) {
+			people = append(people, p)
+		}
+	}
+	return
+}
+
+func (s *testTableSource) JawsTableCount(elem *jaws.Element, filter string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.matching(filter))
+}
+
+func (s *testTableSource) JawsTableRows(elem *jaws.Element, query TableQuery) (rows []TableRow) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last = query
+	people := s.matching(query.Filter)
+	slices.SortStableFunc(people, func(a, b testTablePerson) (n int) {
+		switch query.SortKey {
+		case "name":
+			n = strings.Compare(a.name, b.name)
+		case "age":
+			n = cmp.Compare(a.age, b.age)
+		}
+		if query.Descending {
+			n = -n
+		}
+		return
+	})
+	people = people[min(query.Offset, len(people)):]
+	for _, p := range people[:min(query.Limit+1, len(people))] {
+		rows = append(rows, TableRow{Key: p.id, Cells: []template.HTML{
+			template.HTML(template.HTMLEscapeString(p.name)),
+			template.HTML(strconv.Itoa(p.age)),
+		}})
+	}
+	return
+}
+
+func TestTable(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	src := &testTableSource{people: []testTablePerson{
+		{1, "carol", 41}, {2, "alice", 30}, {3, "bob", 25}, {4, "dave", 30}, {5, "eve", 19},
+	}}
+	elem, got := renderUI(t, rq, NewTable(src, 2), `class="table"`, TableFilterParams{`aria-label="Sök"`})
+	mustMatch(t, `^<table id="Jid\.[0-9]+" class="table"><thead><tr><th colspan="3">`+
+		`<input id="Jid\.[0-9]+" type="search" aria-label="Sök" data-jawsinput="debounce=200"></th></tr>`+
+		`<tr id="Jid\.[0-9]+"><th aria-sort="none"><button type="button" class="jaws-table-sort" name="0">Name</button></th>`+
+		`<th aria-sort="none"><button type="button" class="jaws-table-sort" name="1">Age</button></th><th>&lt;Notes&gt;</th></tr></thead>`+
+		`<tbody id="Jid\.[0-9]+"><tr id="Jid\.[0-9]+"><td>carol</td><td>41</td></tr><tr id="Jid\.[0-9]+"><td>alice</td><td>30</td></tr></tbody>`+
+		`<tfoot><tr><td id="Jid\.[0-9]+" colspan="3">.*<span>Page 1 of 3</span>.*</td></tr></tfoot></table>$`, got)
+
+	st := jaws.ElementState(elem).(*tableState)
+	filter, header, body, pager := st.owned[0], st.owned[1], st.owned[2], st.owned[3]
+	rows := func() (names []string) {
+		for _, childElem := range containerElements(t, body) {
+			names = append(names, string(childElem.UI().(tableRow).cells))
+		}
+		return
+	}
+
+	// Rows beyond the page size are ignored.
+	if got := len(rows()); got != 2 {
+		t.Fatalf("rendered %d rows", got)
+	}
+
+	// Sorting by a column, then reversing it.
+	click := func(part *jaws.Element, name string) error {
+		t.Helper()
+		err := jaws.CallEventHandlers(part.UI(), part, what.Click, "0 0 0 "+name)
+		elem.JawsUpdate()
+		return err
+	}
+	if err := click(header, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if src.last.SortKey != "age" || src.last.Descending || src.last.Offset != 0 || src.last.Limit != 2 {
+		t.Errorf("query %+v", src.last)
+	}
+	if got := rows(); !slices.Equal(got, []string{"<td>eve</td><td>19</td>", "<td>bob</td><td>25</td>"}) {
+		t.Errorf("sorted rows %q", got)
+	}
+	if err := click(header, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if !src.last.Descending {
+		t.Errorf("query %+v", src.last)
+	}
+	if !strings.Contains(string(st.header), `<th aria-sort="descending">`) {
+		t.Errorf("header %q", st.header)
+	}
+	if err := click(header, "2"); err != jaws.ErrEventUnhandled {
+		t.Errorf("unsortable column: %v", err)
+	}
+
+	// Paging.
+	if err := click(pager, "last"); err != nil {
+		t.Fatal(err)
+	}
+	if src.last.Offset != 4 || !strings.Contains(string(st.pager), "Page 3 of 3") {
+		t.Errorf("last page: query %+v, pager %q", src.last, st.pager)
+	}
+	if err := click(pager, "prev"); err != nil {
+		t.Fatal(err)
+	}
+	if src.last.Offset != 2 {
+		t.Errorf("previous page: query %+v", src.last)
+	}
+
+	// Unchanged rows keep their Elements; changed ones are replaced.
+	before := containerElements(t, body)
+	src.mu.Lock()
+	src.people[2].name = "bobby" // id 3, on this page with dave
+	src.mu.Unlock()
+	elem.JawsUpdate()
+	after := containerElements(t, body)
+	if len(after) != 2 {
+		t.Fatalf("rows after change %q", rows())
+	}
+	for i := range after {
+		key := after[i].UI().(tableRow).key
+		if retained := slices.Contains(before, after[i]); retained != (key == 4) {
+			t.Errorf("row %v retained %v", key, retained)
+		}
+	}
+
+	// Filtering returns to the first page and clamps the page count.
+	if err := jaws.CallEventHandlers(filter.UI(), filter, what.Input, "  a "); err != nil {
+		t.Fatal(err)
+	}
+	elem.JawsUpdate()
+	if src.last.Filter != "a" || src.last.Offset != 0 || !strings.Contains(string(st.pager), "Page 1 of 2") {
+		t.Errorf("filtered: query %+v, pager %q", src.last, st.pager)
+	}
+
+	// Removing the Table unregisters its parts and rows.
+	rowElem := containerElements(t, body)[0]
+	deleteOwnedElements(rq, []*jaws.Element{elem})
+	for _, part := range []*jaws.Element{filter, header, body, pager, rowElem} {
+		if !part.Deleted() {
+			t.Errorf("%v not deleted", part)
+		}
+	}
+}
+
+func TestTable_RequestWriter(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	var sb strings.Builder
+	rw := RequestWriter{Request: rq, Writer: &sb}
+	if err := rw.Table(&testTableSource{}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if NewTable(nil, 0).pageSize != 25 {
+		t.Error("default page size")
+	}
+	mustMatch(t, `<span>Page 1 of 1</span>`, sb.String())
+}
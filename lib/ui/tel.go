@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"io"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+)
+
+// Tel renders an HTML tel input bound to a string setter.
+//
+// Browsers do not validate telephone numbers, but may offer a telephone
+// keypad. A Tel value must back at most one live [jaws.Element]. Construct
+// distinct Tel values over the same setter to render one bound value more than
+// once.
+type Tel struct{ InputText }
+
+// NewTel returns a tel input widget bound to g.
+//
+// For writable use, g must provide the setter-derived dirty target described by [Input].
+func NewTel(g bind.Setter[string]) *Tel { return &Tel{InputText{Setter: g}} }
+
+// JawsRender renders ui as an HTML tel input.
+func (u *Tel) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	return u.renderStringInput(elem, w, "tel", params...)
+}
+
+// Tel renders an HTML tel input.
+func (rw RequestWriter) Tel(value any, params ...any) error {
+	return rw.NewUI(NewTel(bind.MakeSetter[string](value)), params...)
+}
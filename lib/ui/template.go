@@ -56,6 +56,7 @@ var (
 	_ jaws.ClickHandler       = Template{} // statically ensure interface is defined
 	_ jaws.ContextMenuHandler = Template{} // statically ensure interface is defined
 	_ jaws.InputHandler       = Template{} // statically ensure interface is defined
+	_ jaws.KeyHandler         = Template{} // statically ensure interface is defined
 )
 
 // templateState is the per-Element state a Template claims while rendering.
@@ -276,6 +277,34 @@ func (tmpl Template) JawsContextMenu(elem *jaws.Element, click jaws.Click) (err
 	return
 }
 
+// JawsKey delegates key events to t.Dot when it implements [jaws.KeyHandler].
+func (tmpl Template) JawsKey(elem *jaws.Element, key jaws.Key) (err error) {
+	err = jaws.ErrEventUnhandled
+	if h, ok := tmpl.Dot.(jaws.KeyHandler); ok {
+		err = h.JawsKey(elem, key)
+	}
+	return
+}
+
+// JawsOrder delegates reorder events to t.Dot when it implements [jaws.OrderHandler].
+func (tmpl Template) JawsOrder(elem *jaws.Element, children []jaws.UI) (err error) {
+	err = jaws.ErrEventUnhandled
+	if h, ok := tmpl.Dot.(jaws.OrderHandler); ok {
+		err = h.JawsOrder(elem, children)
+	}
+	return
+}
+
+// JawsDOMEvent delegates forwarded DOM events to t.Dot when it implements
+// [jaws.DOMEventHandler].
+func (tmpl Template) JawsDOMEvent(elem *jaws.Element, ev jaws.DOMEvent) (err error) {
+	err = jaws.ErrEventUnhandled
+	if h, ok := tmpl.Dot.(jaws.DOMEventHandler); ok {
+		err = h.JawsDOMEvent(elem, ev)
+	}
+	return
+}
+
 // JawsInput delegates input events to t.Dot when it implements [jaws.InputHandler].
 func (tmpl Template) JawsInput(elem *jaws.Element, value string) (err error) {
 	err = jaws.ErrEventUnhandled
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"io"
+	"time"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+)
+
+// Time renders an HTML time input bound to a time value setter.
+//
+// A Time value must back at most one live [jaws.Element]. Construct distinct Time
+// values over the same setter to render one bound value more than once.
+//
+// A browser edit changes the time of day, keeping the calendar date the bound
+// value has in the widget's location. See [InputTime].
+type Time struct{ InputTime }
+
+// NewTime returns a time input widget bound to g, whose browser text is in
+// location loc. If loc is nil, the bound value's own location is used.
+//
+// For writable use, g must provide the setter-derived dirty target described by [Input].
+func NewTime(g bind.Setter[time.Time], loc *time.Location) *Time {
+	return &Time{InputTime{Setter: g, kind: timeKindTime, loc: loc}}
+}
+
+// JawsRender renders ui as an HTML time input.
+func (u *Time) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	return u.renderTimeInput(elem, w, params...)
+}
+
+// Time renders an HTML time input in the bound value's own location.
+func (rw RequestWriter) Time(value any, params ...any) error {
+	return rw.NewUI(NewTime(bind.MakeSetter[time.Time](value), nil), params...)
+}
@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+	"github.com/linkdata/jaws/lib/htmlio"
+)
+
+// InputTime is the reusable base for the [Time], [DateTimeLocal], [Month] and
+// [Week] input widgets.
+//
+// A widget embedding InputTime must back at most one live [jaws.Element].
+//
+// The browser shows and sends wall-clock text without a time zone. InputTime
+// formats the bound [time.Time] in its location, and parses browser text in
+// that location, so edits keep the bound value's zone. The zero [time.Time]
+// renders as an empty input, and clearing the input sets it. A time picked
+// while the bound value is zero is on January 1st 1970. Only years 1 through
+// 9999 round-trip.
+//
+// Malformed browser text is rejected without calling the setter or returning
+// an error, and the canonical value is restored by updating only the
+// originating Element.
+type InputTime struct {
+	Input
+	bind.Setter[time.Time]
+	kind timeKind
+	loc  *time.Location
+}
+
+// timeKind selects the HTML input type, formatter and parser of an InputTime.
+type timeKind uint8
+
+const (
+	timeKindTime timeKind = iota
+	timeKindDateTimeLocal
+	timeKindMonth
+	timeKindWeek
+)
+
+var timeKindHTMLType = [...]string{
+	timeKindTime:          "time",
+	timeKindDateTimeLocal: "datetime-local",
+	timeKindMonth:         "month",
+	timeKindWeek:          "week",
+}
+
+// location returns the location browser text is in, given the bound value v.
+func (u *InputTime) location(v time.Time) *time.Location {
+	if u.loc != nil {
+		return u.loc
+	}
+	return v.Location()
+}
+
+func (u *InputTime) str(v time.Time) string {
+	return formatTimeInput(u.kind, v, u.location(v))
+}
+
+func (u *InputTime) renderTimeInput(elem *jaws.Element, w io.Writer, params ...any) (err error) {
+	getterAttrs := u.applyGetterAttrs(elem, u.Setter)
+	attrs := append(elem.ApplyParams(params), getterAttrs...)
+	// As for InputDate, dedup on the rendered text rather than the time.Time.
+	s := u.str(u.JawsGet(elem))
+	u.Last.Store(s)
+	err = htmlio.WriteHTMLInput(w, elem.Jid(), timeKindHTMLType[u.kind], s, attrs)
+	return
+}
+
+// JawsUpdate updates the input value when the bound time value changes.
+func (u *InputTime) JawsUpdate(elem *jaws.Element) {
+	if s := u.str(u.JawsGet(elem)); u.Last.Swap(s) != s {
+		elem.SetValue(s)
+	}
+}
+
+// JawsInput settles a browser-side time edit.
+//
+// Accepted text is reconciled through the formatter, including when the setter
+// returns [jaws.ErrValueUnchanged], so equivalent spellings such as "09:30:00"
+// and "09:30" settle on the canonical one.
+func (u *InputTime) JawsInput(elem *jaws.Element, text string) (err error) {
+	cur := u.JawsGet(elem)
+	v, accepted := parseTimeInput(u.kind, text, cur, u.location(cur))
+	// Stored before the setter runs, as for numeric inputs. Rejected text is
+	// never produced by the formatter, so the next update restores the value.
+	u.Last.Store(text)
+	if !accepted {
+		elem.Dirty(elem)
+		return
+	}
+	err = u.Setter.JawsSet(elem, v)
+	if errors.Is(err, jaws.ErrValueUnchanged) {
+		elem.Dirty(elem)
+		err = nil
+		return
+	}
+	err = u.maybeDirty(elem, err)
+	return
+}
+
+// formatTimeInput returns the browser text for v in loc. Seconds are included
+// only when v has them, as browsers do.
+func formatTimeInput(kind timeKind, v time.Time, loc *time.Location) (text string) {
+	if v.IsZero() {
+		return
+	}
+	v = v.In(loc)
+	switch kind {
+	case timeKindTime:
+		text = v.Format(clockLayout(v))
+	case timeKindDateTimeLocal:
+		text = v.Format("2006-01-02T" + clockLayout(v))
+	case timeKindMonth:
+		text = v.Format("2006-01")
+	case timeKindWeek:
+		year, week := v.ISOWeek()
+		text = fmt.Sprintf("%04d-W%02d", year, week)
+	}
+	return
+}
+
+func clockLayout(v time.Time) string {
+	if v.Second() == 0 && v.Nanosecond() == 0 {
+		return "15:04"
+	}
+	return "15:04:05.999"
+}
+
+// parseTimeInput parses browser text in loc. A time-only value keeps the
+// calendar date that cur has in loc, or takes January 1st 1970 if cur is the
+// zero time, since midnight on the zero time's date would be the zero time
+// and render empty. The other kinds ignore cur.
+func parseTimeInput(kind timeKind, text string, cur time.Time, loc *time.Location) (v time.Time, ok bool) {
+	if text == "" {
+		ok = true
+		return
+	}
+	switch kind {
+	case timeKindTime:
+		var clock time.Time
+		if clock, ok = parseClock("", text, time.UTC); ok {
+			year, month, day := cur.In(loc).Date()
+			if cur.IsZero() {
+				year, month, day = 1970, time.January, 1
+			}
+			v = time.Date(year, month, day, clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), loc)
+		}
+	case timeKindDateTimeLocal:
+		v, ok = parseClock("2006-01-02T", text, loc)
+	case timeKindMonth:
+		var err error
+		v, err = time.ParseInLocation("2006-01", text, loc)
+		ok = err == nil
+	case timeKindWeek:
+		v, ok = parseWeek(text, loc)
+	}
+	return
+}
+
+// parseClock parses text as datePrefix followed by a clock time with or
+// without seconds. Fractional seconds are accepted after the seconds.
+func parseClock(datePrefix, text string, loc *time.Location) (v time.Time, ok bool) {
+	for _, layout := range []string{datePrefix + "15:04:05", datePrefix + "15:04"} {
+		var err error
+		if v, err = time.ParseInLocation(layout, text, loc); err == nil {
+			ok = true
+			return
+		}
+	}
+	return
+}
+
+// parseWeek parses an ISO 8601 week ("2006-W01") to midnight on its Monday.
+func parseWeek(text string, loc *time.Location) (v time.Time, ok bool) {
+	yearText, weekText, found := strings.Cut(text, "-W")
+	if found && len(yearText) == 4 && len(weekText) == 2 && isDigits(yearText) && isDigits(weekText) {
+		year, _ := strconv.Atoi(yearText)
+		week, _ := strconv.Atoi(weekText)
+		// Week 1 is the week containing January 4th.
+		jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+		monday := jan4.AddDate(0, 0, (week-1)*7-(int(jan4.Weekday())+6)%7)
+		if y, w := monday.ISOWeek(); year > 0 && y == year && w == week {
+			v, ok = monday, true
+		}
+	}
+	return
+}
+
+func isDigits(s string) bool {
+	for _, ch := range s {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
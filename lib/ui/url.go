@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"io"
+	"net/url"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+)
+
+// URL renders an HTML url input bound to a string setter.
+//
+// A URL value must back at most one live [jaws.Element]. Construct distinct URL
+// values over the same setter to render one bound value more than once.
+//
+// Edits are sent on the browser's change event, as for [jaws.InputOnChange],
+// unless the render parameters choose another send policy. The setter only
+// receives an empty string or an absolute URL; see [ValidURL]. Other browser
+// text is rejected without calling the setter or returning an error, and the
+// canonical value is restored by updating only the originating Element.
+type URL struct{ InputText }
+
+// NewURL returns a url input widget bound to g.
+//
+// For writable use, g must provide the setter-derived dirty target described by [Input].
+func NewURL(g bind.Setter[string]) *URL { return &URL{InputText{Setter: g}} }
+
+// ValidURL reports whether text is an absolute URL: it has a scheme, followed
+// by a host, path or opaque part.
+func ValidURL(text string) bool {
+	u, err := url.Parse(text)
+	return err == nil && u.IsAbs() && (u.Host != "" || u.Path != "" || u.Opaque != "")
+}
+
+// JawsRender renders ui as an HTML url input.
+func (u *URL) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	// Caller params come first, so the HTML parser keeps theirs on conflict.
+	return u.renderStringInput(elem, w, "url", append(params[:len(params):len(params)], jaws.InputOnChange)...)
+}
+
+// JawsInput stores a browser-side url input value if it is empty or valid.
+func (u *URL) JawsInput(elem *jaws.Element, value string) error {
+	return u.validatedInput(elem, value, func(s string) bool { return s == "" || ValidURL(s) })
+}
+
+// URL renders an HTML url input.
+func (rw RequestWriter) URL(value any, params ...any) error {
+	return rw.NewUI(NewURL(bind.MakeSetter[string](value)), params...)
+}
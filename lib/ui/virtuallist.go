@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"sync"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/htmlio"
+)
+
+// VirtualSource supplies the children of a [VirtualList] by index.
+//
+// The elem argument is the VirtualList's [jaws.Element]. Return equal UI
+// values for the same child each time, as for [jaws.Container], so children
+// that stay in the window keep their Elements while the user scrolls.
+type VirtualSource interface {
+	// JawsVirtualCount returns the number of children.
+	JawsVirtualCount(elem *jaws.Element) int
+	// JawsVirtualItems returns the children from index start up to, but not
+	// including, end. Children beyond end are ignored.
+	JawsVirtualItems(elem *jaws.Element, start, end int) []jaws.UI
+}
+
+// VirtualList renders a scrollable window over a large child list.
+//
+// Only the children in the visible range, plus overscan children on either
+// side, have Elements. The bundled client reports the scroll position and
+// viewport height as scroll events, and the VirtualList reconciles its window
+// like a [Container], appending, removing and ordering children as they enter
+// and leave it. Padding above and below the children keeps the scroll height
+// of the whole list. Until the browser first reports, the window holds the
+// first 2*overscan children. A reported viewport counts as at most 1000
+// children tall.
+//
+// Every child must render exactly itemHeight CSS pixels tall. The VirtualList's
+// Element is the scrolling viewport: give it a bounded height and vertical
+// overflow through its render parameters, such as
+// style="height:20em;overflow-y:auto". The children render inside an inner
+// outerHTMLTag element. Mark the source dirty after its children change.
+//
+// The window is kept in each VirtualList Element's state, so equal VirtualList
+// values may back multiple live Elements. Use VirtualList as a value; taking
+// its address changes identity and is unsupported.
+type VirtualList struct {
+	outerHTMLTag string
+	source       VirtualSource
+	itemHeight   int
+	overscan     int
+}
+
+var (
+	_ jaws.UI              = VirtualList{}
+	_ jaws.DOMEventHandler = VirtualList{}
+)
+
+// NewVirtualList returns a VirtualList over source whose children render
+// itemHeight CSS pixels tall inside outerHTMLTag, keeping overscan children
+// beyond each edge of the visible range.
+func NewVirtualList(outerHTMLTag string, source VirtualSource, itemHeight, overscan int) VirtualList {
+	if outerHTMLTag == "" {
+		outerHTMLTag = "div"
+	}
+	return VirtualList{outerHTMLTag: outerHTMLTag, source: source, itemHeight: max(itemHeight, 1), overscan: max(overscan, 0)}
+}
+
+// virtualState is the per-Element window a VirtualList claims while rendering.
+type virtualState struct {
+	source       VirtualSource
+	itemHeight   int
+	overscan     int
+	elem         *jaws.Element // the VirtualList's Element
+	mu           sync.Mutex
+	list         *jaws.Element // the inner Container's Element
+	reported     bool
+	scrollTop    float64
+	clientHeight float64
+	count        int
+	start        int
+	end          int
+	style        string // last padding style sent
+}
+
+// JawsRender renders u as a scrolling viewport around its inner list.
+//
+// If elem's widget state is occupied, JawsRender returns
+// [jaws.ErrElementStateClaimed] without rendering.
+func (u VirtualList) JawsRender(elem *jaws.Element, w io.Writer, params []any) (err error) {
+	st := &virtualState{source: u.source, itemHeight: u.itemHeight, overscan: u.overscan, elem: elem}
+	if err = jaws.SetElementState(elem, st); err != nil {
+		return
+	}
+	elem.ApplyGetter(u.source)
+	attrs := append(elem.ApplyParams(params), jaws.ForwardDOMEvents("scroll:throttle=50"), "data-jawsvirtual")
+	list := elem.Request.NewElement(NewContainer(u.outerHTMLTag, virtualItems{st}))
+	st.mu.Lock()
+	st.list = list
+	st.mu.Unlock()
+	style, _ := st.window()
+
+	b := elem.Jid().AppendStartTagAttr(nil, "div")
+	b = htmlio.AppendAttrs(b, attrs)
+	b = append(b, '>')
+	if _, err = w.Write(b); err == nil {
+		if err = list.JawsRender(w, []any{htmlio.Attr("style", style)}); err == nil {
+			_, err = io.WriteString(w, "</div>")
+		}
+	}
+	if err != nil {
+		deleteOwnedElements(elem.Request, st.takeOwnedElements())
+	}
+	return
+}
+
+// JawsUpdate reconciles u's window with its source and scroll position.
+func (u VirtualList) JawsUpdate(elem *jaws.Element) {
+	if st, ok := jaws.ElementState(elem).(*virtualState); ok && st != nil {
+		style, changed := st.window()
+		st.mu.Lock()
+		list := st.list
+		st.mu.Unlock()
+		if list != nil {
+			list.JawsUpdate()
+			if changed {
+				list.SetAttr("style", style)
+			}
+		}
+	}
+}
+
+// JawsDOMEvent moves u's window to follow a scroll event.
+func (u VirtualList) JawsDOMEvent(elem *jaws.Element, ev jaws.DOMEvent) (err error) {
+	err = jaws.ErrEventUnhandled
+	if st, ok := jaws.ElementState(elem).(*virtualState); ok && st != nil && ev.Type == "scroll" {
+		st.mu.Lock()
+		st.reported = true
+		st.scrollTop = ev.ScrollTop
+		st.clientHeight = ev.ClientHeight
+		start, end := st.rangeLocked()
+		changed := start != st.start || end != st.end
+		st.mu.Unlock()
+		if changed {
+			elem.Dirty(elem)
+		}
+		err = nil
+	}
+	return
+}
+
+// takeOwnedElements returns the inner list's Element and forgets it,
+// transferring responsibility for unregistering it to the caller.
+func (st *virtualState) takeOwnedElements() (owned []*jaws.Element) {
+	st.mu.Lock()
+	if st.list != nil {
+		owned = append(owned, st.list)
+		st.list = nil
+	}
+	st.mu.Unlock()
+	return
+}
+
+// window refreshes the child count and window, returning the inner list's
+// padding style and whether it changed.
+func (st *virtualState) window() (style string, changed bool) {
+	count := st.source.JawsVirtualCount(st.elem)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.count = max(count, 0)
+	st.start, st.end = st.rangeLocked()
+	style = "overflow-anchor:none;padding-top:" + strconv.Itoa(st.start*st.itemHeight) +
+		"px;padding-bottom:" + strconv.Itoa((st.count-st.end)*st.itemHeight) + "px"
+	changed = style != st.style
+	st.style = style
+	return
+}
+
+// virtualMaxVisible limits the children a reported viewport height can make
+// visible, so a hostile client cannot make the window arbitrarily large.
+const virtualMaxVisible = 1000
+
+// virtualMaxScrollTop bounds a reported scroll position well within int range.
+const virtualMaxScrollTop = 1 << 40
+
+// rangeLocked returns the window for the last reported scroll position.
+func (st *virtualState) rangeLocked() (start, end int) {
+	first, visible := 0, st.overscan
+	if st.reported {
+		first = int(clampPixels(st.scrollTop, virtualMaxScrollTop)) / st.itemHeight
+		visible = int(math.Ceil(clampPixels(st.clientHeight, virtualMaxVisible*float64(st.itemHeight)) / float64(st.itemHeight)))
+	}
+	end = min(first+visible+st.overscan, st.count)
+	start = min(max(first-st.overscan, 0), end)
+	return
+}
+
+// clampPixels returns the client-reported v limited to the range 0 to limit,
+// so it converts to an int without overflow.
+func clampPixels(v, limit float64) float64 {
+	if !(v > 0) {
+		return 0
+	}
+	return min(v, limit)
+}
+
+// virtualItems provides the children in a VirtualList's window.
+type virtualItems struct{ st *virtualState }
+
+func (u virtualItems) JawsContains(elem *jaws.Element) []jaws.UI {
+	u.st.mu.Lock()
+	start, end := u.st.start, u.st.end
+	u.st.mu.Unlock()
+	items := u.st.source.JawsVirtualItems(u.st.elem, start, end)
+	return items[:min(len(items), end-start)]
+}
+
+// VirtualList renders a scrollable window over source; see [NewVirtualList].
+func (rw RequestWriter) VirtualList(outerHTMLTag string, source VirtualSource, itemHeight, overscan int, params ...any) error {
+	return rw.NewUI(NewVirtualList(outerHTMLTag, source, itemHeight, overscan), params...)
+}
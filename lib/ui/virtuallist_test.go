@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/what"
+)
+
+type testVirtualItem int
+
+func (u testVirtualItem) JawsRender(elem *jaws.Element, w io.Writer, params []any) (err error) {
+	_, err = io.WriteString(w, `<div id="`+elem.Jid().String()+`">`+strconv.Itoa(int(u))+`</div>`)
+	return
+}
+
+func (u testVirtualItem) JawsUpdate(elem *jaws.Element) {}
+
+type testVirtualSource struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (s *testVirtualSource) JawsVirtualCount(elem *jaws.Element) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+func (s *testVirtualSource) JawsVirtualItems(elem *jaws.Element, start, end int) (items []jaws.UI) {
+	for i := start; i < end+1; i++ {
+		items = append(items, testVirtualItem(i))
+	}
+	return
+}
+
+func TestVirtualList(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	src := &testVirtualSource{count: 1000}
+	elem, got := renderUI(t, rq, NewVirtualList("ul", src, 20, 5), `style="height:200px;overflow-y:auto"`)
+	mustMatch(t, `^<div id="Jid\.[0-9]+" style="height:200px;overflow-y:auto" data-jawsevents="scroll:throttle=50" data-jawsvirtual>`+
+		`<ul id="Jid\.[0-9]+" style="overflow-anchor:none;padding-top:0px;padding-bottom:19800px">`+
+		`<div id="Jid\.[0-9]+">0</div>.*<div id="Jid\.[0-9]+">9</div></ul></div>$`, got)
+
+	st := jaws.ElementState(elem).(*virtualState)
+	list := st.list
+	window := func() (first, last testVirtualItem, n int) {
+		contents := containerElements(t, list)
+		n = len(contents)
+		if n > 0 {
+			first, last = contents[0].UI().(testVirtualItem), contents[n-1].UI().(testVirtualItem)
+		}
+		return
+	}
+	scroll := func(data string) {
+		t.Helper()
+		if err := jaws.CallEventHandlers(elem.UI(), elem, what.DOMEvent, data); err != nil {
+			t.Fatal(err)
+		}
+		elem.JawsUpdate()
+	}
+	if first, last, n := window(); first != 0 || last != 9 || n != 10 {
+		t.Fatalf("initial window %d..%d (%d)", first, last, n)
+	}
+
+	scroll(`{"type":"scroll","scrollTop":2000,"clientHeight":200}`)
+	if first, last, n := window(); first != 95 || last != 114 || n != 20 {
+		t.Errorf("window %d..%d (%d)", first, last, n)
+	}
+	if st.style != "overflow-anchor:none;padding-top:1900px;padding-bottom:17700px" {
+		t.Errorf("style %q", st.style)
+	}
+
+	// Children still in the window keep their Elements.
+	before := containerElements(t, list)
+	scroll(`{"type":"scroll","scrollTop":2030,"clientHeight":200}`)
+	after := containerElements(t, list)
+	if first, last, _ := window(); first != 96 || last != 115 {
+		t.Errorf("window %d..%d", first, last)
+	}
+	for _, childElem := range after[:len(after)-1] {
+		if childElem != before[childElem.UI().(testVirtualItem)-95] {
+			t.Errorf("child %v not retained", childElem.UI())
+		}
+	}
+	if !before[0].Deleted() {
+		t.Error("child 95 not removed")
+	}
+
+	if err := jaws.CallEventHandlers(elem.UI(), elem, what.DOMEvent, `{"type":"wheel"}`); err != jaws.ErrEventUnhandled {
+		t.Errorf("wheel event: %v", err)
+	}
+
+	// A shrinking source clamps the window.
+	src.mu.Lock()
+	src.count = 100
+	src.mu.Unlock()
+	elem.JawsUpdate()
+	if first, last, n := window(); first != 96 || last != 99 || n != 4 {
+		t.Errorf("clamped window %d..%d (%d)", first, last, n)
+	}
+
+	// Removing the VirtualList unregisters its list and children.
+	childElem := containerElements(t, list)[0]
+	deleteOwnedElements(rq, []*jaws.Element{elem})
+	if !list.Deleted() || !childElem.Deleted() {
+		t.Error("list not deleted")
+	}
+}
+
+func TestVirtualList_HostileScroll(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	src := &testVirtualSource{count: 100000}
+	elem, _ := renderUI(t, rq, NewVirtualList("ul", src, 20, 5))
+	list := jaws.ElementState(elem).(*virtualState).list
+	for _, tc := range []struct {
+		data        string
+		first, last testVirtualItem
+		n           int
+	}{
+		{`{"type":"scroll","scrollTop":0,"clientHeight":1e7}`, 0, 1004, 1005},
+		{`{"type":"scroll","scrollTop":0,"clientHeight":1e300}`, 0, 1004, 1005},
+		{`{"type":"scroll","scrollTop":-1e300,"clientHeight":-1e300}`, 0, 4, 5},
+		{`{"type":"scroll","scrollTop":1e300,"clientHeight":1e300}`, 0, 0, 0},
+	} {
+		if err := jaws.CallEventHandlers(elem.UI(), elem, what.DOMEvent, tc.data); err != nil {
+			t.Fatal(err)
+		}
+		elem.JawsUpdate()
+		var first, last jaws.UI = testVirtualItem(0), testVirtualItem(0)
+		contents := containerElements(t, list)
+		if n := len(contents); n > 0 {
+			first, last = contents[0].UI(), contents[n-1].UI()
+		}
+		if len(contents) != tc.n || first != tc.first || last != tc.last {
+			t.Errorf("%s: window %v..%v (%d), want %v..%v (%d)", tc.data, first, last, len(contents), tc.first, tc.last, tc.n)
+		}
+	}
+}
+
+func TestVirtualList_RequestWriter(t *testing.T) {
+	_, rq := newCoreRequest(t)
+	var sb strings.Builder
+	rw := RequestWriter{Request: rq, Writer: &sb}
+	if err := rw.VirtualList("", &testVirtualSource{count: 3}, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	mustMatch(t, `<div id="Jid\.[0-9]+" style="overflow-anchor:none;padding-top:0px;padding-bottom:3px"></div></div>$`, sb.String())
+}
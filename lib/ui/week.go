@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"io"
+	"time"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/lib/bind"
+)
+
+// Week renders an HTML week input bound to a time value setter.
+//
+// A Week value must back at most one live [jaws.Element]. Construct distinct Week
+// values over the same setter to render one bound value more than once.
+//
+// The week is an ISO 8601 week. A browser edit sets the bound value to midnight
+// on the Monday of the picked week in the widget's location. See [InputTime].
+type Week struct{ InputTime }
+
+// NewWeek returns a week input widget bound to g, whose browser text is in
+// location loc. If loc is nil, the bound value's own location is used.
+//
+// For writable use, g must provide the setter-derived dirty target described by [Input].
+func NewWeek(g bind.Setter[time.Time], loc *time.Location) *Week {
+	return &Week{InputTime{Setter: g, kind: timeKindWeek, loc: loc}}
+}
+
+// JawsRender renders ui as an HTML week input.
+func (u *Week) JawsRender(elem *jaws.Element, w io.Writer, params []any) error {
+	return u.renderTimeInput(elem, w, params...)
+}
+
+// Week renders an HTML week input in the bound value's own location.
+func (rw RequestWriter) Week(value any, params ...any) error {
+	return rw.NewUI(NewWeek(bind.MakeSetter[time.Time](value), nil), params...)
+}
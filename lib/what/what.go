@@ -25,6 +25,23 @@ const (
 	Order
 	// Call calls a JavaScript function.
 	Call
+	// Reply settles the Promise of a browser [RPC] call.
+	//
+	// Data is a JSON object holding the call id and either a result or an error.
+	Reply
+	// Ask asks the browser a question answered with an [Answer] event.
+	//
+	// Data is a JSON object holding the question id and kind.
+	Ask
+	// Download tells the browser to download the file at the URL in Data.
+	Download
+	// Clipboard tells the browser to write the text in Data to the clipboard.
+	Clipboard
+	// Toast shows a notification that is answered with a [Dismiss] event once
+	// closed.
+	//
+	// Data is a JSON object holding the toast id and its content.
+	Toast
 
 	separator
 
@@ -56,6 +73,21 @@ const (
 	RClass
 	// Value sets an element value.
 	Value
+	// Ack reports that the event handlers for an element event have returned.
+	Ack
+	// Focus moves keyboard focus to an element.
+	Focus
+	// Scroll scrolls an element into view; Data is a JSON object holding the
+	// scrollIntoView options.
+	Scroll
+	// Select focuses an element and selects its text; Data is the start and
+	// end offsets separated by a space, where a negative end selects to the
+	// end of the text.
+	Select
+	// ShowModal opens a dialog element as a modal.
+	ShowModal
+	// Close closes a dialog element.
+	Close
 
 	// Element input events
 
@@ -65,6 +97,42 @@ const (
 	Click
 	// ContextMenu reports that a context menu was requested on an element.
 	ContextMenu
+	// KeyDown reports that a registered key was pressed on an element.
+	KeyDown
+	// KeyUp reports that a registered key was released on an element.
+	KeyUp
+	// Reorder reports that the user reordered an element's children; Data is
+	// the space-separated child Jids in their new order.
+	Reorder
+	// DOMEvent reports a DOM event the element opted into forwarding; Data is
+	// a JSON object describing the event.
+	DOMEvent
+	// RPC calls a named Go function registered with HandleRPC; Data is the call
+	// id, the function name and the JSON arguments, separated by spaces.
+	//
+	// RPC is not associated with an Element and is sent without a Jid.
+	RPC
+	// Answer answers an [Ask] command; Data is a JSON object holding the
+	// question id and either a result or an error.
+	//
+	// Answer is not associated with an Element and is sent without a Jid.
+	Answer
+	// Client reports the browser environment; Data is a JSON object as sent
+	// in the WebSocket URL when connecting.
+	//
+	// Client is not associated with an Element and is sent without a Jid.
+	Client
+	// Navigate asks that the Request of a page the browser loaded take over
+	// the WebSocket; Data is its request key. The server replies with a
+	// Navigate command carrying the key if it did, or an empty key if not.
+	//
+	// Navigate is not associated with an Element and is sent without a Jid.
+	Navigate
+	// Dismiss reports that a [Toast] was closed; Data is a JSON object holding
+	// the toast id and whether its action button closed it.
+	//
+	// Dismiss is not associated with an Element and is sent without a Jid.
+	Dismiss
 
 	// Hook synchronously invokes the matching event handler.
 	//
@@ -15,28 +15,48 @@ func _() {
 	_ = x[Alert-4]
 	_ = x[Order-5]
 	_ = x[Call-6]
-	_ = x[separator-7]
-	_ = x[Set-8]
-	_ = x[Inner-9]
-	_ = x[Delete-10]
-	_ = x[Replace-11]
-	_ = x[Remove-12]
-	_ = x[Insert-13]
-	_ = x[Append-14]
-	_ = x[SAttr-15]
-	_ = x[RAttr-16]
-	_ = x[SClass-17]
-	_ = x[RClass-18]
-	_ = x[Value-19]
-	_ = x[Input-20]
-	_ = x[Click-21]
-	_ = x[ContextMenu-22]
-	_ = x[Hook-23]
+	_ = x[Reply-7]
+	_ = x[Ask-8]
+	_ = x[Download-9]
+	_ = x[Clipboard-10]
+	_ = x[Toast-11]
+	_ = x[separator-12]
+	_ = x[Set-13]
+	_ = x[Inner-14]
+	_ = x[Delete-15]
+	_ = x[Replace-16]
+	_ = x[Remove-17]
+	_ = x[Insert-18]
+	_ = x[Append-19]
+	_ = x[SAttr-20]
+	_ = x[RAttr-21]
+	_ = x[SClass-22]
+	_ = x[RClass-23]
+	_ = x[Value-24]
+	_ = x[Ack-25]
+	_ = x[Focus-26]
+	_ = x[Scroll-27]
+	_ = x[Select-28]
+	_ = x[ShowModal-29]
+	_ = x[Close-30]
+	_ = x[Input-31]
+	_ = x[Click-32]
+	_ = x[ContextMenu-33]
+	_ = x[KeyDown-34]
+	_ = x[KeyUp-35]
+	_ = x[Reorder-36]
+	_ = x[DOMEvent-37]
+	_ = x[RPC-38]
+	_ = x[Answer-39]
+	_ = x[Client-40]
+	_ = x[Navigate-41]
+	_ = x[Dismiss-42]
+	_ = x[Hook-43]
 }
 
-const _What_name = "InvalidUpdateReloadRedirectAlertOrderCallseparatorSetInnerDeleteReplaceRemoveInsertAppendSAttrRAttrSClassRClassValueInputClickContextMenuHook"
+const _What_name = "InvalidUpdateReloadRedirectAlertOrderCallReplyAskDownloadClipboardToastseparatorSetInnerDeleteReplaceRemoveInsertAppendSAttrRAttrSClassRClassValueAckFocusScrollSelectShowModalCloseInputClickContextMenuKeyDownKeyUpReorderDOMEventRPCAnswerClientNavigateDismissHook"
 
-var _What_index = [...]uint8{0, 7, 13, 19, 27, 32, 37, 41, 50, 53, 58, 64, 71, 77, 83, 89, 94, 99, 105, 111, 116, 121, 126, 137, 141}
+var _What_index = [...]uint16{0, 7, 13, 19, 27, 32, 37, 41, 46, 49, 57, 66, 71, 80, 83, 88, 94, 101, 107, 113, 119, 124, 129, 135, 141, 146, 149, 154, 160, 166, 175, 180, 185, 190, 201, 208, 213, 220, 228, 231, 237, 243, 251, 258, 262}
 
 func (i What) String() string {
 	idx := int(i) - 0
@@ -16,7 +16,27 @@ func TestParse(t *testing.T) {
 		{"blank is Update", "", Update},
 		{"Update", "Update", Update},
 		{"Inner", "Inner", Inner},
+		{"Ack", "Ack", Ack},
+		{"Focus", "Focus", Focus},
+		{"Scroll", "Scroll", Scroll},
+		{"Select", "Select", Select},
+		{"ShowModal", "ShowModal", ShowModal},
+		{"Close", "Close", Close},
 		{"ContextMenu", "ContextMenu", ContextMenu},
+		{"KeyDown", "KeyDown", KeyDown},
+		{"KeyUp", "KeyUp", KeyUp},
+		{"Reorder", "Reorder", Reorder},
+		{"DOMEvent", "DOMEvent", DOMEvent},
+		{"Reply", "Reply", Reply},
+		{"RPC", "RPC", RPC},
+		{"Ask", "Ask", Ask},
+		{"Download", "Download", Download},
+		{"Clipboard", "Clipboard", Clipboard},
+		{"Toast", "Toast", Toast},
+		{"Dismiss", "Dismiss", Dismiss},
+		{"Answer", "Answer", Answer},
+		{"Client", "Client", Client},
+		{"Navigate", "Navigate", Navigate},
 		{"lowercase is not matched", "inner", Invalid},
 		{"innerr", "innerr", Invalid},
 		{"last", lastWhat.String(), lastWhat},
@@ -58,10 +78,26 @@ func TestIsCommandAndValid(t *testing.T) {
 		{"Reload", Reload, true, true},
 		{"Redirect", Redirect, true, true},
 		{"Alert", Alert, true, true},
-		{"Call", Call, true, true},             // last command, just below separator
+		{"Call", Call, true, true},
+		{"Reply", Reply, true, true},
+		{"Ask", Ask, true, true},
+		{"Download", Download, true, true},
+		{"Clipboard", Clipboard, true, true},
+		{"Toast", Toast, true, true},           // last command, just below separator
 		{"separator", separator, false, false}, // internal boundary marker, not a command or event
 		{"Set", Set, true, false},              // first element value, just above separator
 		{"Inner", Inner, true, false},
+		{"Ack", Ack, true, false},
+		{"Focus", Focus, true, false},
+		{"Close", Close, true, false},
+		{"KeyUp", KeyUp, true, false},
+		{"Reorder", Reorder, true, false},
+		{"DOMEvent", DOMEvent, true, false},
+		{"RPC", RPC, true, false},
+		{"Answer", Answer, true, false},
+		{"Client", Client, true, false},
+		{"Navigate", Navigate, true, false},
+		{"Dismiss", Dismiss, true, false},
 		{"Hook", Hook, true, false},            // last defined value, must stay valid
 		{"above Hook", Hook + 1, false, false}, // first undefined value above Hook
 		{"max uint8", What(255), false, false}, // top of the uint8 range, undefined
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/coder/websocket"
@@ -143,6 +144,12 @@ func readWebSocket(ctx context.Context, resultCh chan<- wsReadResult, ws *websoc
 //
 // ccf may be nil, in which case errors are not reported and only the loop exits.
 func WriteLoop(ctx context.Context, ccf context.CancelCauseFunc, doneCh <-chan struct{}, outboundMsgCh <-chan WsMsg, writeTimeout time.Duration, ws *websocket.Conn) {
+	WriteLoopCounted(ctx, ccf, doneCh, outboundMsgCh, writeTimeout, ws, nil)
+}
+
+// WriteLoopCounted is [WriteLoop], and also adds the length of every text
+// message written to the WebSocket to written, unless it is nil.
+func WriteLoopCounted(ctx context.Context, ccf context.CancelCauseFunc, doneCh <-chan struct{}, outboundMsgCh <-chan WsMsg, writeTimeout time.Duration, ws *websocket.Conn, written *atomic.Uint64) {
 	defer func() { _ = ws.Close(websocket.StatusNormalClosure, "") }()
 	ctx, cancel := contextWithDone(ctx, doneCh)
 	defer cancel()
@@ -158,7 +165,7 @@ func WriteLoop(ctx context.Context, ccf context.CancelCauseFunc, doneCh <-chan s
 			writectx, writecancel := context.WithTimeout(ctx, writeTimeout)
 			var wc io.WriteCloser
 			if wc, err = ws.Writer(writectx, websocket.MessageText); err == nil {
-				err = writeData(wc, msg, outboundMsgCh)
+				err = writeData(wc, msg, outboundMsgCh, written)
 			}
 			writecancel()
 		}
@@ -190,7 +197,7 @@ func reportError(ctx context.Context, doneCh <-chan struct{}, ccf context.Cancel
 	}
 }
 
-func writeData(wc io.WriteCloser, firstMsg WsMsg, outboundMsgCh <-chan WsMsg) (err error) {
+func writeData(wc io.WriteCloser, firstMsg WsMsg, outboundMsgCh <-chan WsMsg, written *atomic.Uint64) (err error) {
 	b := firstMsg.Append(nil)
 	// accumulate data to send as long as more messages are available until it
 	// exceeds writeBatchLimit
@@ -206,7 +213,11 @@ batchloop:
 			break batchloop
 		}
 	}
-	_, err = wc.Write(b)
+	var n int
+	n, err = wc.Write(b)
+	if written != nil {
+		written.Add(uint64(n)) // #nosec G115 -- Write never returns a negative count
+	}
 	err = errors.Join(err, wc.Close())
 	return
 }
@@ -488,6 +488,39 @@ func TestWriteLoop_SendsThePayload(t *testing.T) {
 	waitDone(t, writeDoneCh, "WriteLoop after context cancel")
 }
 
+func TestWriteLoopCounted_CountsWrittenBytes(t *testing.T) {
+	outCh := make(chan WsMsg, 1)
+	client, server := pipe(t)
+	defer func() { _ = client.CloseNow() }()
+	defer func() { _ = server.CloseNow() }()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var written atomic.Uint64
+	writeDoneCh := make(chan struct{})
+	go func() {
+		defer close(writeDoneCh)
+		WriteLoopCounted(ctx, nil, make(chan struct{}), outCh, time.Hour, server, &written)
+	}()
+
+	msg := WsMsg{Jid: jid.Jid(1234), What: what.Inner, Data: "hello"}
+	outCh <- msg
+	readCtx, readCancel := context.WithTimeout(t.Context(), 3*time.Second)
+	defer readCancel()
+	_, b, err := client.Read(readCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := written.Load(); got != uint64(len(b)) || string(b) != msg.Format() {
+		t.Errorf("written %d, read %q", got, b)
+	}
+
+	cancel()
+	_ = client.CloseNow()
+	waitDone(t, writeDoneCh, "WriteLoopCounted after context cancel")
+}
+
 func TestWriteLoop_ConcatenatesMessages(t *testing.T) {
 	outCh := make(chan WsMsg, 2)
 	jawsDoneCh := make(chan struct{})
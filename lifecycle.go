@@ -0,0 +1,181 @@
+package jaws
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/linkdata/jaws/lib/what"
+)
+
+// LifecycleKind identifies the change reported by a [LifecycleEvent].
+type LifecycleKind uint8
+
+const (
+	// SessionCreated reports a [Session] that became registered, either newly
+	// created or restored from the [Jaws.SessionStore].
+	SessionCreated LifecycleKind = iota + 1
+	// SessionClosed reports a [Session] invalidated by [Session.Close] or [Jaws.Close].
+	SessionClosed
+	// SessionExpired reports a [Session] removed by maintenance after it expired.
+	SessionExpired
+	// RequestCreated reports a pending [Request] returned by [Jaws.NewRequest].
+	RequestCreated
+	// RequestClaimed reports a [Request] claimed by [Jaws.UseRequest].
+	RequestClaimed
+	// RequestDisconnected reports that [Request.ServeHTTP] stopped serving a
+	// claimed [Request]. Err holds the cancellation cause.
+	RequestDisconnected
+	// RequestRecycled reports a [Request] that was unregistered, either after
+	// RequestDisconnected or because it was retired before its WebSocket
+	// connected. Err holds the cancellation cause.
+	RequestRecycled
+	// EventDispatched reports an event whose handlers returned without error.
+	EventDispatched
+	// EventFailed reports an event whose handlers returned an error, held in Err.
+	EventFailed
+)
+
+func (k LifecycleKind) String() string {
+	switch k {
+	case SessionCreated:
+		return "SessionCreated"
+	case SessionClosed:
+		return "SessionClosed"
+	case SessionExpired:
+		return "SessionExpired"
+	case RequestCreated:
+		return "RequestCreated"
+	case RequestClaimed:
+		return "RequestClaimed"
+	case RequestDisconnected:
+		return "RequestDisconnected"
+	case RequestRecycled:
+		return "RequestRecycled"
+	case EventDispatched:
+		return "EventDispatched"
+	case EventFailed:
+		return "EventFailed"
+	default:
+		return "LifecycleKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// LifecycleEvent describes a lifecycle change delivered to a [LifecycleObserver].
+type LifecycleEvent struct {
+	Kind LifecycleKind
+	// Session is the Session the change concerns. For Request and event kinds it
+	// is the Request's Session when the event is delivered, and may be nil; a
+	// recycled Request has already been detached from its Session.
+	Session *Session
+	Request *Request  // the Request for Request and event kinds
+	Element *Element  // the event target for event kinds
+	What    what.What // the event kind for event kinds
+	// Err is the cancellation cause for RequestDisconnected and RequestRecycled,
+	// as returned by [context.Cause] on [Request.Context], and the handler error
+	// for EventFailed. A cause supplied by JaWS matches [ErrRequestCancelled].
+	Err error
+}
+
+// LifecycleObserver receives [LifecycleEvent] values from the [Jaws] instances
+// it is added to with [Jaws.AddLifecycleObserver].
+//
+// JawsLifecycle is called synchronously, without JaWS core locks held, from the
+// goroutine that made the change; events from one goroutine arrive in order,
+// while events from different Requests may be delivered concurrently. It must
+// not block for long, and must not retain the Request or Element beyond the call.
+// A panic is recovered and reported through [Jaws.Log].
+type LifecycleObserver interface {
+	JawsLifecycle(ev LifecycleEvent)
+}
+
+type lifecycleObserver struct {
+	obs LifecycleObserver
+}
+
+// AddLifecycleObserver adds obs to the observers notified of Session, Request and
+// event lifecycle changes, and returns a function that removes it again.
+//
+// It is safe for concurrent use. Changes made while obs is being added or removed
+// may or may not be reported to it. A nil obs is ignored.
+func (jw *Jaws) AddLifecycleObserver(obs LifecycleObserver) (remove func()) {
+	remove = func() {}
+	if obs != nil {
+		entry := &lifecycleObserver{obs: obs}
+		jw.mu.Lock()
+		observers := append(slices.Clip(jw.loadLifecycleObservers()), entry)
+		jw.lifecycleObservers.Store(&observers)
+		jw.mu.Unlock()
+		remove = func() {
+			jw.mu.Lock()
+			observers := jw.loadLifecycleObservers()
+			if i := slices.Index(observers, entry); i >= 0 {
+				observers = slices.Delete(slices.Clone(observers), i, i+1)
+				jw.lifecycleObservers.Store(&observers)
+			}
+			jw.mu.Unlock()
+		}
+	}
+	return
+}
+
+func (jw *Jaws) loadLifecycleObservers() (observers []*lifecycleObserver) {
+	if p := jw.lifecycleObservers.Load(); p != nil {
+		observers = *p
+	}
+	return
+}
+
+// notifyLifecycle delivers ev to the registered observers. Caller must not hold
+// jw.mu or any Request or Session lock.
+func (jw *Jaws) notifyLifecycle(ev LifecycleEvent) {
+	if observers := jw.loadLifecycleObservers(); len(observers) > 0 {
+		if ev.Request != nil && ev.Session == nil {
+			ev.Session = ev.Request.Session()
+		}
+		for _, entry := range observers {
+			entry.call(jw, ev)
+		}
+	}
+}
+
+func (entry *lifecycleObserver) call(jw *Jaws, ev LifecycleEvent) {
+	defer func() {
+		if x := recover(); x != nil {
+			_ = jw.Log(fmt.Errorf("jaws: lifecycle observer panic on %v: %v", ev.Kind, x))
+		}
+	}()
+	entry.obs.JawsLifecycle(ev)
+}
+
+func (jw *Jaws) notifySessions(kind LifecycleKind, sessions []*Session) {
+	for _, sess := range sessions {
+		jw.notifyLifecycle(LifecycleEvent{Kind: kind, Session: sess})
+	}
+}
+
+func (jw *Jaws) notifyRequests(kind LifecycleKind, requests []*Request) {
+	if len(jw.loadLifecycleObservers()) == 0 {
+		return
+	}
+	for _, rq := range requests {
+		ev := LifecycleEvent{Kind: kind, Request: rq}
+		if kind == RequestDisconnected || kind == RequestRecycled {
+			ev.Err = context.Cause(rq.Context())
+		}
+		jw.notifyLifecycle(ev)
+	}
+}
+
+// notifyEvent reports the outcome of an event call dispatched by the eventCaller.
+func (rq *Request) notifyEvent(call eventFnCall, err error) {
+	ev := LifecycleEvent{Kind: EventDispatched, Request: rq, Element: call.elem, What: call.wht, Err: err}
+	if ev.Element == nil && len(call.more) > 0 {
+		ev.Element = call.more[0]
+	}
+	if err != nil {
+		ev.Kind = EventFailed
+	}
+	rq.Jaws.notifyLifecycle(ev)
+}
@@ -0,0 +1,180 @@
+package jaws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/linkdata/jaws/lib/what"
+)
+
+type testLifecycleObserver struct {
+	mu     sync.Mutex
+	events []LifecycleEvent
+}
+
+func (o *testLifecycleObserver) JawsLifecycle(ev LifecycleEvent) {
+	o.mu.Lock()
+	o.events = append(o.events, ev)
+	o.mu.Unlock()
+}
+
+// wait returns the first recorded event of the given kind, waiting for it if needed.
+func (o *testLifecycleObserver) wait(t *testing.T, kind LifecycleKind) (ev LifecycleEvent) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		o.mu.Lock()
+		i := slices.IndexFunc(o.events, func(ev LifecycleEvent) bool { return ev.Kind == kind })
+		if i >= 0 {
+			ev = o.events[i]
+		}
+		o.mu.Unlock()
+		if i >= 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("no %v event", kind)
+	return
+}
+
+func (o *testLifecycleObserver) kinds() (kinds []LifecycleKind) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, ev := range o.events {
+		kinds = append(kinds, ev.Kind)
+	}
+	return
+}
+
+type testFailingClickHandler struct{ err error }
+
+func (h testFailingClickHandler) JawsClick(*Element, Click) error { return h.err }
+
+type testPanicObserver struct{}
+
+func (testPanicObserver) JawsLifecycle(LifecycleEvent) { panic("boom") }
+
+func TestLifecycle_RequestAndSession(t *testing.T) {
+	jw, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(jw.Close)
+	go jw.Serve()
+	waitForServeLoop(t, jw)
+	server := httptest.NewServer(jw)
+	t.Cleanup(server.Close)
+
+	obs := &testLifecycleObserver{}
+	remove := jw.AddLifecycleObserver(obs)
+	removePanic := jw.AddLifecycleObserver(testPanicObserver{})
+	if jw.AddLifecycleObserver(nil) == nil {
+		t.Fatal("nil remove func")
+	}
+
+	hr := httptest.NewRequest(http.MethodGet, server.URL+"/", nil)
+	hr.RemoteAddr = "127.0.0.1:1"
+	sess := jw.NewSession(nil, hr)
+	if ev := obs.wait(t, SessionCreated); ev.Session != sess {
+		t.Errorf("SessionCreated %v", ev.Session)
+	}
+	if jw.ErrorCount() == 0 {
+		t.Error("observer panic not logged")
+	}
+	removePanic()
+
+	rq := jw.NewRequest(httptest.NewRecorder(), hr)
+	if ev := obs.wait(t, RequestCreated); ev.Request != rq || ev.Session != sess {
+		t.Errorf("RequestCreated %v %v", ev.Request, ev.Session)
+	}
+	ok := rq.NewElement(testDivWidget{inner: "ok"})
+	ok.AddHandlers(testClickHandler{})
+	ok.Freeze()
+	errBoom := errors.New("boom")
+	bad := rq.NewElement(testDivWidget{inner: "bad"})
+	bad.AddHandlers(testFailingClickHandler{err: errBoom})
+	bad.Freeze()
+
+	hdr := http.Header{}
+	hdr.Set("Origin", server.URL)
+	ctx, cancel := context.WithTimeout(t.Context(), 3*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/jaws/"+rq.JawsKeyString(), &websocket.DialOptions{HTTPHeader: hdr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+	if ev := obs.wait(t, RequestClaimed); ev.Request != rq {
+		t.Errorf("RequestClaimed %v", ev.Request)
+	}
+
+	for _, elem := range []*Element{ok, bad} {
+		if err := conn.Write(ctx, websocket.MessageText, []byte("Click\t"+elem.Jid().String()+"\t\"1 2 0 x\"\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ev := obs.wait(t, EventDispatched); ev.Element != ok || ev.What != what.Click || ev.Request != rq || ev.Err != nil {
+		t.Errorf("EventDispatched %+v", ev)
+	}
+	if ev := obs.wait(t, EventFailed); ev.Element != bad || !errors.Is(ev.Err, errBoom) {
+		t.Errorf("EventFailed %+v", ev)
+	}
+
+	sess.Close()
+	if ev := obs.wait(t, SessionClosed); ev.Session != sess {
+		t.Errorf("SessionClosed %v", ev.Session)
+	}
+	_ = conn.Close(websocket.StatusNormalClosure, "")
+	if ev := obs.wait(t, RequestDisconnected); ev.Request != rq || ev.Err == nil {
+		t.Errorf("RequestDisconnected %+v", ev)
+	}
+	if ev := obs.wait(t, RequestRecycled); ev.Request != rq || ev.Session != nil {
+		t.Errorf("RequestRecycled %+v", ev)
+	}
+
+	remove()
+	n := len(obs.kinds())
+	jw.NewRequest(httptest.NewRecorder(), hr)
+	if got := obs.kinds(); len(got) != n {
+		t.Errorf("removed observer got %v", got[n:])
+	}
+}
+
+func TestLifecycle_RetiredAndClosed(t *testing.T) {
+	jw, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	obs := &testLifecycleObserver{}
+	jw.AddLifecycleObserver(obs)
+	jw.MaxPendingRequestsPerIP = 1
+	old := jw.newRequest(newPendingLimitRequest("192.0.2.1:1000"))
+	setPendingLimitLastWrite(t, old, 3600)
+	jw.newRequest(newPendingLimitRequest("192.0.2.1:1001"))
+	if ev := obs.wait(t, RequestRecycled); ev.Request != old || !errors.Is(ev.Err, ErrRequestCancelled) || !errors.Is(ev.Err, ErrTooManyPendingRequests) {
+		t.Errorf("RequestRecycled %+v", ev)
+	}
+
+	hr := httptest.NewRequest(http.MethodGet, "/", nil)
+	sess := jw.NewSession(nil, hr)
+	jw.Close()
+	if ev := obs.wait(t, SessionClosed); ev.Session != sess {
+		t.Errorf("SessionClosed %v", ev.Session)
+	}
+	want := []LifecycleKind{RequestCreated, RequestRecycled, RequestCreated, SessionCreated, RequestRecycled, SessionClosed}
+	if got := obs.kinds(); !slices.Equal(got, want) {
+		t.Errorf("kinds %v, want %v", got, want)
+	}
+	if s := LifecycleKind(0).String(); s != "LifecycleKind(0)" {
+		t.Error(s)
+	}
+}
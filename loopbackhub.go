@@ -0,0 +1,78 @@
+package jaws
+
+import (
+	"slices"
+
+	"github.com/linkdata/deadlock"
+)
+
+// LoopbackHub is an in-process [Broadcaster] transport connecting several
+// [Jaws] instances, mainly for tests and examples.
+//
+// Each joined instance delivers packets to the others in publish order from a
+// goroutine per instance, which ends when that instance is closed.
+type LoopbackHub struct {
+	mu      deadlock.Mutex
+	members []*loopbackMember
+}
+
+type loopbackMember struct {
+	hub *LoopbackHub
+	jw  *Jaws
+	ch  chan []byte
+}
+
+var _ Broadcaster = (*loopbackMember)(nil)
+
+// NewLoopbackHub returns an empty [LoopbackHub].
+func NewLoopbackHub() *LoopbackHub {
+	return &LoopbackHub{}
+}
+
+// Join sets [Jaws.Broadcaster] of jw to publish to the other joined instances
+// and starts delivering their packets to jw.
+//
+// Call Join before serving, like setting any other [Jaws] configuration field.
+func (hub *LoopbackHub) Join(jw *Jaws) {
+	m := &loopbackMember{hub: hub, jw: jw, ch: make(chan []byte, 64)}
+	jw.Broadcaster = m
+	hub.mu.Lock()
+	hub.members = append(hub.members, m)
+	hub.mu.Unlock()
+	go m.deliver()
+}
+
+func (m *loopbackMember) deliver() {
+	defer m.leave()
+	for {
+		select {
+		case <-m.jw.Done():
+			return
+		case packet := <-m.ch:
+			_ = m.jw.Log(m.jw.ReceiveBroadcast(packet))
+		}
+	}
+}
+
+func (m *loopbackMember) leave() {
+	m.hub.mu.Lock()
+	m.hub.members = slices.DeleteFunc(m.hub.members, func(other *loopbackMember) bool { return other == m })
+	m.hub.mu.Unlock()
+}
+
+// Publish implements [Broadcaster]. It waits for room in each peer's queue,
+// skipping peers that are closed.
+func (m *loopbackMember) Publish(packet []byte) (err error) {
+	m.hub.mu.Lock()
+	peers := slices.Clone(m.hub.members)
+	m.hub.mu.Unlock()
+	for _, peer := range peers {
+		if peer != m {
+			select {
+			case <-peer.jw.Done():
+			case peer.ch <- packet:
+			}
+		}
+	}
+	return
+}
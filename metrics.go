@@ -0,0 +1,196 @@
+package jaws
+
+import (
+	"bufio"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/linkdata/jaws/lib/what"
+)
+
+// metricWhats is the number of [what.What] values with per-kind counters.
+const metricWhats = int(what.Hook) + 1
+
+// eventLatencyBounds are the upper bounds, in nanoseconds, of the event handler
+// latency histogram buckets.
+var eventLatencyBounds = []int64{
+	int64(100 * time.Microsecond),
+	int64(250 * time.Microsecond),
+	int64(500 * time.Microsecond),
+	int64(time.Millisecond),
+	int64(2500 * time.Microsecond),
+	int64(5 * time.Millisecond),
+	int64(10 * time.Millisecond),
+	int64(25 * time.Millisecond),
+	int64(50 * time.Millisecond),
+	int64(100 * time.Millisecond),
+	int64(250 * time.Millisecond),
+	int64(500 * time.Millisecond),
+	int64(time.Second),
+	int64(2500 * time.Millisecond),
+	int64(5 * time.Second),
+}
+
+// dirtyFanoutBounds are the upper bounds of the dirty fan-out histogram buckets.
+var dirtyFanoutBounds = []int64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000}
+
+// metricHistogram is a lock-free histogram over integer observations. Its
+// bucket bounds are kept by the caller; buckets holds non-cumulative counts,
+// with the last used bucket counting observations above every bound.
+type metricHistogram struct {
+	buckets [16]atomic.Uint64
+	count   atomic.Uint64
+	sum     atomic.Int64
+}
+
+func (h *metricHistogram) observe(bounds []int64, v int64) {
+	i := 0
+	for i < len(bounds) && v > bounds[i] {
+		i++
+	}
+	h.buckets[i].Add(1)
+	h.sum.Add(v)
+	h.count.Add(1)
+}
+
+// metrics holds the counters exported by [Jaws.MetricsHandler].
+type metrics struct {
+	sent         [metricWhats]atomic.Uint64
+	received     [metricWhats]atomic.Uint64
+	bytesWritten atomic.Uint64
+	evictions    atomic.Uint64
+	eventLatency [metricWhats]metricHistogram
+	dirtyFanout  metricHistogram
+}
+
+func (m *metrics) countSent(wht what.What) {
+	if int(wht) < metricWhats {
+		m.sent[wht].Add(1)
+	}
+}
+
+func (m *metrics) countReceived(wht what.What) {
+	if int(wht) < metricWhats {
+		m.received[wht].Add(1)
+	}
+}
+
+func (m *metrics) observeEvent(wht what.What, d time.Duration) {
+	if int(wht) < metricWhats {
+		m.eventLatency[wht].observe(eventLatencyBounds, int64(d))
+	}
+}
+
+// MetricsHandler returns an [http.Handler] that serves the instance's status
+// counters in the OpenMetrics text format, for scraping by Prometheus or a
+// compatible collector.
+//
+// Besides the values of [Jaws.RequestCounts], [Jaws.Pending],
+// [Jaws.SessionCount], [Jaws.ActiveSessionCount] and [Jaws.ErrorCount], it
+// exports the WebSocket messages sent and received per [what.What], the bytes
+// written to WebSockets, event handler latency per [what.What], the number of
+// Elements updated by each dirty pass of a Request, and the number of pending
+// Requests evicted by [Jaws.MaxPendingRequestsPerIP].
+//
+// The handler does not authenticate its callers; mount it on a route that is
+// not reachable by the public.
+func (jw *Jaws) MetricsHandler() http.Handler {
+	return http.HandlerFunc(jw.serveMetrics)
+}
+
+func (jw *Jaws) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	w.Header().Set("Cache-Control", headerCacheControlNoStore)
+	bw := bufio.NewWriter(w)
+	jw.writeMetrics(bw)
+	_ = bw.Flush()
+}
+
+func (jw *Jaws) writeMetrics(w *bufio.Writer) {
+	total, active := jw.RequestCounts()
+	pending := jw.Pending()
+	metricFamily(w, "jaws_requests", "gauge", "", "Registered Requests.")
+	metricSample(w, "jaws_requests", "", float64(total))
+	metricFamily(w, "jaws_active_requests", "gauge", "", "Requests with a running WebSocket.")
+	metricSample(w, "jaws_active_requests", "", float64(active))
+	metricFamily(w, "jaws_pending_requests", "gauge", "", "Requests waiting for their WebSocket.")
+	metricSample(w, "jaws_pending_requests", "", float64(pending))
+	metricFamily(w, "jaws_sessions", "gauge", "", "Registered Sessions.")
+	metricSample(w, "jaws_sessions", "", float64(jw.SessionCount()))
+	metricFamily(w, "jaws_active_sessions", "gauge", "", "Sessions with a running Request.")
+	metricSample(w, "jaws_active_sessions", "", float64(jw.ActiveSessionCount()))
+	metricFamily(w, "jaws_errors", "counter", "", "Errors reported to Log or MustLog.")
+	metricSample(w, "jaws_errors_total", "", float64(jw.ErrorCount()))
+	metricFamily(w, "jaws_pending_request_evictions", "counter", "", "Pending Requests evicted by MaxPendingRequestsPerIP.")
+	metricSample(w, "jaws_pending_request_evictions_total", "", float64(jw.metrics.evictions.Load()))
+
+	metricFamily(w, "jaws_messages_sent", "counter", "", "Messages queued for WebSockets.")
+	writeWhatCounters(w, "jaws_messages_sent_total", &jw.metrics.sent, isSentWhat)
+	metricFamily(w, "jaws_messages_received", "counter", "", "Messages received from WebSockets.")
+	writeWhatCounters(w, "jaws_messages_received_total", &jw.metrics.received, isReceivedWhat)
+	metricFamily(w, "jaws_websocket_written_bytes", "counter", "bytes", "Bytes written to WebSockets.")
+	metricSample(w, "jaws_websocket_written_bytes_total", "", float64(jw.metrics.bytesWritten.Load()))
+
+	metricFamily(w, "jaws_event_handler_duration_seconds", "histogram", "seconds", "Event handler latency.")
+	for i := range metricWhats {
+		if h := &jw.metrics.eventLatency[i]; h.count.Load() > 0 {
+			metricHistogramSamples(w, "jaws_event_handler_duration_seconds", `what="`+what.What(i).String()+`"`, h, eventLatencyBounds, 1e-9) // #nosec G115 -- i < metricWhats
+		}
+	}
+	metricFamily(w, "jaws_dirty_fanout_elements", "histogram", "elements", "Elements updated by a dirty pass of a Request.")
+	metricHistogramSamples(w, "jaws_dirty_fanout_elements", "", &jw.metrics.dirtyFanout, dirtyFanoutBounds, 1)
+	_, _ = w.WriteString("# EOF\n")
+}
+
+// isSentWhat reports whether the server sends wht to the browser.
+func isSentWhat(wht what.What) bool {
+	return wht.IsValid() && (wht < what.Input || wht == what.Navigate)
+}
+
+// isReceivedWhat reports whether the bundled client sends wht to the server.
+func isReceivedWhat(wht what.What) bool {
+	return wht == what.Set || wht == what.Remove || (wht >= what.Input && wht < what.Hook)
+}
+
+func writeWhatCounters(w *bufio.Writer, name string, counters *[metricWhats]atomic.Uint64, include func(what.What) bool) {
+	for i := range counters {
+		if wht := what.What(i); include(wht) { // #nosec G115 -- i < metricWhats
+			metricSample(w, name, `what="`+wht.String()+`"`, float64(counters[i].Load()))
+		}
+	}
+}
+
+func metricFamily(w *bufio.Writer, name, typ, unit, help string) {
+	_, _ = w.WriteString("# TYPE " + name + " " + typ + "\n")
+	if unit != "" {
+		_, _ = w.WriteString("# UNIT " + name + " " + unit + "\n")
+	}
+	_, _ = w.WriteString("# HELP " + name + " " + help + "\n")
+}
+
+func metricSample(w *bufio.Writer, name, labels string, value float64) {
+	_, _ = w.WriteString(name)
+	if labels != "" {
+		_, _ = w.WriteString("{" + labels + "}")
+	}
+	_, _ = w.WriteString(" " + strconv.FormatFloat(value, 'g', -1, 64) + "\n")
+}
+
+func metricHistogramSamples(w *bufio.Writer, name, labels string, h *metricHistogram, bounds []int64, scale float64) {
+	sep := ""
+	if labels != "" {
+		sep = ","
+	}
+	// Read the count first so the cumulative buckets never exceed it.
+	count := h.count.Load()
+	var cumulative uint64
+	for i, bound := range bounds {
+		cumulative += h.buckets[i].Load()
+		metricSample(w, name+"_bucket", labels+sep+`le="`+strconv.FormatFloat(float64(bound)*scale, 'g', -1, 64)+`"`, float64(min(cumulative, count)))
+	}
+	metricSample(w, name+"_bucket", labels+sep+`le="+Inf"`, float64(count))
+	metricSample(w, name+"_sum", labels, float64(h.sum.Load())*scale)
+	metricSample(w, name+"_count", labels, float64(count))
+}
@@ -0,0 +1,110 @@
+package jaws
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/linkdata/jaws/lib/what"
+)
+
+func scrapeMetrics(t *testing.T, jw *Jaws) map[string]float64 {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	jw.MetricsHandler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/openmetrics-text") {
+		t.Errorf("Content-Type %q", ct)
+	}
+	body := rr.Body.String()
+	if !strings.HasSuffix(body, "\n# EOF\n") {
+		t.Errorf("missing EOF: %q", body)
+	}
+	samples := map[string]float64{}
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, " ")
+		f, err := strconv.ParseFloat(value, 64)
+		if !ok || err != nil {
+			t.Fatalf("bad sample %q", line)
+		}
+		samples[name] = f
+	}
+	return samples
+}
+
+func TestMetrics_CountsTraffic(t *testing.T) {
+	jw, _, rq, dial := newResumeTestRequest(t, 0)
+	elem := rq.NewElement(testDivWidget{inner: "x"})
+	elem.AddHandlers(testClickHandler{})
+	elem.Freeze()
+
+	conn, err := dial("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+	ctx, cancel := context.WithTimeout(t.Context(), 3*time.Second)
+	defer cancel()
+	if err := conn.Write(ctx, websocket.MessageText, []byte("Click\t"+elem.Jid().String()+"\t\"1 2 0 x\"\n")); err != nil {
+		t.Fatal(err)
+	}
+	jw.Alert("info", "hello")
+	readUntil(t, conn, "hello")
+	jw.Dirty(elem)
+
+	var samples map[string]float64
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		samples = scrapeMetrics(t, jw)
+		if samples[`jaws_event_handler_duration_seconds_count{what="Click"}`] == 1 && samples["jaws_dirty_fanout_elements_count"] >= 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	for name, want := range map[string]float64{
+		`jaws_messages_received_total{what="Click"}`:                         1,
+		`jaws_messages_sent_total{what="Alert"}`:                             1,
+		`jaws_event_handler_duration_seconds_count{what="Click"}`:            1,
+		`jaws_event_handler_duration_seconds_bucket{what="Click",le="+Inf"}`: 1,
+		"jaws_requests":        1,
+		"jaws_active_requests": 1,
+	} {
+		if got := samples[name]; got != want {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+	if samples["jaws_websocket_written_bytes_total"] < float64(len("Alert\t\t\"info\\nhello\"\n")) {
+		t.Errorf("written bytes %v", samples["jaws_websocket_written_bytes_total"])
+	}
+	if samples[`jaws_dirty_fanout_elements_bucket{le="1"}`] < 1 {
+		t.Errorf("dirty fan-out not observed: %v", samples)
+	}
+	if _, ok := samples[`jaws_messages_sent_total{what="`+what.Hook.String()+`"}`]; ok {
+		t.Error("Hook is not a wire message")
+	}
+}
+
+func TestMetrics_CountsEvictions(t *testing.T) {
+	jw, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jw.Close()
+	jw.MaxPendingRequestsPerIP = 1
+	old := jw.newRequest(newPendingLimitRequest("192.0.2.1:1000"))
+	setPendingLimitLastWrite(t, old, 3600)
+	jw.newRequest(newPendingLimitRequest("192.0.2.1:1001"))
+	if got := scrapeMetrics(t, jw)["jaws_pending_request_evictions_total"]; got != 1 {
+		t.Errorf("evictions %v", got)
+	}
+}
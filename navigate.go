@@ -0,0 +1,132 @@
+package jaws
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/coder/websocket"
+	"github.com/linkdata/jaws/lib/key"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+// NavigateLinks is a render parameter making the bundled client follow
+// same-origin links inside an Element without a full page reload.
+//
+// The client fetches the linked page, and if it is a JaWS page, asks the
+// server to hand the WebSocket over to the page's [Request]. The current
+// Request is then retired as if its WebSocket had closed, the document body is
+// replaced with the new page's body, and the history is updated so the back
+// and forward buttons navigate the same way. Scripts in the new body are not
+// run.
+//
+// Links with a target or download attribute, clicks with a modifier key held,
+// and links to pages that are not JaWS pages load normally.
+//
+// With [Jaws.ResumeWindow] positive, the new page's Request also takes over
+// the resume state, so it can be resumed like the page it replaced.
+const NavigateLinks = template.HTMLAttr(`data-jawsnav`)
+
+// wsConn is a WebSocket connection that outlives the Request that accepted
+// it, so it can be handed over to the Request of the page the browser
+// navigates to.
+type wsConn struct {
+	ws            *websocket.Conn
+	r             *http.Request // the WebSocket upgrade request
+	incomingMsgCh chan wire.WsMsg
+	outboundMsgCh chan wire.WsMsg
+	ctx           context.Context
+	cancel        context.CancelFunc
+	current       atomic.Pointer[Request] // Request currently served
+	stop          func() bool             // stops cancelling ctx with current
+	rs            *resumeState            // resume state, if resumable; its attached connections replace ws
+}
+
+func newWSConn(ctx context.Context, ws *websocket.Conn, r *http.Request, incomingMsgCh, outboundMsgCh chan wire.WsMsg) (conn *wsConn) {
+	conn = &wsConn{
+		ws:            ws,
+		r:             r,
+		incomingMsgCh: incomingMsgCh,
+		outboundMsgCh: outboundMsgCh,
+	}
+	conn.ctx, conn.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	return
+}
+
+// link makes rq the Request served by conn, closing conn when rq ends.
+func (conn *wsConn) link(rq *Request) {
+	conn.current.Store(rq)
+	conn.stop = context.AfterFunc(rq.Context(), conn.cancel)
+}
+
+// unlink keeps conn open when the current Request ends, and retires its resume
+// state, since only the Request that takes over conn may be resumed.
+func (conn *wsConn) unlink() {
+	conn.stop()
+	if rq := conn.current.Load(); conn.rs != nil {
+		rq.mu.Lock()
+		rq.resume = nil
+		rq.mu.Unlock()
+	}
+}
+
+// disconnect cancels the current Request after a transport failure.
+//
+// Transport failures ordinarily only report that the peer is no longer
+// reachable. A read-limit violation is actionable application feedback, so
+// it is retained even when transport debugging is disabled.
+func (conn *wsConn) disconnect(err error) {
+	rq := conn.current.Load()
+	if !rq.Jaws.Debug && !errors.Is(err, websocket.ErrMessageTooBig) {
+		err = nil
+	}
+	rq.cancel(err)
+}
+
+// close stops the connection loops and closes the WebSocket without a
+// handshake.
+func (conn *wsConn) close() {
+	conn.cancel()
+	_ = conn.ws.CloseNow()
+}
+
+// claimNavigation claims the Request whose key the browser sent in a Navigate
+// message, returning nil if it can't. Called only from process.
+func (rq *Request) claimNavigation(conn *wsConn, data string) (next *Request) {
+	if k, tail := key.Parse(data); k != 0 && tail == "" && k != rq.JawsKey {
+		if next = rq.Jaws.UseRequest(k, conn.r); next != nil {
+			ci := rq.ClientInfo()
+			next.mu.Lock()
+			next.clientInfo = ci
+			next.mu.Unlock()
+		}
+	}
+	return
+}
+
+// serveNavigation serves conn for the claimed rq that the browser navigated
+// to, closing conn when done unless the browser navigates again, in which case
+// it returns the next Request to serve conn.
+func (rq *Request) serveNavigation(conn *wsConn) (next *Request) {
+	if rq.startServe() {
+		defer rq.stopServe()
+		broadcastMsgCh, err := rq.connect()
+		if err == nil {
+			rq.mu.Lock()
+			rq.incomingMsgCh = conn.incomingMsgCh
+			rq.resume = conn.rs
+			rq.mu.Unlock()
+			conn.link(rq)
+			next, _ = rq.processConn(broadcastMsgCh, conn.incomingMsgCh, conn.outboundMsgCh, conn)
+		}
+		rq.cancel(err)
+		if err != nil {
+			conn.close()
+		}
+	} else {
+		conn.close()
+	}
+	return
+}
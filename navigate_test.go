@@ -0,0 +1,161 @@
+package jaws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+func TestNavigate_HandsOverWebSocket(t *testing.T) {
+	jw, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(jw.Close)
+	go jw.Serve()
+	waitForServeLoop(t, jw)
+	server := httptest.NewServer(jw)
+	t.Cleanup(server.Close)
+
+	newRequest := func() *Request {
+		hr := httptest.NewRequest(http.MethodGet, server.URL+"/", nil)
+		hr.RemoteAddr = "127.0.0.1:1"
+		return jw.NewRequest(httptest.NewRecorder(), hr)
+	}
+	rq1 := newRequest()
+	rq2 := newRequest()
+	connectCh := make(chan ClientInfo, 1)
+	rq2.SetConnectFn(func(rq *Request) error {
+		connectCh <- rq.ClientInfo()
+		return nil
+	})
+
+	hdr := http.Header{}
+	hdr.Set("Origin", server.URL)
+	ctx, cancel := context.WithTimeout(t.Context(), testTimeout)
+	defer cancel()
+	info := `{"width":800}`
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/jaws/"+rq1.JawsKeyString()+"?client="+url.QueryEscape(info), &websocket.DialOptions{HTTPHeader: hdr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+
+	send := func(msg string) {
+		t.Helper()
+		if err := conn.Write(ctx, websocket.MessageText, []byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expect := func(want string) {
+		t.Helper()
+		for {
+			_, data, err := conn.Read(ctx)
+			if err != nil {
+				t.Fatalf("waiting for %q: %v", want, err)
+			}
+			for line := range strings.SplitSeq(string(data), "\n") {
+				if line == want {
+					return
+				}
+			}
+		}
+	}
+
+	// Unknown keys and the current Request's own key are refused.
+	send("Navigate\t\t" + strconv.Quote("12345") + "\n")
+	expect("Navigate\t\t\"\"")
+	send("Navigate\t\t" + strconv.Quote(rq1.JawsKeyString()) + "\n")
+	expect("Navigate\t\t\"\"")
+	rq1.Alert("info", "still here")
+	expect("Alert\t\t\"info\\nstill here\"")
+
+	rq1ctx := rq1.Context()
+	send("Navigate\t\t" + strconv.Quote(rq2.JawsKeyString()) + "\n")
+	expect("Navigate\t\t" + strconv.Quote(rq2.JawsKeyString()))
+	select {
+	case ci := <-connectCh:
+		if ci.Width != 800 {
+			t.Errorf("ClientInfo not carried over: %+v", ci)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+	<-rq1ctx.Done()
+
+	rq2.Alert("info", "navigated")
+	expect("Alert\t\t\"info\\nnavigated\"")
+	if err := rq2.Context().Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Closing the connection ends the Request that took it over.
+	conn.CloseNow()
+	select {
+	case <-rq2.Context().Done():
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+}
+
+func TestNavigate_HandsOverResumableWebSocket(t *testing.T) {
+	jw, server, rq1, dial := newResumeTestRequest(t, time.Minute)
+	hr := httptest.NewRequest(http.MethodGet, server.URL+"/", nil)
+	hr.RemoteAddr = "127.0.0.1:1"
+	rq2 := jw.newRequest(hr)
+
+	conn, err := dial("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+	rq1.Alert("info", "first")
+	readUntil(t, conn, "first")
+
+	rq1ctx := rq1.Context()
+	if err = conn.Write(t.Context(), websocket.MessageText, []byte("Navigate\t\t"+strconv.Quote(rq2.JawsKeyString())+"\n")); err != nil {
+		t.Fatal(err)
+	}
+	readUntil(t, conn, "Navigate\t\t"+strconv.Quote(rq2.JawsKeyString()))
+	<-rq1ctx.Done()
+	rq1.mu.RLock()
+	retired := rq1.resume == nil
+	rq1.mu.RUnlock()
+	if !retired {
+		t.Error("navigated away from Request can still be resumed")
+	}
+
+	// The browser counts records from zero again after navigating.
+	rq2.Alert("info", "second")
+	readUntil(t, conn, "second")
+	_ = conn.CloseNow()
+	waitDetached(t, rq2)
+	rq2.Alert("info", "missed")
+
+	if conn, err = dial("?resume=0"); err == nil {
+		_ = conn.CloseNow()
+		t.Fatal("resumed the Request navigated away from")
+	}
+
+	hdr := http.Header{}
+	hdr.Set("Origin", server.URL)
+	ctx, cancel := context.WithTimeout(t.Context(), testTimeout)
+	defer cancel()
+	if conn, _, err = websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/jaws/"+rq2.JawsKeyString()+"?resume=1", &websocket.DialOptions{HTTPHeader: hdr}); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+	if got := readUntil(t, conn, "missed"); strings.Contains(got, "second") {
+		t.Errorf("replayed a record the client already had: %q", got)
+	}
+	if err = rq2.Context().Err(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -23,12 +23,13 @@ func eligibleAsTag(t any, validate func(any) error) (ok bool) {
 // [Element], returning UI tags, event handlers and HTML attributes.
 //
 // ParseParams recognizes values whose dynamic type is exactly [InputFn], and
-// values implementing [InputHandler], [ClickHandler] or [ContextMenuHandler],
-// as event handlers. It does not invoke
-// [InitialHTMLAttrHandler.JawsInitialHTMLAttr]; implementing that interface does
-// not affect parameter classification.
+// values implementing [InputHandler], [ClickHandler], [ContextMenuHandler],
+// [KeyHandler], [OrderHandler], [DOMEventHandler] or [UploadHandler], as event
+// handlers. It does not invoke [InitialHTMLAttrHandler.JawsInitialHTMLAttr];
+// implementing that interface does not affect parameter classification.
 //
-// A nil [InputFn] is ignored.
+// A [BusyPolicy] is returned as its data-jawsbusy attribute. A nil [InputFn] is
+// ignored.
 //
 // A recognized event handler that is also usable as a tag is returned in both tags
 // and handlers.
@@ -45,6 +46,10 @@ func ParseParams(params []any) (tags []any, handlers []any, attrs []string) {
 			attrs = append(attrs, data)
 		case []string:
 			attrs = append(attrs, data...)
+		case BusyPolicy:
+			if attr := data.attr(); attr != "" {
+				attrs = append(attrs, attr)
+			}
 		case InputFn:
 			if data != nil {
 				handlers = append(handlers, data)
@@ -56,6 +61,14 @@ func ParseParams(params []any) (tags []any, handlers []any, attrs []string) {
 				handlers = append(handlers, data)
 			} else if _, ok := data.(ContextMenuHandler); ok {
 				handlers = append(handlers, data)
+			} else if _, ok := data.(KeyHandler); ok {
+				handlers = append(handlers, data)
+			} else if _, ok := data.(OrderHandler); ok {
+				handlers = append(handlers, data)
+			} else if _, ok := data.(DOMEventHandler); ok {
+				handlers = append(handlers, data)
+			} else if _, ok := data.(UploadHandler); ok {
+				handlers = append(handlers, data)
 			}
 			if eligibleAsTag(data, tag.NewErrNotUsableAsTag) {
 				tags = append(tags, data)
@@ -0,0 +1,202 @@
+package jaws
+
+import (
+	"slices"
+
+	"github.com/linkdata/jaws/lib/what"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+// SetUser associates the application user key user with the [Session].
+//
+// The user key identifies the same user across Sessions, such as several
+// browsers, for [Jaws.BroadcastUser], [Jaws.DirtyUser] and presence queries. An
+// empty user clears the association. When the Session has no user key as a
+// [Request] connects and [Jaws.MakeAuth] is set, the user key is taken from
+// [Auth.Email].
+//
+// With a [Jaws.SessionStore], the user key is saved with the Session.
+func (sess *Session) SetUser(user string) {
+	if sess != nil {
+		sess.mu.Lock()
+		changed := sess.user != user
+		sess.user = user
+		attached := len(sess.requests) > 0
+		sess.mu.Unlock()
+		if changed {
+			sess.save()
+			if attached {
+				sess.jw.presenceChanged()
+			}
+		}
+	}
+}
+
+// User returns the user key set with [Session.SetUser], or an empty string.
+func (sess *Session) User() (user string) {
+	if sess != nil {
+		sess.mu.RLock()
+		user = sess.user
+		sess.mu.RUnlock()
+	}
+	return
+}
+
+// User returns the user key of the Request's [Session], or an empty string.
+func (rq *Request) User() string {
+	return rq.Session().User()
+}
+
+// authUser sets the Session user key from [Jaws.MakeAuth] if the Session has none.
+func (rq *Request) authUser() {
+	if makeAuth := rq.Jaws.MakeAuth; makeAuth != nil {
+		if sess := rq.Session(); sess != nil && sess.User() == "" {
+			if auth := makeAuth(rq); auth != nil {
+				sess.SetUser(auth.Email())
+			}
+		}
+	}
+}
+
+// hasUserLocked reports whether the Request's Session has a user key. Caller
+// must hold rq.mu.
+func (rq *Request) hasUserLocked() (yes bool) {
+	if sess := rq.session; sess != nil {
+		sess.mu.RLock()
+		yes = sess.user != ""
+		sess.mu.RUnlock()
+	}
+	return
+}
+
+// PresenceTag returns this instance's presence tag.
+//
+// JaWS dirties it when a [Request] with a user key starts or stops running, and
+// when the user key of a Session with Requests changes. Tag the Elements that render
+// [Jaws.Presence] or [Jaws.PresenceOf] with it to keep them current. The tag is
+// stable for the Jaws lifetime and unique to this instance.
+func (jw *Jaws) PresenceTag() any {
+	return &jw.statusTags.presence
+}
+
+func (jw *Jaws) presenceChanged() {
+	jw.mu.Lock()
+	jw.presenceChangedLocked()
+	jw.mu.Unlock()
+}
+
+// presenceChangedLocked schedules the presence tag for the next dirty pass.
+// Caller must hold jw.mu.
+func (jw *Jaws) presenceChangedLocked() {
+	jw.dirtOrder++
+	jw.dirty[jw.PresenceTag()] = jw.dirtOrder
+}
+
+// presentUsers returns the sorted, distinct user keys of running Requests for
+// which match returns true. match is called with rq.mu held.
+func (jw *Jaws) presentUsers(match func(rq *Request) bool) (users []string) {
+	jw.mu.RLock()
+	for _, rq := range jw.requests {
+		if rq != nil && rq.loadState() == reqRunning {
+			rq.mu.RLock()
+			if rq.session != nil && match(rq) {
+				rq.session.mu.RLock()
+				if rq.session.user != "" {
+					users = append(users, rq.session.user)
+				}
+				rq.session.mu.RUnlock()
+			}
+			rq.mu.RUnlock()
+		}
+	}
+	jw.mu.RUnlock()
+	slices.Sort(users)
+	return slices.Compact(users)
+}
+
+// Presence returns the sorted, distinct user keys of the running Requests whose
+// initial HTTP request path is route.
+//
+// Requests without a user key are not included. See [Jaws.PresenceTag] for
+// keeping a rendering of the result current.
+func (jw *Jaws) Presence(route string) []string {
+	return jw.presentUsers(func(rq *Request) bool {
+		return rq.initial != nil && rq.initial.URL != nil && rq.initial.URL.Path == route
+	})
+}
+
+// PresenceOf returns the sorted, distinct user keys of the running Requests that
+// have an Element tagged with any of the tags tagValue expands to.
+//
+// Requests without a user key are not included. The expansion follows
+// [Jaws.MustTagExpand]. See [Jaws.PresenceTag] for keeping a rendering of the
+// result current.
+func (jw *Jaws) PresenceOf(tagValue any) []string {
+	tags := jw.MustTagExpand(tagValue)
+	return jw.presentUsers(func(rq *Request) bool {
+		for _, t := range tags {
+			if len(rq.tagMap[t]) > 0 {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// userRequests returns the registered Requests whose Session has the user key user.
+func (jw *Jaws) userRequests(user string) (requests []*Request) {
+	if user != "" {
+		jw.mu.RLock()
+		for _, rq := range jw.requests {
+			if rq != nil {
+				rq.mu.RLock()
+				if sess := rq.session; sess != nil && rq.loadState().registered() {
+					sess.mu.RLock()
+					if sess.user == user {
+						requests = append(requests, rq)
+					}
+					sess.mu.RUnlock()
+				}
+				rq.mu.RUnlock()
+			}
+		}
+		jw.mu.RUnlock()
+	}
+	return
+}
+
+// BroadcastUser sends msg to every active [Request] whose [Session] has the user
+// key user, replacing msg.Dest, like [Session.Broadcast] does for one Session.
+//
+// An empty user matches nothing. It must not be called before the JaWS
+// processing loop ([Jaws.Serve] or [Jaws.ServeWithTimeout]) is running.
+// Otherwise this call may block.
+func (jw *Jaws) BroadcastUser(user string, msg wire.Message) {
+	for _, rq := range jw.userRequests(user) {
+		if k := rq.destKey(); k != 0 {
+			msg.Dest = k
+			jw.Broadcast(msg)
+		}
+	}
+}
+
+// DirtyUser schedules updates for tags on the Requests whose [Session] has the
+// user key user, leaving the same tags on other Requests untouched.
+//
+// The tags are expanded as by [Jaws.Dirty], and are not published through the
+// [Jaws.Broadcaster]. A Request that has not connected yet applies them when
+// it does. An empty user matches nothing. It has the same processing loop
+// requirement as [Jaws.BroadcastUser].
+func (jw *Jaws) DirtyUser(user string, dirtyTags ...any) {
+	if requests := jw.userRequests(user); len(requests) > 0 {
+		tags := jw.MustTagExpand(dirtyTags)
+		for _, rq := range requests {
+			rq.appendDirtyTags(tags)
+			// The key-targeted Update only wakes a running process loop so it
+			// drains todoDirt; see Session.Close.
+			if k := rq.destKey(); k != 0 {
+				jw.Broadcast(wire.Message{Dest: k, What: what.Update})
+			}
+		}
+	}
+}
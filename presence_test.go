@@ -0,0 +1,146 @@
+package jaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/lib/tag"
+	"github.com/linkdata/jaws/lib/what"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+type testEmailAuth string
+
+func (testEmailAuth) Data() map[string]any { return nil }
+func (a testEmailAuth) Email() string      { return string(a) }
+func (testEmailAuth) IsAdmin() bool        { return false }
+
+func waitPresence(t *testing.T, get func() []string, want ...string) {
+	t.Helper()
+	deadline := time.Now().Add(testTimeout)
+	for !slices.Equal(get(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("presence %v, want %v", get(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPresence_UsersAndTargeting(t *testing.T) {
+	tj := newTestJaws()
+	t.Cleanup(tj.Close)
+	jw := tj.Jaws
+
+	newUserRequest := func(path, user string) *TestRequest {
+		t.Helper()
+		sess := jw.NewSession(nil, httptest.NewRequest(http.MethodGet, "/", nil))
+		sess.SetUser(user)
+		tr := tj.newRequest(newSessionTestRequest(sess, path))
+		waitTestRequestReady(t, tr)
+		if tr.User() != user {
+			t.Fatalf("user %q", tr.User())
+		}
+		return tr
+	}
+	alice := newUserRequest("/doc", "alice")
+	bob := newUserRequest("/doc", "bob")
+	alice2 := newUserRequest("/doc", "alice")
+	carol := newUserRequest("/other", "carol")
+	anon := tj.newRequest(httptest.NewRequest(http.MethodGet, "/doc", nil))
+	waitTestRequestReady(t, anon)
+	defer anon.Close()
+
+	watcher := &testUi{}
+	alice.NewElement(watcher).Tag(jw.PresenceTag())
+	carol.NewElement(&testUi{}).Tag(tag.Tag("doc-1"))
+	bobUi := &testUi{}
+	bob.NewElement(bobUi).Tag(tag.Tag("doc-1"))
+	aliceUi := &testUi{}
+	alice.NewElement(aliceUi).Tag(tag.Tag("doc-1"))
+
+	if got := jw.Presence("/doc"); !slices.Equal(got, []string{"alice", "bob"}) {
+		t.Errorf("Presence %v", got)
+	}
+	if got := jw.PresenceOf(tag.Tag("doc-1")); !slices.Equal(got, []string{"alice", "bob", "carol"}) {
+		t.Errorf("PresenceOf %v", got)
+	}
+
+	jw.BroadcastUser("bob", wire.Message{What: what.Alert, Data: "info\nhi bob"})
+	select {
+	case msg := <-bob.OutCh:
+		if msg.What != what.Alert || msg.Data != "info\nhi bob" {
+			t.Errorf("bob got %v", msg)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("bob got no alert")
+	}
+	select {
+	case msg := <-alice.OutCh:
+		t.Errorf("alice got %v", msg)
+	default:
+	}
+
+	jw.DirtyUser("bob", tag.Tag("doc-1"))
+	deadline := time.Now().Add(testTimeout)
+	for atomic.LoadInt32(&bobUi.updateCalled) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if atomic.LoadInt32(&bobUi.updateCalled) != 1 || atomic.LoadInt32(&aliceUi.updateCalled) != 0 {
+		t.Errorf("updates bob %d alice %d", bobUi.updateCalled, aliceUi.updateCalled)
+	}
+
+	before := atomic.LoadInt32(&watcher.updateCalled)
+	stopTestRequest(t, bob)
+	waitPresence(t, func() []string { return jw.Presence("/doc") }, "alice")
+	for atomic.LoadInt32(&watcher.updateCalled) == before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if atomic.LoadInt32(&watcher.updateCalled) == before {
+		t.Error("presence tag not dirtied")
+	}
+
+	stopTestRequest(t, alice)
+	waitPresence(t, func() []string { return jw.Presence("/doc") }, "alice")
+	stopTestRequest(t, alice2)
+	waitPresence(t, func() []string { return jw.Presence("/doc") })
+	stopTestRequest(t, carol)
+	if jw.Presence("/nowhere") != nil || jw.userRequests("") != nil {
+		t.Error("expected no presence")
+	}
+}
+
+func TestPresence_UserFromMakeAuthAndStore(t *testing.T) {
+	jw, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jw.Close()
+	jw.MakeAuth = func(rq *Request) Auth { return testEmailAuth("dave@example.com") }
+	hr := httptest.NewRequest(http.MethodGet, "/", nil)
+	sess := jw.NewSession(nil, hr)
+	rq := jw.NewRequest(httptest.NewRecorder(), hr)
+	rq.authUser()
+	if sess.User() != "dave@example.com" {
+		t.Errorf("user %q", sess.User())
+	}
+	sess.SetUser("erin")
+	rq.authUser()
+	if rq.User() != "erin" {
+		t.Errorf("user %q", rq.User())
+	}
+	sess.mu.RLock()
+	rec, _ := sess.recordLocked(time.Now())
+	sess.mu.RUnlock()
+	if rec.User != "erin" {
+		t.Errorf("record user %q", rec.User)
+	}
+	var nilSess *Session
+	nilSess.SetUser("x")
+	if nilSess.User() != "" {
+		t.Error("nil Session has a user")
+	}
+}
@@ -85,25 +85,36 @@ type requestBuffers struct {
 // non-running Request is retired. It then remains cancelled and unregistered. Its
 // pointer identity is never reused for another connection.
 type Request struct {
-	Jaws             *Jaws                   // (read-only) the JaWS instance the Request belongs to
-	JawsKey          key.Key                 // (read-only) random key assigned to this Request; routes JaWS URLs and request-targeted broadcasts only while registered
-	remoteIP         netip.Addr              // (read-only) remote IP, or the zero netip.Addr if unset
-	state            atomic.Int32            // reqState lifecycle (reqUnclaimable/reqPending/reqClaimed/reqRunning/reqFinished); see loadState/casState
-	lastWriteSeconds atomic.Int32            // [Jaws.runtimeSeconds] value at the most recent RequestWriter write; lock-free, drives pending-eviction preference (pendingEvictionVictimLocked) and idle expiry (maintenance)
-	mu               deadlock.RWMutex        // protects following
-	lastJid          Jid                     // last element Jid allocated within this Request
-	initial          *http.Request           // initial HTTP request passed to Jaws.NewRequest
-	session          *Session                // session, if established
-	todoDirt         []any                   // pending dirty tags and exact Element targets
-	ctx              context.Context         // current context, derived from either Jaws or WS HTTP req; stored in the struct because there is no call chain between Request creation and its use once the WebSocket exists
-	httpDoneCh       <-chan struct{}         // once claimed, set to http.Request.Context().Done()
-	cancelFn         context.CancelCauseFunc // cancel function
-	connectFn        ConnectFn               // a ConnectFn to call before starting message processing for the Request
-	buffers          *requestBuffers         // reusable storage borrowed from Jaws.requestBufferPool; returned to the pool on completion, kept on retirement
-	elems            []*Element              // our Elements
-	tagMap           map[any][]*Element      // maps tags to Elements
-	muQueue          deadlock.Mutex          // protects wsQueue and tailsent
-	wsQueue          []wire.WsMsg            // queued messages to send
+	Jaws             *Jaws                     // (read-only) the JaWS instance the Request belongs to
+	JawsKey          key.Key                   // (read-only) random key assigned to this Request; routes JaWS URLs and request-targeted broadcasts only while registered
+	remoteIP         netip.Addr                // (read-only) remote IP, or the zero netip.Addr if unset
+	state            atomic.Int32              // reqState lifecycle (reqUnclaimable/reqPending/reqClaimed/reqRunning/reqFinished); see loadState/casState
+	lastWriteSeconds atomic.Int32              // [Jaws.runtimeSeconds] value at the most recent RequestWriter write; lock-free, drives pending-eviction preference (pendingEvictionVictimLocked) and idle expiry (maintenance)
+	mu               deadlock.RWMutex          // protects following
+	lastJid          Jid                       // last element Jid allocated within this Request
+	initial          *http.Request             // initial HTTP request passed to Jaws.NewRequest
+	session          *Session                  // session, if established
+	todoDirt         []any                     // pending dirty tags and exact Element targets
+	acks             []*Element                // busy Elements to acknowledge after the current update pass; see sendAcks
+	ctx              context.Context           // current context, derived from either Jaws or WS HTTP req; stored in the struct because there is no call chain between Request creation and its use once the WebSocket exists
+	httpDoneCh       <-chan struct{}           // once claimed, set to http.Request.Context().Done()
+	cancelFn         context.CancelCauseFunc   // cancel function
+	connectFn        ConnectFn                 // a ConnectFn to call before starting message processing for the Request
+	buffers          *requestBuffers           // reusable storage borrowed from Jaws.requestBufferPool; returned to the pool on completion, kept on retirement
+	elems            []*Element                // our Elements
+	tagMap           map[any][]*Element        // maps tags to Elements
+	rpcFns           map[string]RPCFn          // RPC functions registered with Request.HandleRPC
+	askSeq           uint64                    // last question id sent with what.Ask
+	asks             map[uint64]chan askAnswer // questions awaiting a what.Answer
+	clientInfo       ClientInfo                // browser environment reported by jaws.js
+	downloads        map[key.Key]*download     // content staged by Request.Download until fetched
+	toastSeq         uint64                    // last toast id sent with what.Toast
+	toasts           map[uint64]*toastEntry    // toasts shown until a what.Dismiss
+	toastKeys        map[string]uint64         // ids of shown toasts by Toast.Key
+	incomingMsgCh    chan wire.WsMsg           // inbound records for the processing loop; set once WebSocket processing starts
+	resume           *resumeState              // non-nil while running with Jaws.ResumeWindow enabled; set once WebSocket processing starts
+	muQueue          deadlock.Mutex            // protects wsQueue and tailsent
+	wsQueue          []wire.WsMsg              // queued messages to send
 	tailsent         bool
 }
 
@@ -313,10 +324,16 @@ func (rq *Request) killSessionLocked(wasClaimed bool) {
 	}
 }
 
+// killSession detaches the Session, dirtying the presence tag if a running
+// Request leaves a Session with a user key.
 func (rq *Request) killSession(wasClaimed bool) {
 	rq.mu.Lock()
+	hadUser := rq.loadState() == reqRunning && rq.hasUserLocked()
 	rq.killSessionLocked(wasClaimed)
 	rq.mu.Unlock()
+	if hadUser {
+		rq.Jaws.presenceChanged()
+	}
 }
 
 // deadSession atomically detaches sess and arms one page reload, returning the
@@ -355,6 +372,8 @@ func (rq *Request) ensureAutoSession(w http.ResponseWriter, r *http.Request) {
 	if rq.Jaws.AutoSession {
 		if sess := rq.newAutoSession(r); sess != nil {
 			sess.addCookie(w, r)
+			sess.save()
+			rq.Jaws.notifyLifecycle(LifecycleEvent{Kind: SessionCreated, Session: sess})
 		}
 	}
 }
@@ -433,6 +452,7 @@ func (rq *Request) releaseBuffersLocked() (buffers *requestBuffers) {
 	}
 	rq.buffers = nil
 	rq.todoDirt = nil
+	rq.acks = nil
 	rq.elems = nil
 	rq.tagMap = nil
 
@@ -599,12 +619,23 @@ func (rq *Request) replaceContext(fn func(oldCtx context.Context) (newCtx contex
 // maintenance reports whether rq has expired and should be retired. For a
 // request that never went live it cancels and reports expiry once it has been
 // idle (no [RequestWriter] write) longer than requestTimeout, or immediately if its
-// context is already done. nowSeconds is the reference instant ([Jaws.runtimeSeconds]).
+// context is already done. A running Request detached from its WebSocket longer
+// than [Jaws.ResumeWindow] is cancelled but not reported. nowSeconds is the
+// reference instant ([Jaws.runtimeSeconds]).
 // Called from the Serve loop's maintenance pass while jw.mu is held.
 //
 // It returns the cancellation cause (or nil) for the caller to queue.
 func (rq *Request) maintenance(nowSeconds int32, requestTimeout time.Duration) (expired bool, cause error) {
-	if rq.loadState() != reqRunning {
+	if rq.loadState() == reqRunning {
+		// A running Request whose connection was lost is cancelled once the
+		// resume window passes; its processing loop then retires it. Like other
+		// lost connections, this is not logged.
+		rq.mu.Lock()
+		if rq.resume != nil && rq.resume.detachedFor(time.Now()) > rq.Jaws.ResumeWindow {
+			_ = rq.cancelLocked(ErrResumeWindowExpired)
+		}
+		rq.mu.Unlock()
+	} else {
 		rq.mu.Lock()
 		if rq.ctx.Err() != nil {
 			expired = true
@@ -669,15 +700,17 @@ func alertData(level, msg string) string {
 	return html.EscapeString(level) + "\n" + html.EscapeString(msg)
 }
 
-// Alert attempts to show an alert message on the current request webpage if it
-// has an HTML element with the data-jaws-alerts attribute.
+// Alert shows an alert message on the current request webpage, in the HTML
+// element with the data-jaws-alerts attribute if it has one.
 //
 // The level argument should be one of Bootstrap's alert levels: primary, secondary, success, danger, warning, info, light or dark.
 //
 // The level and msg are HTML-escaped before being sent, so it is safe to pass
 // untrusted text; do not pre-escape it.
 //
-// The default JaWS JavaScript only supports Bootstrap dismissible alerts.
+// If Bootstrap is loaded and the page has such an element, the default JaWS
+// JavaScript adds a dismissible alert to it. Otherwise it shows the alert as a
+// [Toast] with the given level.
 //
 // See [Request] for pointer lifetime and [Jaws.Broadcast] for processing-loop
 // requirements.
@@ -755,7 +788,7 @@ func (rq *Request) TagsOf(elem *Element) (tags []any) {
 // The receiver does not scope tag matching to rq. See [Jaws.Dirty] for expansion,
 // exact Element targeting, lifecycle, and batching behavior.
 func (rq *Request) Dirty(dirtyTags ...any) {
-	rq.Jaws.setDirty(rq.Jaws.MustTagExpand(dirtyTags))
+	rq.Jaws.Dirty(dirtyTags...)
 }
 
 // wantMessage returns true if the Request want the message.
@@ -888,8 +921,13 @@ func (rq *Request) appendDirtyTags(tags []any) {
 	rq.mu.Lock()
 	if rq.loadState().registered() {
 		for _, tagValue := range tags {
-			if elem, exact := tagValue.(*Element); exact {
-				if elem == nil || elem.Request != rq || elem.deleted.Load() {
+			switch v := tagValue.(type) {
+			case *Element:
+				if v == nil || v.Request != rq || v.deleted.Load() {
+					continue
+				}
+			case ackTag:
+				if v.elem.Request != rq {
 					continue
 				}
 			}
@@ -1140,20 +1178,29 @@ func (rq *Request) startServe() (ok bool) {
 	rq.mu.RLock()
 	registered := rq.Jaws.requests[rq.JawsKey] == rq
 	contextLive := rq.ctx != nil && rq.ctx.Err() == nil
+	hasUser := rq.hasUserLocked()
 	rq.mu.RUnlock()
 	// casState(reqClaimed, reqRunning) atomically requires the Request to be claimed
 	// (not already running, retired, or unclaimed) and transitions it to running.
-	return registered && contextLive && rq.casState(reqClaimed, reqRunning)
+	if ok = registered && contextLive && rq.casState(reqClaimed, reqRunning); ok && hasUser {
+		rq.Jaws.presenceChangedLocked()
+	}
+	return
 }
 
 func (rq *Request) stopServe() {
 	rq.cancel(nil)
-	rq.Jaws.recycle(rq)
+	rq.dropDownloads()
+	jw := rq.Jaws
+	jw.notifyRequests(RequestDisconnected, []*Request{rq})
+	if jw.recycle(rq) {
+		jw.notifyRequests(RequestRecycled, []*Request{rq})
+	}
 }
 
-// runWebSocket subscribes rq, runs its connect callback, and processes the
-// accepted WebSocket when the callback succeeds.
-func (rq *Request) runWebSocket(ws *websocket.Conn, idleInterval, wsTimeout time.Duration) (err error) {
+// connect subscribes rq and runs its connect callback, returning the broadcast
+// channel for processing once the callback succeeds.
+func (rq *Request) connect() (broadcastMsgCh chan wire.Message, err error) {
 	// Subscribe before onConnect so broadcasts from the callback are buffered for
 	// this Request. Browser input and outbound writes do not start until the
 	// callback succeeds.
@@ -1167,36 +1214,58 @@ func (rq *Request) runWebSocket(ws *websocket.Conn, idleInterval, wsTimeout time
 	pendingSubscription := rq.Jaws.subscribe(rq, 4+numElems*4)
 	defer func() {
 		// onConnect is user code and may return an error or panic. Release its
-		// subscription unless process took responsibility for doing so.
+		// subscription unless the caller took responsibility for doing so.
 		if pendingSubscription != nil {
 			rq.Jaws.unsubscribe(pendingSubscription)
 		}
 	}()
 
+	rq.authUser()
 	if err = rq.onConnect(); err == nil {
+		broadcastMsgCh = pendingSubscription
+		pendingSubscription = nil
+	}
+	return
+}
+
+// runWebSocket subscribes rq, runs its connect callback, and processes the
+// accepted WebSocket when the callback succeeds.
+//
+// If the browser navigates to another page, next is that page's claimed
+// Request, which is to take over conn with [Request.serveNavigation].
+func (rq *Request) runWebSocket(ws *websocket.Conn, r *http.Request, idleInterval, wsTimeout time.Duration) (next *Request, conn *wsConn, err error) {
+	var broadcastMsgCh chan wire.Message
+	if broadcastMsgCh, err = rq.connect(); err == nil {
 		incomingMsgCh := make(chan wire.WsMsg)
+		outboundMsgCh := make(chan wire.WsMsg, cap(broadcastMsgCh))
+		var rs *resumeState
+		if rq.Jaws.ResumeWindow > 0 {
+			rs = newResumeState(cap(outboundMsgCh))
+		}
 		// Snapshot ctx after onConnect so a context installed by the callback
 		// governs all WebSocket loops.
-		rq.mu.RLock()
+		rq.mu.Lock()
 		ctx := rq.ctx
-		rq.mu.RUnlock()
-		// Transport failures ordinarily only report that the peer is no longer
-		// reachable. A read-limit violation is actionable application feedback, so
-		// retain it even when transport debugging is disabled.
-		disconnect := func(err error) {
-			if !rq.Jaws.Debug && !errors.Is(err, websocket.ErrMessageTooBig) {
-				err = nil
-			}
-			rq.cancel(err)
+		rq.incomingMsgCh = incomingMsgCh
+		rq.resume = rs
+		rq.mu.Unlock()
+		// The connection loops outlive rq if the browser navigates, so they
+		// run on the connection context rather than the Request's.
+		conn = newWSConn(ctx, ws, r, incomingMsgCh, outboundMsgCh)
+		conn.link(rq)
+		if rs != nil {
+			// The connection loops are per connection; incomingMsgCh and
+			// outboundMsgCh outlive them so a resumed connection can take over.
+			conn.rs, rs.wc = rs, conn
+			go rs.pump(outboundMsgCh)
+			rs.attach(rq.Jaws, ws, 0)
+		} else {
+			go wire.ReadLoop(conn.ctx, conn.disconnect, rq.Jaws.Done(), incomingMsgCh, idleInterval, wsTimeout, ws)                          // closes incomingMsgCh
+			go wire.WriteLoopCounted(conn.ctx, conn.disconnect, rq.Jaws.Done(), outboundMsgCh, wsTimeout, ws, &rq.Jaws.metrics.bytesWritten) // calls ws.Close()
 		}
-		outboundMsgCh := make(chan wire.WsMsg, cap(pendingSubscription))
-		go wire.ReadLoop(ctx, disconnect, rq.Jaws.Done(), incomingMsgCh, idleInterval, wsTimeout, ws) // closes incomingMsgCh
-		go wire.WriteLoop(ctx, disconnect, rq.Jaws.Done(), outboundMsgCh, wsTimeout, ws)              // calls ws.Close()
-		broadcastMsgCh := pendingSubscription
-		pendingSubscription = nil
 		// Production deliberately discards the recovered value so a loop panic stays
 		// contained to the failed Request while its connection is torn down.
-		rq.process(broadcastMsgCh, incomingMsgCh, outboundMsgCh) // unsubscribes broadcastMsgCh, closes outboundMsgCh
+		next, _ = rq.processConn(broadcastMsgCh, incomingMsgCh, outboundMsgCh, conn) // unsubscribes broadcastMsgCh, closes outboundMsgCh unless navigating
 	}
 	return
 }
@@ -1220,45 +1289,10 @@ func (rq *Request) runWebSocket(ws *websocket.Conn, idleInterval, wsTimeout time
 // passed to [Jaws.Log] instead.
 func (rq *Request) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if rq.startServe() {
-		defer rq.stopServe()
-		idleInterval := rq.Jaws.WebSocketPingInterval
-		wsTimeout := rq.Jaws.getWebSocketTimeout()
-		if strings.HasSuffix(r.URL.Path, "/noscript") {
-			w.WriteHeader(http.StatusNoContent)
-			rq.cancel(ErrJavascriptDisabled)
-			return
+		next, conn := rq.serveWebSocket(w, r)
+		for next != nil {
+			next = next.serveNavigation(conn)
 		}
-		var err error
-		acceptRequest := r
-		acceptWriter := w
-		if r.Header.Get("Sec-WebSocket-Key") != "" {
-			if err = rq.validateWebSocketOrigin(r); err != nil {
-				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
-				rq.cancel(err)
-				return
-			}
-			if rq.Jaws.AutoSession && rq.Session() == nil {
-				// Defer AutoSession creation to the handshake commit point so a
-				// handshake websocket.Accept rejects leaves no session or cookie
-				// behind (see autoSessionWriter).
-				acceptWriter = &autoSessionWriter{ResponseWriter: w, rq: rq, r: r}
-			}
-			acceptRequest = normalizedWebSocketAcceptRequest(r)
-		}
-		var ws *websocket.Conn
-		ws, err = websocket.Accept(acceptWriter, acceptRequest, nil)
-		if err == nil {
-			ws.SetReadLimit(webSocketReadLimit)
-			if err = rq.runWebSocket(ws, idleInterval, wsTimeout); err != nil {
-				// A ConnectFn failure is terminal. Cancel before touching the socket so
-				// a non-reading peer cannot retain the Request, then close without a
-				// handshake because no WebSocket processing loops were started.
-				rq.cancel(err)
-				_ = ws.CloseNow()
-				return
-			}
-		}
-		rq.cancel(err)
 	} else {
 		// The Request was never claimed (UseRequest not called) or is already
 		// being served; either way its single-use token is invalid, so
@@ -1266,3 +1300,50 @@ func (rq *Request) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, http.StatusText(http.StatusGone), http.StatusGone)
 	}
 }
+
+// serveWebSocket accepts and serves the WebSocket for the running rq. If the
+// browser navigates to another page, it returns that page's claimed Request and
+// the connection it is to take over.
+func (rq *Request) serveWebSocket(w http.ResponseWriter, r *http.Request) (next *Request, conn *wsConn) {
+	defer rq.stopServe()
+	idleInterval := rq.Jaws.WebSocketPingInterval
+	wsTimeout := rq.Jaws.getWebSocketTimeout()
+	if strings.HasSuffix(r.URL.Path, "/noscript") {
+		w.WriteHeader(http.StatusNoContent)
+		rq.cancel(ErrJavascriptDisabled)
+		return
+	}
+	var err error
+	acceptRequest := r
+	acceptWriter := w
+	if r.Header.Get("Sec-WebSocket-Key") != "" {
+		if err = rq.validateWebSocketOrigin(r); err != nil {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			rq.cancel(err)
+			return
+		}
+		rq.setClientInfo(r.URL.Query().Get("client"))
+		if rq.Jaws.AutoSession && rq.Session() == nil {
+			// Defer AutoSession creation to the handshake commit point so a
+			// handshake websocket.Accept rejects leaves no session or cookie
+			// behind (see autoSessionWriter).
+			acceptWriter = &autoSessionWriter{ResponseWriter: w, rq: rq, r: r}
+		}
+		acceptRequest = normalizedWebSocketAcceptRequest(r)
+	}
+	var ws *websocket.Conn
+	ws, err = websocket.Accept(acceptWriter, acceptRequest, nil)
+	if err == nil {
+		ws.SetReadLimit(webSocketReadLimit)
+		if next, conn, err = rq.runWebSocket(ws, r, idleInterval, wsTimeout); err != nil {
+			// A ConnectFn failure is terminal. Cancel before touching the socket so
+			// a non-reading peer cannot retain the Request, then close without a
+			// handshake because no WebSocket processing loops were started.
+			rq.cancel(err)
+			_ = ws.CloseNow()
+			return
+		}
+	}
+	rq.cancel(err)
+	return
+}
@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/linkdata/jaws/lib/jid"
 	"github.com/linkdata/jaws/lib/key"
@@ -28,6 +29,17 @@ import (
 // loop panic is contained, a diagnostic is passed to [Jaws.Log], and the
 // recovered value is returned unchanged for the caller to handle.
 func (rq *Request) process(broadcastMsgCh chan wire.Message, incomingMsgCh <-chan wire.WsMsg, outboundMsgCh chan<- wire.WsMsg) (panicValue any) {
+	_, panicValue = rq.processConn(broadcastMsgCh, incomingMsgCh, outboundMsgCh, nil)
+	return
+}
+
+// processConn is [Request.process] for the WebSocket connection conn, which
+// may be nil if navigation is not supported.
+//
+// If the browser asks to navigate, processConn returns the claimed Request of
+// the new page as next after replying to the browser, leaving incomingMsgCh
+// and outboundMsgCh open for next to take over.
+func (rq *Request) processConn(broadcastMsgCh chan wire.Message, incomingMsgCh <-chan wire.WsMsg, outboundMsgCh chan<- wire.WsMsg, conn *wsConn) (next *Request, panicValue any) {
 	jawsDoneCh := rq.Jaws.Done()
 	// Snapshot cancelFn under rq.mu, the same way ServeHTTP does: its only writers
 	// (claim, getRequestLocked, releaseBuffersLocked) run strictly before or after
@@ -47,8 +59,22 @@ func (rq *Request) process(broadcastMsgCh chan wire.Message, incomingMsgCh <-cha
 		// process runs only for a running (hence claimed) Request, so its WebSocket
 		// earns the session grace window.
 		rq.killSession(rq.loadState().claimed())
+		if next != nil {
+			conn.unlink()
+		}
 		cancelFn(nil)
 		close(eventCallCh)
+		if next != nil {
+			// The reply follows every message rq sent, so the browser swaps in
+			// the new page only after it has applied them.
+			<-eventDoneCh
+			select {
+			case outboundMsgCh <- wire.WsMsg{What: what.Navigate, Data: next.JawsKeyString()}:
+				rq.Jaws.metrics.countSent(what.Navigate)
+			case <-conn.ctx.Done():
+			}
+			return
+		}
 		for {
 			select {
 			case _, ok := <-incomingMsgCh:
@@ -83,11 +109,15 @@ func (rq *Request) process(broadcastMsgCh chan wire.Message, incomingMsgCh <-cha
 		// Drain pending dirty tags and exact Element targets, then call
 		// JawsUpdate for the selected Elements. Updates queue browser messages
 		// on the Request.
-		for _, elem := range rq.makeUpdateList() {
-			elem.JawsUpdate()
+		if todo := rq.makeUpdateList(); len(todo) > 0 {
+			rq.Jaws.metrics.dirtyFanout.observe(dirtyFanoutBounds, int64(len(todo)))
+			for _, elem := range todo {
+				elem.JawsUpdate()
+			}
 		}
 
 		rq.sendQueue(outboundMsgCh)
+		rq.sendAcks(outboundMsgCh)
 
 		select {
 		case <-jawsDoneCh:
@@ -96,6 +126,11 @@ func (rq *Request) process(broadcastMsgCh chan wire.Message, incomingMsgCh <-cha
 		case tagmsg, ok = <-broadcastMsgCh:
 		case wsmsg, ok = <-incomingMsgCh:
 			if ok {
+				if wsmsg.What == what.Navigate && conn != nil {
+					if next = rq.claimNavigation(conn, wsmsg.Data); next != nil {
+						return
+					}
+				}
 				// incoming event message from the WebSocket
 				rq.handleIncoming(wsmsg, eventCallCh)
 				continue
@@ -114,10 +149,25 @@ func (rq *Request) process(broadcastMsgCh chan wire.Message, incomingMsgCh <-cha
 // handleIncoming processes a single incoming WebSocket event message, queuing an
 // event-function call or handling a child removal. Called only from process.
 func (rq *Request) handleIncoming(wsmsg wire.WsMsg, eventCallCh chan eventFnCall) {
+	rq.Jaws.metrics.countReceived(wsmsg.What)
 	if wsmsg.Jid.IsValid() {
 		switch wsmsg.What {
-		case what.Input, what.Click, what.ContextMenu, what.Set:
+		case what.Input, what.Click, what.ContextMenu, what.KeyDown, what.KeyUp, what.Reorder, what.DOMEvent, what.Set:
 			rq.queueEvent(eventCallCh, rq.resolveEventFnCall(wsmsg.Jid, wsmsg.What, wsmsg.Data))
+		case what.RPC, what.Dismiss:
+			// RPC calls and toast callbacks have no target Element; they are
+			// queued so they run on the event goroutine in order with the
+			// Request's events.
+			rq.queueEvent(eventCallCh, eventFnCall{wht: wsmsg.What, data: wsmsg.Data})
+		case what.Answer:
+			// Answers are delivered here rather than queued, since the handler
+			// waiting for one is blocking the event goroutine.
+			rq.handleAnswer(wsmsg.Data)
+		case what.Client:
+			rq.handleClientInfo(wsmsg.Data)
+		case what.Navigate:
+			// A navigation that processConn did not hand over is refused.
+			rq.queue(wire.WsMsg{What: what.Navigate})
 		case what.Remove:
 			rq.handleRemove(wsmsg.Jid, wsmsg.Data)
 		}
@@ -128,11 +178,11 @@ func (rq *Request) handleIncoming(wsmsg wire.WsMsg, eventCallCh chan eventFnCall
 // message destination to the affected elements and dispatches by command. Called
 // only from process.
 func (rq *Request) handleBroadcast(tagmsg wire.Message, eventCallCh chan eventFnCall) {
-	// Reload, Redirect, Order and Alert are page-global commands: they apply to
-	// the whole document, so emit the single Jid:0 frame and return before
-	// resolving Dest.
+	// Reload, Redirect, Order, Alert, Ask, Download, Clipboard and Toast are
+	// page-global commands: they apply to the whole document, so emit the single
+	// Jid:0 frame and return before resolving Dest.
 	switch tagmsg.What {
-	case what.Reload, what.Redirect, what.Order, what.Alert:
+	case what.Reload, what.Redirect, what.Order, what.Alert, what.Ask, what.Download, what.Clipboard, what.Toast:
 		rq.queue(wire.WsMsg{
 			Jid:  0,
 			Data: tagmsg.Data,
@@ -174,8 +224,9 @@ func (rq *Request) handleBroadcast(tagmsg wire.Message, eventCallCh chan eventFn
 				What: what.Delete,
 			})
 			rq.DeleteElement(elem)
-		case what.Input, what.Click, what.ContextMenu:
-			// Input, Click or ContextMenu messages received here come from broadcasts;
+		case what.Input, what.Click, what.ContextMenu, what.KeyDown, what.KeyUp, what.Reorder, what.DOMEvent:
+			// Input, Click, ContextMenu, key, reorder or DOM event messages received
+			// here come from broadcasts;
 			// primarily used in tests by injecting a wire.WsMsg on the inbound channel.
 			// they won't be sent out on the WebSocket, but will queue up a
 			// call to the event function (if any).
@@ -358,16 +409,22 @@ func (rq *Request) callAllEventHandlers(id Jid, wht what.What, value string) (er
 // fallen too far behind to stay consistent (an event would be lost), so it is
 // cancelled rather than dropping the event, mirroring the broadcast back-pressure
 // path in [Jaws.ServeWithTimeout]. cancel takes rq.mu, which the process loop does
-// not hold when calling this. Calls without a resolved target are ignored.
+// not hold when calling this. Calls without a resolved target are ignored, except
+// for [what.RPC], which has none.
 func (rq *Request) queueEvent(eventCallCh chan eventFnCall, call eventFnCall) {
-	if call.elem == nil {
+	if call.elem == nil && call.wht != what.RPC && call.wht != what.Dismiss {
 		return
 	}
 	select {
 	case eventCallCh <- call:
 	default:
-		targets := 1 + len(call.more)
-		rq.cancel(fmt.Errorf("%w: %v: eventCallCh full sending FirstTarget=%v Targets=%d What=%v Data=%q", ErrRequestOverloaded, rq, call.elem.Jid(), targets, call.wht, call.data))
+		var firstTarget Jid
+		targets := len(call.more)
+		if call.elem != nil {
+			firstTarget = call.elem.Jid()
+			targets++
+		}
+		rq.cancel(fmt.Errorf("%w: %v: eventCallCh full sending FirstTarget=%v Targets=%d What=%v Data=%q", ErrRequestOverloaded, rq, firstTarget, targets, call.wht, call.data))
 	}
 }
 
@@ -438,6 +495,7 @@ func (rq *Request) sendQueue(outboundMsgCh chan<- wire.WsMsg) {
 		select {
 		case <-done:
 		case outboundMsgCh <- msg:
+			rq.Jaws.metrics.countSent(msg.What)
 		}
 	}
 }
@@ -521,11 +579,16 @@ func (rq *Request) DeleteElements(elems []*Element) {
 
 // makeUpdateList drains exact Element targets and pending-dirt tags, resolves them
 // to distinct Elements, and returns them sorted by Jid. It takes rq.mu. The Request
-// processing loop calls JawsUpdate on each returned Element.
+// processing loop calls JawsUpdate on each returned Element. Pending Acks are moved
+// to rq.acks for sendAcks.
 func (rq *Request) makeUpdateList() (todo []*Element) {
 	rq.mu.Lock()
 	seen := map[*Element]struct{}{}
 	for _, tagValue := range rq.todoDirt {
+		if ack, ok := tagValue.(ackTag); ok {
+			rq.acks = append(rq.acks, ack.elem)
+			continue
+		}
 		if elem, exact := tagValue.(*Element); exact {
 			// appendDirtyTags establishes ownership and liveness. Deletion removes
 			// queued targets under rq.mu; JawsUpdate handles deletion after this drain.
@@ -563,7 +626,20 @@ func (rq *Request) eventCaller(eventCallCh <-chan eventFnCall, outboundMsgCh cha
 			continue
 		default:
 		}
-		if err := rq.Jaws.Log(call.invoke()); err != nil {
+		if call.wht == what.RPC {
+			rq.replyRPC(call, outboundMsgCh)
+			continue
+		}
+		var err error
+		if call.wht == what.Dismiss {
+			err = rq.toastDismissed(call.data)
+		} else {
+			start := time.Now()
+			err = call.invoke()
+			rq.Jaws.metrics.observeEvent(call.wht, time.Since(start))
+			rq.notifyEvent(call, err)
+		}
+		if err = rq.Jaws.Log(err); err != nil {
 			var m wire.WsMsg
 			m.FillAlert(err)
 			// This error alert is best-effort: unlike queueEvent, which cancels the
@@ -574,10 +650,14 @@ func (rq *Request) eventCaller(eventCallCh <-chan eventFnCall, outboundMsgCh cha
 			// discarded rather than tearing down the Request.
 			select {
 			case outboundMsgCh <- m:
+				rq.Jaws.metrics.countSent(m.What)
 			default:
 				_ = rq.Jaws.Log(fmt.Errorf("jaws: outboundMsgCh full sending event error '%s'", err.Error()))
 			}
 		}
+		if call.elem != nil && call.elem.busy.Load() {
+			rq.queueAck(call.elem)
+		}
 	}
 }
 
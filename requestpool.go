@@ -64,7 +64,12 @@ func (jw *Jaws) NewRequest(w http.ResponseWriter, r *http.Request) *Request {
 
 func (jw *Jaws) newRequest(r *http.Request) (rq *Request) {
 	remoteIP := jw.clientIP(r)
+	if r != nil && jw.SessionStore != nil {
+		jw.restoreSessions(getCookieSessionsIDs(r.Header, jw.CookieName))
+	}
 
+	var evicted []*Request
+	closed := false
 	func() {
 		jw.mu.Lock()
 		defer jw.mu.Unlock()
@@ -73,12 +78,11 @@ func (jw *Jaws) newRequest(r *http.Request) (rq *Request) {
 		// to advance the counter, so a stale value could otherwise make an idle
 		// pending Request look freshly written and lose eviction preference.
 		jw.refreshRuntimeSeconds()
-		closed := false
 		select {
 		case <-jw.closeCh:
 			closed = true
 		default:
-			jw.limitPendingRequestsLocked(remoteIP)
+			evicted = jw.limitPendingRequestsLocked(remoteIP)
 		}
 		for rq == nil {
 			jawsKey := jw.nonZeroRandomLocked()
@@ -94,6 +98,10 @@ func (jw *Jaws) newRequest(r *http.Request) (rq *Request) {
 			}
 		}
 	}()
+	jw.notifyRequests(RequestRecycled, evicted)
+	if !closed {
+		jw.notifyLifecycle(LifecycleEvent{Kind: RequestCreated, Request: rq})
+	}
 	return
 }
 
@@ -113,8 +121,8 @@ func (jw *Jaws) refreshRuntimeSeconds() {
 }
 
 // limitPendingRequestsLocked evicts pending Requests for remoteIP until the cap is
-// satisfied. Caller must hold jw.mu.
-func (jw *Jaws) limitPendingRequestsLocked(remoteIP netip.Addr) {
+// satisfied, returning the evicted Requests. Caller must hold jw.mu.
+func (jw *Jaws) limitPendingRequestsLocked(remoteIP netip.Addr) (evicted []*Request) {
 	limit := jw.MaxPendingRequestsPerIP
 	if limit > 0 {
 		nowSeconds := jw.runtimeSeconds.Load()
@@ -124,7 +132,10 @@ func (jw *Jaws) limitPendingRequestsLocked(remoteIP netip.Addr) {
 			if cause := jw.retireNonRunningRequestWithCauseLocked(victim, newErrTooManyPendingRequests(remoteIP, limit)); cause != nil {
 				_ = jw.Log(cause)
 			}
-			if len(jw.pending[remoteIP]) >= before {
+			if len(jw.pending[remoteIP]) < before {
+				jw.metrics.evictions.Add(1)
+				evicted = append(evicted, victim)
+			} else {
 				// Retirement declines a running Request or one that lost registry
 				// identity. Neither can be pending, but if that invariant ever broke
 				// the loop would reselect the same victim forever while holding jw.mu,
@@ -133,6 +144,7 @@ func (jw *Jaws) limitPendingRequestsLocked(remoteIP netip.Addr) {
 			}
 		}
 	}
+	return
 }
 
 // pendingEvictionVictimLocked returns the pending [Request] for remoteIP to
@@ -238,6 +250,9 @@ func (jw *Jaws) UseRequest(jawsKey key.Key, r *http.Request) (rq *Request) {
 		}
 		jw.mu.Unlock()
 		_ = jw.Log(err)
+		if rq != nil {
+			jw.notifyLifecycle(LifecycleEvent{Kind: RequestClaimed, Request: rq})
+		}
 	}
 	return
 }
@@ -301,28 +316,29 @@ func releaseRetiredRequestKey(retired retiredRequestKey) {
 }
 
 // retireNonRunningRequestLocked cancels and unregisters rq without an error
-// cause, clearing, or pooling it. A nil entry keeps its key reserved until a
-// runtime cleanup runs after the Request becomes unreachable. Caller must hold
-// jw.mu, and rq must not be running.
-func (jw *Jaws) retireNonRunningRequestLocked(rq *Request) {
-	jw.retireNonRunningRequestCoreLocked(rq, nil, nil)
+// cause, clearing, or pooling it, and reports whether it did. A nil entry keeps
+// its key reserved until a runtime cleanup runs after the Request becomes
+// unreachable. Caller must hold jw.mu, and rq must not be running.
+func (jw *Jaws) retireNonRunningRequestLocked(rq *Request) (retired bool) {
+	return jw.retireNonRunningRequestCoreLocked(rq, nil, nil)
 }
 
 // retireNonRunningRequestWithCauseLocked cancels and unregisters rq with err
 // without clearing or pooling it. Caller must hold jw.mu, rq must not be running,
 // and err must be non-nil.
 func (jw *Jaws) retireNonRunningRequestWithCauseLocked(rq *Request, err error) (cause error) {
-	jw.retireNonRunningRequestCoreLocked(rq, err, &cause)
+	_ = jw.retireNonRunningRequestCoreLocked(rq, err, &cause)
 	return
 }
 
 // retireNonRunningRequestCoreLocked implements normal and cause-bearing
 // retirement. A nil causeOut selects normal cancellation; otherwise err must be
 // non-nil and the resulting cancellation cause is stored in causeOut. Caller
-// must hold jw.mu, and rq must not be running.
-func (jw *Jaws) retireNonRunningRequestCoreLocked(rq *Request, err error, causeOut *error) {
+// must hold jw.mu, and rq must not be running. It reports whether rq was retired.
+func (jw *Jaws) retireNonRunningRequestCoreLocked(rq *Request, err error, causeOut *error) (retired bool) {
 	rq.mu.Lock()
 	if rq.JawsKey != 0 && jw.requests[rq.JawsKey] == rq && rq.loadState() != reqRunning {
+		retired = true
 		jawsKey := rq.JawsKey
 		if causeOut != nil {
 			*causeOut = rq.cancelLocked(err)
@@ -341,6 +357,7 @@ func (jw *Jaws) retireNonRunningRequestCoreLocked(rq *Request, err error, causeO
 	}
 	rq.mu.Unlock()
 	runtime.KeepAlive(rq)
+	return
 }
 
 // recycleLockedWithCause finishes rq and returns its reusable buffers to
@@ -349,12 +366,16 @@ func (jw *Jaws) retireNonRunningRequestCoreLocked(rq *Request, err error, causeO
 // borrower retains it.
 //
 // It uses err as the cancellation cause when non-nil.
-// It returns the cancellation cause (or nil) for its caller to queue. Caller must
-// hold jw.mu.
-func (jw *Jaws) recycleLockedWithCause(rq *Request, err error) (cause error) {
+// It reports whether rq was still registered and so was recycled, and returns the
+// cancellation cause (or nil) for its caller to queue. Caller must hold jw.mu.
+func (jw *Jaws) recycleLockedWithCause(rq *Request, err error) (recycled bool, cause error) {
 	var buffers *requestBuffers
 	rq.mu.Lock()
 	if rq.JawsKey != 0 && jw.requests[rq.JawsKey] == rq {
+		recycled = true
+		if rq.loadState() == reqRunning && rq.hasUserLocked() {
+			jw.presenceChangedLocked()
+		}
 		jawsKey := rq.JawsKey
 		cause = rq.cancelLocked(err)
 		jw.removePendingRequestLocked(rq)
@@ -379,14 +400,15 @@ func (jw *Jaws) recycleLockedWithCause(rq *Request, err error) (cause error) {
 	return
 }
 
-func (jw *Jaws) recycleLocked(rq *Request) {
-	_ = jw.recycleLockedWithCause(rq, nil) // nil err yields a nil cause; nothing to log
+func (jw *Jaws) recycleLocked(rq *Request) (recycled bool) {
+	recycled, _ = jw.recycleLockedWithCause(rq, nil) // nil err yields a nil cause; nothing to log
+	return
 }
 
-func (jw *Jaws) recycle(rq *Request) {
+func (jw *Jaws) recycle(rq *Request) (recycled bool) {
 	jw.mu.Lock()
 	defer jw.mu.Unlock()
-	jw.recycleLocked(rq)
+	return jw.recycleLocked(rq)
 }
 
 // cancelIfCurrent cancels rq only if it is still the [Request] registered for
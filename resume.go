@@ -0,0 +1,244 @@
+package jaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/linkdata/deadlock"
+	"github.com/linkdata/jaws/lib/key"
+	"github.com/linkdata/jaws/lib/what"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+const (
+	// resumeLogRecords and resumeLogBytes bound the outbound records a Request
+	// keeps for replay. A client that missed more than that reloads instead.
+	resumeLogRecords = 1024
+	resumeLogBytes   = 1 << 20
+
+	// statusResumeRejected is the WebSocket close code sent when a resume
+	// handshake reaches a live Request that cannot replay what the client missed.
+	statusResumeRejected = websocket.StatusCode(4000)
+)
+
+// resumeState lets a running [Request] outlive its WebSocket for
+// [Jaws.ResumeWindow]. Outbound records pass through pump, which numbers them,
+// keeps the most recent ones for replay and forwards them to the attached
+// connection, if any. Only the connection-level loops stop when a connection
+// is lost; the Request's processing loop keeps running.
+//
+// Like the channels of wc, a resumeState is handed over to the Request the
+// browser navigates to. Numbering then starts over, as the browser counts the
+// records of the new page from zero.
+type resumeState struct {
+	wc       *wsConn        // the handed over connection; set once before pump starts
+	mu       deadlock.Mutex // leaf lock; protects following
+	seq      uint64         // number of records passed to pump
+	log      []wire.WsMsg   // the most recent records, the last one being number seq
+	logBytes int
+	conn     *resumeConn // attached connection, or nil
+	detached time.Time   // when conn became nil; zero while attached
+	closed   bool        // set when the processing loop has ended
+	capacity int         // capacity of the Request outbound channel
+}
+
+// resumeConn is one WebSocket connection attached to a resumable Request.
+type resumeConn struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	outCh  chan wire.WsMsg
+	wg     sync.WaitGroup
+}
+
+func newResumeState(capacity int) *resumeState {
+	return &resumeState{capacity: capacity}
+}
+
+// recordLocked appends msg to the replay log, trimming the oldest records
+// beyond the log bounds. Caller must hold rs.mu.
+func (rs *resumeState) recordLocked(msg wire.WsMsg) {
+	rs.seq++
+	rs.log = append(rs.log, msg)
+	rs.logBytes += len(msg.Data)
+	n := 0
+	for len(rs.log)-n > resumeLogRecords || (rs.logBytes > resumeLogBytes && n < len(rs.log)) {
+		rs.logBytes -= len(rs.log[n].Data)
+		n++
+	}
+	if n > 0 {
+		clear(rs.log[:n])
+		rs.log = rs.log[n:]
+	}
+}
+
+// replayLocked returns the records after the first seq ones, and false if they
+// are no longer all available. Caller must hold rs.mu.
+func (rs *resumeState) replayLocked(seq uint64) (replay []wire.WsMsg, ok bool) {
+	if !rs.closed && seq <= rs.seq && rs.seq-seq <= uint64(len(rs.log)) {
+		replay = slices.Clone(rs.log[len(rs.log)-int(rs.seq-seq):]) // #nosec G115 -- bounded by len(rs.log) above
+		ok = true
+	}
+	return
+}
+
+// pump numbers and records each record the processing loop sends on
+// outboundMsgCh, and forwards it to the attached connection. A Navigate reply
+// handing the connection over to another Request clears the records instead.
+// It ends when the last processing loop closes outboundMsgCh, closing the
+// attached connection's outbound channel so its WriteLoop closes the WebSocket.
+func (rs *resumeState) pump(outboundMsgCh <-chan wire.WsMsg) {
+	for msg := range outboundMsgCh {
+		rs.mu.Lock()
+		if msg.What == what.Navigate && msg.Data != "" {
+			clear(rs.log)
+			rs.seq, rs.log, rs.logBytes = 0, rs.log[:0], 0
+		} else {
+			rs.recordLocked(msg)
+		}
+		conn := rs.conn
+		rs.mu.Unlock()
+		if conn != nil {
+			select {
+			case conn.outCh <- msg:
+			case <-conn.ctx.Done():
+			}
+		}
+	}
+	rs.mu.Lock()
+	conn := rs.conn
+	rs.conn = nil
+	rs.closed = true
+	rs.mu.Unlock()
+	if conn != nil {
+		close(conn.outCh)
+	}
+}
+
+// attach starts serving ws for the Request currently served by rs.wc, first
+// replaying the records after the first seq ones. Any previously attached
+// connection is dropped. It returns nil if those records are no longer
+// available.
+func (rs *resumeState) attach(jw *Jaws, ws *websocket.Conn, seq uint64) (conn *resumeConn) {
+	rs.mu.Lock()
+	replay, ok := rs.replayLocked(seq)
+	if ok {
+		if rs.conn != nil {
+			rs.conn.cancel(nil)
+		}
+		conn = &resumeConn{outCh: make(chan wire.WsMsg, len(replay)+rs.capacity)}
+		conn.ctx, conn.cancel = context.WithCancelCause(rs.wc.ctx)
+		for _, msg := range replay {
+			conn.outCh <- msg
+		}
+		rs.conn = conn
+		rs.detached = time.Time{}
+	}
+	rs.mu.Unlock()
+	if conn != nil {
+		wsTimeout := jw.getWebSocketTimeout()
+		incomingMsgCh := rs.wc.incomingMsgCh
+		disconnect := func(err error) { rs.disconnect(rs.wc.current.Load(), conn, err) }
+		connMsgCh := make(chan wire.WsMsg)
+		conn.wg.Go(func() {
+			wire.ReadLoop(conn.ctx, disconnect, jw.Done(), connMsgCh, jw.WebSocketPingInterval, wsTimeout, ws) // closes connMsgCh
+		})
+		conn.wg.Go(func() {
+			wire.WriteLoopCounted(conn.ctx, disconnect, jw.Done(), conn.outCh, wsTimeout, ws, &jw.metrics.bytesWritten) // calls ws.Close()
+		})
+		conn.wg.Go(func() {
+			for msg := range connMsgCh {
+				select {
+				case incomingMsgCh <- msg:
+				case <-conn.ctx.Done():
+				}
+			}
+			rs.detach(conn)
+		})
+	}
+	return
+}
+
+// disconnect handles a transport failure of conn. A read limit violation or a
+// close handshake from the browser ends the Request as it would without
+// resume; other failures detach conn so the browser may resume.
+func (rs *resumeState) disconnect(rq *Request, conn *resumeConn, err error) {
+	switch {
+	case errors.Is(err, websocket.ErrMessageTooBig):
+		rq.cancel(err)
+	case websocket.CloseStatus(err) == websocket.StatusNormalClosure, websocket.CloseStatus(err) == websocket.StatusGoingAway:
+		rq.cancel(nil)
+	default:
+		if rq.Jaws.Debug {
+			_ = rq.Jaws.Log(fmt.Errorf("jaws: %v: connection lost: %w", rq, err))
+		}
+		rs.detach(conn)
+	}
+}
+
+// detach marks conn as no longer attached and stops its loops.
+func (rs *resumeState) detach(conn *resumeConn) {
+	rs.mu.Lock()
+	if rs.conn == conn {
+		rs.conn = nil
+		rs.detached = time.Now()
+	}
+	rs.mu.Unlock()
+	conn.cancel(nil)
+}
+
+// detachedFor returns how long the Request has had no connection attached.
+func (rs *resumeState) detachedFor(now time.Time) (d time.Duration) {
+	rs.mu.Lock()
+	if !rs.detached.IsZero() {
+		d = now.Sub(rs.detached)
+	}
+	rs.mu.Unlock()
+	return
+}
+
+// resumableRequest returns the running, resumable Request with the given key
+// if the client IP of r matches.
+func (jw *Jaws) resumableRequest(jawsKey key.Key, r *http.Request) (rq *Request, rs *resumeState) {
+	if found := jw.runningRequest(jawsKey, r); found != nil {
+		found.mu.RLock()
+		if found.resume != nil {
+			rq, rs = found, found.resume
+		}
+		found.mu.RUnlock()
+	}
+	return
+}
+
+// serveResume handles a WebSocket handshake for "/jaws/<key>?resume=<seq>",
+// where seq is the number of records the browser has processed.
+//
+// The handler returns when the resumed connection ends.
+func (jw *Jaws) serveResume(w http.ResponseWriter, r *http.Request, jawsKey key.Key) {
+	rq, rs := jw.resumableRequest(jawsKey, r)
+	seq, err := strconv.ParseUint(r.URL.Query().Get("resume"), 10, 64)
+	if rq == nil || err != nil || r.Header.Get("Sec-WebSocket-Key") == "" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err = rq.validateWebSocketOrigin(r); err != nil {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		_ = jw.Log(err)
+		return
+	}
+	var ws *websocket.Conn
+	if ws, err = websocket.Accept(w, normalizedWebSocketAcceptRequest(r), nil); err == nil {
+		ws.SetReadLimit(webSocketReadLimit)
+		if conn := rs.attach(jw, ws, seq); conn != nil {
+			conn.wg.Wait()
+		} else {
+			_ = ws.Close(statusResumeRejected, "")
+		}
+	}
+}
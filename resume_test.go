@@ -0,0 +1,146 @@
+package jaws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/linkdata/jaws/lib/what"
+)
+
+func newResumeTestRequest(t *testing.T, window time.Duration) (jw *Jaws, server *httptest.Server, rq *Request, dial func(query string) (*websocket.Conn, error)) {
+	t.Helper()
+	var err error
+	if jw, err = New(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(jw.Close)
+	jw.ResumeWindow = window
+	go jw.Serve()
+	waitForServeLoop(t, jw)
+	server = httptest.NewServer(jw)
+	t.Cleanup(server.Close)
+
+	hr := httptest.NewRequest(http.MethodGet, server.URL+"/", nil)
+	hr.RemoteAddr = "127.0.0.1:1"
+	rq = jw.newRequest(hr)
+	dial = func(query string) (*websocket.Conn, error) {
+		hdr := http.Header{}
+		hdr.Set("Origin", server.URL)
+		ctx, cancel := context.WithTimeout(t.Context(), 3*time.Second)
+		defer cancel()
+		conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/jaws/"+rq.JawsKeyString()+query, &websocket.DialOptions{HTTPHeader: hdr})
+		return conn, err
+	}
+	return
+}
+
+func waitDetached(t *testing.T, rq *Request) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		rq.mu.RLock()
+		rs := rq.resume
+		rq.mu.RUnlock()
+		if rs != nil && rs.detachedFor(time.Now()) > 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("Request was not detached")
+}
+
+func readUntil(t *testing.T, conn *websocket.Conn, marker string) string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(t.Context(), 3*time.Second)
+	defer cancel()
+	var acc strings.Builder
+	for !strings.Contains(acc.String(), marker) {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			t.Fatalf("read: %v (got %q)", err, acc.String())
+		}
+		acc.Write(data)
+	}
+	return acc.String()
+}
+
+func TestResume_ReplaysMissedRecords(t *testing.T) {
+	jw, _, rq, dial := newResumeTestRequest(t, time.Minute)
+	conn, err := dial("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jw.Alert("info", "first")
+	readUntil(t, conn, "first")
+	_ = conn.CloseNow()
+	waitDetached(t, rq)
+
+	jw.Alert("info", "missed")
+	if rq.Context().Err() != nil {
+		t.Fatal("Request cancelled while detached")
+	}
+
+	if conn, err = dial("?resume=5"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = conn.Read(t.Context()); websocket.CloseStatus(err) != statusResumeRejected {
+		t.Fatalf("resume beyond sent records: %v", err)
+	}
+
+	if conn, err = dial("?resume=1"); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+	got := readUntil(t, conn, "missed")
+	if strings.Contains(got, "first") {
+		t.Errorf("replayed a record the client already had: %q", got)
+	}
+	jw.Alert("info", "live")
+	readUntil(t, conn, "live")
+	if n := strings.Count(got, what.Alert.String()); n != 1 {
+		t.Errorf("got %d alerts, want 1: %q", n, got)
+	}
+}
+
+func TestResume_WindowExpires(t *testing.T) {
+	jw, _, rq, dial := newResumeTestRequest(t, time.Millisecond)
+	conn, err := dial("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.CloseNow()
+	waitDetached(t, rq)
+	time.Sleep(5 * time.Millisecond)
+	jw.maintenance(time.Minute)
+	select {
+	case <-rq.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("Request not cancelled after resume window")
+	}
+	if err := context.Cause(rq.Context()); !errors.Is(err, ErrResumeWindowExpired) {
+		t.Error(err)
+	}
+	if _, err = dial("?resume=0"); err == nil {
+		t.Error("resumed an expired Request")
+	}
+}
+
+func TestResume_CloseHandshakeEndsRequest(t *testing.T) {
+	_, _, rq, dial := newResumeTestRequest(t, time.Minute)
+	conn, err := dial("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.Close(websocket.StatusGoingAway, "")
+	select {
+	case <-rq.Context().Done():
+	case <-time.After(3 * time.Second):
+		t.Fatal("Request kept after a close handshake")
+	}
+}
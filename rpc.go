@@ -0,0 +1,146 @@
+package jaws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linkdata/jaws/lib/what"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+// DefaultRPCTimeout bounds the context passed to an [RPCFn].
+const DefaultRPCTimeout = time.Second * 30
+
+// ErrRPCNotFound is returned to the browser for an RPC call naming a function
+// that has no registered [RPCFn].
+var ErrRPCNotFound = errors.New("rpc function not found")
+
+// RPCFn handles a browser RPC call.
+//
+// args holds the JSON arguments passed by the browser, or "null" if none were
+// given. The returned result is marshalled with [encoding/json] and resolves the
+// browser Promise; a non-nil err rejects it with err's message instead.
+//
+// ctx is derived from [Request.Context] and times out after [DefaultRPCTimeout].
+// RPC functions run on the Request's event goroutine, serialized with its event
+// handlers, so they should return promptly.
+type RPCFn = func(ctx context.Context, rq *Request, args json.RawMessage) (result any, err error)
+
+// HandleRPC registers fn as the RPC function name for all Requests.
+//
+// The bundled client calls it with jawsRPC(name, args), which returns a Promise
+// settled with the result of fn. A function registered with
+// [Request.HandleRPC] takes precedence. A nil fn removes the registration.
+func (jw *Jaws) HandleRPC(name string, fn RPCFn) {
+	jw.mu.Lock()
+	jw.rpcFns = setRPCFn(jw.rpcFns, name, fn)
+	jw.mu.Unlock()
+}
+
+// HandleRPC registers fn as the RPC function name for this Request only,
+// taking precedence over one registered with [Jaws.HandleRPC].
+//
+// A nil fn removes the registration.
+func (rq *Request) HandleRPC(name string, fn RPCFn) {
+	rq.mu.Lock()
+	rq.rpcFns = setRPCFn(rq.rpcFns, name, fn)
+	rq.mu.Unlock()
+}
+
+func setRPCFn(fns map[string]RPCFn, name string, fn RPCFn) map[string]RPCFn {
+	if fn == nil {
+		delete(fns, name)
+	} else {
+		if fns == nil {
+			fns = make(map[string]RPCFn)
+		}
+		fns[name] = fn
+	}
+	return fns
+}
+
+func (rq *Request) getRPCFn(name string) (fn RPCFn) {
+	rq.mu.RLock()
+	fn = rq.rpcFns[name]
+	rq.mu.RUnlock()
+	if fn == nil {
+		rq.Jaws.mu.RLock()
+		fn = rq.Jaws.rpcFns[name]
+		rq.Jaws.mu.RUnlock()
+	}
+	return
+}
+
+type rpcReply struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// callRPC runs the RPC call encoded in data, "<id> <name> <json args>", and
+// returns the [what.Reply] message settling it along with the error it reports,
+// if any. ok is false if data is malformed, leaving no call id to reply to.
+func (rq *Request) callRPC(data string) (msg wire.WsMsg, ok bool, err error) {
+	idStr, rest, _ := strings.Cut(data, " ")
+	name, args, _ := strings.Cut(rest, " ")
+	var reply rpcReply
+	if reply.ID, err = strconv.ParseUint(idStr, 10, 64); err == nil {
+		ok = true
+		if args == "" {
+			args = "null"
+		}
+		err = fmt.Errorf("%w: %q", ErrRPCNotFound, name)
+		if fn := rq.getRPCFn(name); fn != nil {
+			var result any
+			ctx, cancel := context.WithTimeout(rq.Context(), DefaultRPCTimeout)
+			if result, err = callRPCFn(ctx, rq, fn, json.RawMessage(args)); err == nil {
+				reply.Result, err = json.Marshal(result)
+			}
+			cancel()
+		}
+		if err != nil {
+			reply.Result = nil
+			reply.Error = err.Error()
+		}
+		var b []byte
+		b, _ = json.Marshal(reply) // rpcReply fields always marshal
+		msg = wire.WsMsg{What: what.Reply, Data: string(b)}
+	}
+	return
+}
+
+// callRPCFn calls fn, returning a panic in it as an error matching
+// [ErrEventHandlerPanic], as [CallEventHandlers] does for event handlers.
+func callRPCFn(ctx context.Context, rq *Request, fn RPCFn, args json.RawMessage) (result any, err error) {
+	defer func() {
+		if x := recover(); x != nil {
+			err = errEventHandlerPanic{Type: reflect.TypeOf(fn), Value: x}
+		}
+	}()
+	return fn(ctx, rq, args)
+}
+
+// replyRPC runs an RPC call for the eventCaller and sends the reply.
+//
+// Unlike an event error alert, the reply is not dropped when outboundMsgCh is
+// full, as the browser Promise would otherwise wait for its timeout; the send
+// is abandoned only once the Request context is done.
+func (rq *Request) replyRPC(call eventFnCall, outboundMsgCh chan<- wire.WsMsg) {
+	start := time.Now()
+	msg, ok, err := rq.callRPC(call.data)
+	rq.Jaws.metrics.observeEvent(call.wht, time.Since(start))
+	rq.notifyEvent(call, err)
+	if ok {
+		select {
+		case outboundMsgCh <- msg:
+			rq.Jaws.metrics.countSent(msg.What)
+		case <-rq.Context().Done():
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package jaws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/lib/what"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+func TestRPC_CallsAndReplies(t *testing.T) {
+	th := newTestHelper(t)
+	rq := newTestRequest(t)
+	defer rq.Close()
+
+	rq.Jaws.HandleRPC("sum", func(ctx context.Context, rq *Request, args json.RawMessage) (any, error) {
+		var nums []int
+		if err := json.Unmarshal(args, &nums); err != nil {
+			return nil, err
+		}
+		if _, ok := ctx.Deadline(); !ok {
+			return nil, errors.New("no deadline")
+		}
+		total := 0
+		for _, n := range nums {
+			total += n
+		}
+		return total, nil
+	})
+	rq.Jaws.HandleRPC("who", func(context.Context, *Request, json.RawMessage) (any, error) {
+		return "jaws", nil
+	})
+	rq.HandleRPC("who", func(_ context.Context, _ *Request, args json.RawMessage) (any, error) {
+		return "request " + string(args), nil
+	})
+	rq.Jaws.HandleRPC("gone", func(context.Context, *Request, json.RawMessage) (any, error) {
+		return nil, nil
+	})
+	rq.Jaws.HandleRPC("gone", nil)
+	rq.Jaws.HandleRPC("boom", func(context.Context, *Request, json.RawMessage) (any, error) {
+		panic("boom")
+	})
+
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"1 sum [1,2,3]", `{"id":1,"result":6}`},
+		{"2 sum {}", `{"id":2,"error":"json: cannot unmarshal object into Go value of type []int"}`},
+		{"3 who", `{"id":3,"result":"request null"}`},
+		{"4 gone null", `{"id":4,"error":"rpc function not found: \"gone\""}`},
+	}
+	for _, tt := range tests {
+		rq.InCh <- wire.WsMsg{Data: tt.data, What: what.RPC}
+		select {
+		case <-th.C:
+			th.Timeout()
+		case msg := <-rq.OutCh:
+			if msg.What != what.Reply || msg.Jid != 0 || msg.Data != tt.want {
+				t.Errorf("%q: got %v %q", tt.data, msg.What, msg.Data)
+			}
+		}
+	}
+
+	// A panicking RPC function is reported as the call's error.
+	rq.InCh <- wire.WsMsg{Data: "6 boom", What: what.RPC}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case msg := <-rq.OutCh:
+		var reply rpcReply
+		if err := json.Unmarshal([]byte(msg.Data), &reply); err != nil || reply.ID != 6 || !strings.HasSuffix(reply.Error, " panic: boom") {
+			t.Errorf("got %q", msg.Data)
+		}
+	}
+
+	rq.HandleRPC("who", nil)
+	rq.InCh <- wire.WsMsg{Data: "bad who", What: what.RPC}
+	rq.InCh <- wire.WsMsg{Data: "5 who", What: what.RPC}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case msg := <-rq.OutCh:
+		if msg.Data != `{"id":5,"result":"jaws"}` {
+			t.Errorf("got %q", msg.Data)
+		}
+	}
+}
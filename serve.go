@@ -184,6 +184,7 @@ func (jw *Jaws) unsubscribe(msgCh chan wire.Message) {
 }
 
 func (jw *Jaws) maintenance(requestTimeout time.Duration) {
+	var retired []*Request
 	jw.mu.Lock()
 	nowSeconds := jw.runtimeSeconds.Load()
 	for _, rq := range jw.requests {
@@ -192,14 +193,22 @@ func (jw *Jaws) maintenance(requestTimeout time.Duration) {
 		}
 		if expired, cause := rq.maintenance(nowSeconds, requestTimeout); expired {
 			_ = jw.Log(cause)
-			jw.retireNonRunningRequestLocked(rq)
+			if jw.retireNonRunningRequestLocked(rq) {
+				retired = append(retired, rq)
+			}
 		}
 	}
+	var expired []*Session
 	for k, sess := range jw.sessions {
 		if sess.isDead() {
 			delete(jw.sessions, k)
+			expired = append(expired, sess)
 		}
 	}
+	expireStore := jw.sessionStoreExpiryDueLocked()
 	jw.updateStatusLocked()
 	jw.mu.Unlock()
+	jw.maintainSessionStore(expired, expireStore)
+	jw.notifyRequests(RequestRecycled, retired)
+	jw.notifySessions(SessionExpired, expired)
 }
@@ -21,22 +21,29 @@ import (
 // safe to call on a nil *Session; methods with results return the result type's
 // zero value, and the others do nothing.
 type Session struct {
-	jw        *Jaws
-	sessionID key.Key
-	remoteIP  netip.Addr
-	mu        deadlock.RWMutex // protects following
-	requests  []*Request
-	deadline  time.Time
-	cookie    http.Cookie
-	data      map[string]any
-}
+	jw            *Jaws
+	sessionID     key.Key
+	remoteIP      netip.Addr
+	storeMu       deadlock.Mutex   // serializes SessionStore I/O for this Session; taken before mu, never under a core lock
+	savedDeadline time.Time        // Deadline of the last saved SessionRecord; protected by storeMu
+	mu            deadlock.RWMutex // protects following
+	requests      []*Request
+	deadline      time.Time
+	cookie        http.Cookie
+	data          map[string]any
+	user          string // application user key; see Session.SetUser
+}
+
+// sessionGracePeriod is how long a Session without Requests stays alive, both
+// after creation and after a claimed Request's WebSocket ends.
+const sessionGracePeriod = time.Minute
 
 func newSession(jw *Jaws, sessionID key.Key, remoteIP netip.Addr, secure bool) *Session {
 	return &Session{
 		jw:        jw,
 		sessionID: sessionID,
 		remoteIP:  remoteIP,
-		deadline:  time.Now().Add(time.Minute),
+		deadline:  time.Now().Add(sessionGracePeriod),
 		cookie: http.Cookie{ // #nosec G124 -- Secure is set from the request scheme, and HttpOnly/SameSite are set below.
 			Name:     jw.CookieName,
 			Path:     "/",
@@ -90,7 +97,7 @@ func (sess *Session) delRequest(rq *Request, wasClaimed bool) {
 		// must fire even when other requests remain attached, otherwise an aged
 		// session whose last departing request is an unclaimed bootstrap render
 		// would be reaped with its stale deadline despite recent live activity.
-		sess.deadline = time.Now().Add(time.Minute)
+		sess.deadline = time.Now().Add(sessionGracePeriod)
 	}
 	// For an unclaimed request (its bootstrap render finished before the
 	// WebSocket connected) leave the existing deadline intact: the creation-time
@@ -118,7 +125,8 @@ func (sess *Session) Get(key string) (value any) {
 
 // Set associates value with key.
 //
-// A nil value removes key.
+// A nil value removes key. With a [Jaws.SessionStore], the changed Session is
+// saved before Set returns; a store error is reported through [Jaws.Log].
 func (sess *Session) Set(key string, value any) {
 	if sess != nil {
 		sess.mu.Lock()
@@ -130,6 +138,7 @@ func (sess *Session) Set(key string, value any) {
 			}
 		}
 		sess.mu.Unlock()
+		sess.save()
 	}
 }
 
@@ -203,6 +212,7 @@ func (sess *Session) addCookie(w http.ResponseWriter, r *http.Request) {
 // Close invalidates and expires the [Session].
 //
 // Future [Request] values won't be able to associate with it, and [Session.Cookie] will return a deletion cookie.
+// With a [Jaws.SessionStore], its record is deleted from the store.
 //
 // Existing [Request] values already associated with the [Session] will ask the
 // browser to reload the pages. This holds even for a [Request] whose WebSocket
@@ -216,7 +226,7 @@ func (sess *Session) addCookie(w http.ResponseWriter, r *http.Request) {
 // Close returns a non-nil deletion cookie for a non-nil [Session].
 func (sess *Session) Close() (cookie *http.Cookie) {
 	if sess != nil {
-		sess.jw.deleteSessionIfCurrent(sess)
+		deleted := sess.jw.deleteSessionIfCurrent(sess)
 
 		sess.mu.Lock()
 		sess.cookie.MaxAge = -1 // #nosec G124 -- marks the already initialized session cookie for deletion.
@@ -225,6 +235,7 @@ func (sess *Session) Close() (cookie *http.Cookie) {
 		cookie = new(http.Cookie)
 		*cookie = sess.cookie
 		sess.mu.Unlock()
+		sess.forget()
 
 		// deadSession queues the reload directly onto each Request, covering those
 		// whose WebSocket has not subscribed yet. This key-targeted Update is only a
@@ -238,6 +249,9 @@ func (sess *Session) Close() (cookie *http.Cookie) {
 				sess.jw.Broadcast(msg)
 			}
 		}
+		if deleted {
+			sess.jw.notifyLifecycle(LifecycleEvent{Kind: SessionClosed, Session: sess})
+		}
 	}
 	return
 }
@@ -249,11 +263,14 @@ func (sess *Session) Reload() {
 }
 
 // Clear removes all key/value pairs from the session.
+//
+// With a [Jaws.SessionStore], the emptied Session is saved like [Session.Set].
 func (sess *Session) Clear() {
 	if sess != nil {
 		sess.mu.Lock()
 		clear(sess.data)
 		sess.mu.Unlock()
+		sess.save()
 	}
 }
 
@@ -362,9 +379,13 @@ func getCookieSessionsIDs(h http.Header, wanted string) (cookies []key.Key) {
 // proxy that connects over loopback, every request appears to come from loopback
 // and IP binding is effectively disabled unless [Jaws.TrustForwardedHeaders] is
 // enabled so the forwarded client IP is used instead.
+//
+// With a [Jaws.SessionStore], a cookie naming a Session that is not in memory is
+// first looked up in the store; see [SessionStore].
 func (jw *Jaws) GetSession(r *http.Request) (sess *Session) {
 	if r != nil {
 		if sessionIDs := getCookieSessionsIDs(r.Header, jw.CookieName); len(sessionIDs) > 0 {
+			jw.restoreSessions(sessionIDs)
 			remoteIP := jw.clientIP(r)
 			jw.mu.RLock()
 			sess = jw.getSessionLocked(sessionIDs, remoteIP)
@@ -401,6 +422,7 @@ func (jw *Jaws) GetSession(r *http.Request) (sess *Session) {
 func (jw *Jaws) NewSession(w http.ResponseWriter, r *http.Request) (sess *Session) {
 	if r != nil {
 		if sessionIDs := getCookieSessionsIDs(r.Header, jw.CookieName); len(sessionIDs) > 0 {
+			jw.restoreSessions(sessionIDs)
 			remoteIP := jw.clientIP(r)
 			for _, sessionID := range sessionIDs {
 				jw.mu.RLock()
@@ -430,6 +452,8 @@ func (jw *Jaws) newSession(w http.ResponseWriter, r *http.Request) (sess *Sessio
 	}()
 	if sess != nil {
 		sess.addCookie(w, r)
+		sess.save()
+		jw.notifyLifecycle(LifecycleEvent{Kind: SessionCreated, Session: sess})
 	}
 	return
 }
@@ -462,26 +486,41 @@ func (jw *Jaws) newSessionLocked(remoteIP netip.Addr, secure bool) (sess *Sessio
 
 // closeSessionsLocked invalidates and releases every registered Session.
 // The caller must hold jw.mu after detaching all current Requests.
-func (jw *Jaws) closeSessionsLocked() {
+//
+// With a SessionStore it returns the unexpired records of the Sessions, and
+// the other Sessions that had already expired in memory, for the caller to save
+// and delete after releasing jw.mu.
+func (jw *Jaws) closeSessionsLocked() (records []sessionSnapshot, expired []*Session) {
+	store := jw.SessionStore
+	now := time.Now()
 	for _, sess := range jw.sessions {
 		sess.mu.Lock()
+		if store != nil {
+			if rec, _ := sess.recordLocked(now); rec != nil && !rec.Expired(now) {
+				records = append(records, sessionSnapshot{sess: sess, rec: rec})
+			} else if sess.isDeadLocked() {
+				expired = append(expired, sess)
+			}
+		}
 		sess.cookie.MaxAge = -1 // #nosec G124 -- marks the already initialized session cookie for deletion.
 		sess.requests = nil
 		sess.data = nil
 		sess.mu.Unlock()
 	}
 	jw.sessions = nil
+	return
 }
 
-// deleteSessionIfCurrent unregisters sess only while it still owns its ID.
-// Session pointers can outlive registration, so a stale Close must not remove a
-// later Session that received the same numeric ID.
-func (jw *Jaws) deleteSessionIfCurrent(sess *Session) {
+// deleteSessionIfCurrent unregisters sess only while it still owns its ID, and
+// reports whether it did. Session pointers can outlive registration, so a stale
+// Close must not remove a later Session that received the same numeric ID.
+func (jw *Jaws) deleteSessionIfCurrent(sess *Session) (deleted bool) {
 	jw.mu.Lock()
-	if jw.sessions[sess.sessionID] == sess {
+	if deleted = jw.sessions[sess.sessionID] == sess; deleted {
 		delete(jw.sessions, sess.sessionID)
 	}
 	jw.mu.Unlock()
+	return
 }
 
 type sessioner struct {
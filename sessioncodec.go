@@ -0,0 +1,128 @@
+package jaws
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"reflect"
+	"time"
+
+	"github.com/linkdata/jaws/lib/key"
+)
+
+// SessionCodec encodes and decodes [SessionRecord] values for a [SessionStore].
+type SessionCodec interface {
+	EncodeSession(w io.Writer, rec *SessionRecord) (err error)
+	DecodeSession(r io.Reader) (rec *SessionRecord, err error)
+}
+
+// GobSessionCodec is a [SessionCodec] using [encoding/gob].
+//
+// Concrete types stored in [Session] data must be registered with [gob.Register]
+// before they are encoded or decoded.
+type GobSessionCodec struct{}
+
+var _ SessionCodec = GobSessionCodec{}
+
+// EncodeSession implements [SessionCodec].
+func (GobSessionCodec) EncodeSession(w io.Writer, rec *SessionRecord) error {
+	return gob.NewEncoder(w).Encode(rec)
+}
+
+// DecodeSession implements [SessionCodec].
+func (GobSessionCodec) DecodeSession(r io.Reader) (rec *SessionRecord, err error) {
+	rec = &SessionRecord{}
+	if err = gob.NewDecoder(r).Decode(rec); err != nil {
+		rec = nil
+	}
+	return
+}
+
+// JSONSessionCodec is a [SessionCodec] using [encoding/json].
+//
+// JSON loses the Go type of interface values, so each [Session] data value is
+// stored together with the name its type was registered under, and decoded back
+// into that type. Use [NewJSONSessionCodec] to create one.
+type JSONSessionCodec struct {
+	types typeRegistry
+}
+
+var _ SessionCodec = (*JSONSessionCodec)(nil)
+
+// NewJSONSessionCodec returns a [JSONSessionCodec] with the builtin
+// bool, string, numeric, []string and [time.Time] types registered under their
+// Go type names.
+func NewJSONSessionCodec() *JSONSessionCodec {
+	c := &JSONSessionCodec{}
+	for _, v := range []any{
+		false, "", []string(nil), time.Time{},
+		int(0), int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+		float32(0), float64(0),
+	} {
+		_ = c.Register(reflect.TypeOf(v).String(), v)
+	}
+	return c
+}
+
+// Register makes the type of value encodable under name.
+//
+// Registering the same name and type again is a no-op; reusing a name or type
+// with a different counterpart is an error.
+func (c *JSONSessionCodec) Register(name string, value any) (err error) {
+	if err = c.types.register(name, value); err != nil {
+		err = fmt.Errorf("jaws: JSONSessionCodec.Register: %w", err)
+	}
+	return
+}
+
+type jsonSessionRecord struct {
+	ID       key.Key               `json:"id"`
+	RemoteIP netip.Addr            `json:"ip"`
+	Secure   bool                  `json:"secure,omitempty"`
+	Deadline time.Time             `json:"deadline"`
+	Data     map[string]typedValue `json:"data,omitempty"`
+	User     string                `json:"user,omitempty"`
+}
+
+// EncodeSession implements [SessionCodec].
+func (c *JSONSessionCodec) EncodeSession(w io.Writer, rec *SessionRecord) (err error) {
+	jrec := jsonSessionRecord{
+		ID:       rec.ID,
+		RemoteIP: rec.RemoteIP,
+		Secure:   rec.Secure,
+		Deadline: rec.Deadline,
+		Data:     make(map[string]typedValue, len(rec.Data)),
+		User:     rec.User,
+	}
+	for k, v := range rec.Data {
+		if jrec.Data[k], err = c.types.marshal(v, ErrSessionValueNotRegistered); err != nil {
+			return fmt.Errorf("jaws: session value %q: %w", k, err)
+		}
+	}
+	return json.NewEncoder(w).Encode(&jrec)
+}
+
+// DecodeSession implements [SessionCodec].
+func (c *JSONSessionCodec) DecodeSession(r io.Reader) (rec *SessionRecord, err error) {
+	var jrec jsonSessionRecord
+	if err = json.NewDecoder(r).Decode(&jrec); err == nil {
+		data := make(map[string]any, len(jrec.Data))
+		for k, tv := range jrec.Data {
+			if data[k], err = c.types.unmarshal(tv, ErrSessionValueNotRegistered); err != nil {
+				return nil, fmt.Errorf("jaws: session value %q: %w", k, err)
+			}
+		}
+		rec = &SessionRecord{
+			ID:       jrec.ID,
+			RemoteIP: jrec.RemoteIP,
+			Secure:   jrec.Secure,
+			Deadline: jrec.Deadline,
+			Data:     data,
+			User:     jrec.User,
+		}
+	}
+	return
+}
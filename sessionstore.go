@@ -0,0 +1,299 @@
+package jaws
+
+import (
+	"maps"
+	"net/netip"
+	"time"
+
+	"github.com/linkdata/jaws/lib/key"
+)
+
+// SessionRecord is the persisted form of a [Session].
+//
+// Data holds the Session key/value pairs. A [SessionCodec] must be able to
+// encode every value stored with [Session.Set] for the Session to survive a
+// restart.
+type SessionRecord struct {
+	ID       key.Key        // session ID, as sent in the session cookie
+	RemoteIP netip.Addr     // remote IP the Session is bound to
+	Secure   bool           // whether the session cookie has the Secure flag
+	Deadline time.Time      // the record is expired after this time
+	Data     map[string]any // key/value pairs set with Session.Set
+	User     string         // user key set with Session.SetUser
+}
+
+// Expired reports whether the record's deadline has passed at now.
+func (rec *SessionRecord) Expired(now time.Time) bool {
+	return rec == nil || now.After(rec.Deadline)
+}
+
+// SessionStore persists [Session] state so it survives a restart of the
+// [Jaws] instance, or can be shared by several instances behind a load balancer
+// that agree on [Jaws.CookieName].
+//
+// When [Jaws.SessionStore] is set:
+//   - a session cookie naming a Session that is not in memory is looked up
+//     with LoadSession, and an unexpired record is restored as a live Session
+//     bound to the record's RemoteIP. An ID with no such record is not looked
+//     up again for a minute;
+//   - a record's Deadline is [Jaws.SessionStoreTTL] after the Session's
+//     in-memory deadline, so a Session can be restored well after it expired
+//     in memory, such as after a restart;
+//   - a Session is saved when it is created, after [Session.Set] and
+//     [Session.Clear], periodically by the maintenance pass while it has
+//     Requests attached, when it expires in memory, and by [Jaws.Close];
+//   - a Session is deleted by [Session.Close], and when it expires in memory
+//     if its record has expired too;
+//   - ExpireSessions is called from the maintenance pass about once a minute
+//     to discard records no instance has deleted.
+//
+// JaWS never calls the methods while holding its own locks, but may call them
+// concurrently for different sessions. Errors are reported through [Jaws.Log].
+type SessionStore interface {
+	// LoadSession returns the record for id, or nil and a nil error if there is none.
+	LoadSession(id key.Key) (rec *SessionRecord, err error)
+	// SaveSession creates or replaces the record for rec.ID.
+	SaveSession(rec *SessionRecord) (err error)
+	// DeleteSession removes the record for id. Deleting a missing record is not an error.
+	DeleteSession(id key.Key) (err error)
+	// ExpireSessions removes all records whose deadline is before now.
+	ExpireSessions(now time.Time) (err error)
+}
+
+// sessionSnapshot pairs a Session with a record captured under its lock.
+type sessionSnapshot struct {
+	sess *Session
+	rec  *SessionRecord
+}
+
+// recordLocked returns a record of sess as of now, or nil if sess is closed.
+// The record's deadline is the SessionStoreTTL after the in-memory deadline. A
+// Session with Requests attached does not expire in memory, so that deadline is
+// taken to be at least one grace period ahead of now.
+//
+// The caller must hold sess.mu.
+func (sess *Session) recordLocked(now time.Time) (rec *SessionRecord, attached bool) {
+	if sess.cookie.MaxAge >= 0 && sess.data != nil {
+		attached = len(sess.requests) > 0
+		deadline := sess.deadline
+		if minDeadline := now.Add(sessionGracePeriod); attached && minDeadline.After(deadline) {
+			deadline = minDeadline
+		}
+		rec = &SessionRecord{
+			ID:       sess.sessionID,
+			RemoteIP: sess.remoteIP,
+			Secure:   sess.cookie.Secure,
+			Deadline: deadline.Add(sess.jw.sessionStoreTTL()),
+			Data:     maps.Clone(sess.data),
+			User:     sess.user,
+		}
+	}
+	return
+}
+
+// persist saves sess to store. If onlyStale is true, the save is skipped
+// unless the deadline moved and, for a Session with Requests attached, the
+// saved deadline is about to lapse.
+func (sess *Session) persist(store SessionStore, now time.Time, onlyStale bool) {
+	sess.storeMu.Lock()
+	defer sess.storeMu.Unlock()
+	sess.mu.RLock()
+	rec, attached := sess.recordLocked(now)
+	sess.mu.RUnlock()
+	if rec != nil {
+		if onlyStale {
+			if rec.Deadline.Equal(sess.savedDeadline) {
+				return
+			}
+			if attached && sess.savedDeadline.Sub(now) >= sess.jw.sessionStoreTTL()+sessionGracePeriod/2 {
+				return
+			}
+		}
+		sess.storeRecordLocked(store, rec)
+	}
+}
+
+// storeRecordLocked saves rec and remembers its deadline.
+//
+// The caller must hold sess.storeMu.
+func (sess *Session) storeRecordLocked(store SessionStore, rec *SessionRecord) {
+	if sess.jw.Log(store.SaveSession(rec)) == nil {
+		sess.savedDeadline = rec.Deadline
+	}
+}
+
+// save writes sess to the Jaws SessionStore, if any.
+func (sess *Session) save() {
+	if store := sess.jw.SessionStore; store != nil {
+		sess.persist(store, time.Now(), false)
+	}
+}
+
+// expire saves the record of sess after it expired in memory, or deletes it if
+// the record has expired too.
+func (sess *Session) expire(store SessionStore, now time.Time) {
+	sess.mu.RLock()
+	rec, _ := sess.recordLocked(now)
+	sess.mu.RUnlock()
+	if rec != nil && !rec.Expired(now) {
+		sess.persist(store, now, true)
+	} else {
+		sess.forget()
+	}
+}
+
+// forget deletes the record of sess from the Jaws SessionStore, if any.
+func (sess *Session) forget() {
+	if store := sess.jw.SessionStore; store != nil {
+		sess.storeMu.Lock()
+		if sess.jw.Log(store.DeleteSession(sess.sessionID)) == nil {
+			sess.savedDeadline = time.Time{}
+		}
+		sess.storeMu.Unlock()
+	}
+}
+
+// sessionMissesMax bounds the number of session IDs remembered as missing from
+// the SessionStore.
+const sessionMissesMax = 4096
+
+// sessionStoreTTL returns the SessionStoreTTL, or zero if it is not positive.
+func (jw *Jaws) sessionStoreTTL() time.Duration {
+	return max(jw.SessionStoreTTL, 0)
+}
+
+// restoreSessions loads the records for any of sessionIDs not in memory from the
+// SessionStore and registers them as live Sessions. Expired records are deleted.
+// IDs with no usable record are remembered for a grace period, so stale or
+// forged cookies do not cause a store lookup on every request.
+func (jw *Jaws) restoreSessions(sessionIDs []key.Key) {
+	store := jw.SessionStore
+	if store == nil || len(sessionIDs) == 0 {
+		return
+	}
+	var missing []key.Key
+	now := time.Now()
+	jw.mu.RLock()
+	for _, sessionID := range sessionIDs {
+		if _, ok := jw.sessions[sessionID]; !ok && sessionID != 0 {
+			if when, ok := jw.sessionMisses[sessionID]; !ok || now.Sub(when) >= sessionGracePeriod {
+				missing = append(missing, sessionID)
+			}
+		}
+	}
+	jw.mu.RUnlock()
+	for _, sessionID := range missing {
+		rec, err := store.LoadSession(sessionID)
+		if jw.Log(err) != nil {
+			continue
+		}
+		now := time.Now()
+		if rec == nil || rec.ID != sessionID || rec.Expired(now) {
+			if rec != nil && rec.ID == sessionID {
+				_ = jw.Log(store.DeleteSession(sessionID))
+			}
+			jw.sessionMissed(sessionID, now)
+			continue
+		}
+		sess := newSession(jw, sessionID, rec.RemoteIP, rec.Secure)
+		if rec.Deadline.Before(sess.deadline) {
+			sess.deadline = rec.Deadline
+		}
+		sess.savedDeadline = rec.Deadline
+		sess.user = rec.User
+		if rec.Data != nil {
+			sess.data = rec.Data
+		}
+		restored := false
+		jw.mu.Lock()
+		select {
+		case <-jw.closeCh:
+		default:
+			if _, ok := jw.sessions[sessionID]; !ok {
+				jw.sessions[sessionID] = sess
+				restored = true
+			}
+		}
+		jw.mu.Unlock()
+		if restored {
+			jw.notifyLifecycle(LifecycleEvent{Kind: SessionCreated, Session: sess})
+		}
+	}
+}
+
+// sessionMissed remembers that sessionID has no usable record as of now. If
+// too many IDs are remembered, those older than a grace period are forgotten,
+// and if that is not enough, all of them.
+func (jw *Jaws) sessionMissed(sessionID key.Key, now time.Time) {
+	jw.mu.Lock()
+	if len(jw.sessionMisses) >= sessionMissesMax {
+		jw.pruneSessionMissesLocked(now)
+		if len(jw.sessionMisses) >= sessionMissesMax {
+			clear(jw.sessionMisses)
+		}
+	}
+	if jw.sessionMisses == nil {
+		jw.sessionMisses = make(map[key.Key]time.Time)
+	}
+	jw.sessionMisses[sessionID] = now
+	jw.mu.Unlock()
+}
+
+// pruneSessionMissesLocked forgets the missing session IDs remembered for a
+// grace period or longer.
+//
+// The caller must hold jw.mu.
+func (jw *Jaws) pruneSessionMissesLocked(now time.Time) {
+	maps.DeleteFunc(jw.sessionMisses, func(_ key.Key, when time.Time) bool {
+		return now.Sub(when) >= sessionGracePeriod
+	})
+}
+
+// sessionStoreExpiryDueLocked reports whether the maintenance pass should call
+// SessionStore.ExpireSessions, and if so records the time and forgets stale
+// missing session IDs.
+//
+// The caller must hold jw.mu.
+func (jw *Jaws) sessionStoreExpiryDueLocked() (due bool) {
+	if jw.SessionStore != nil {
+		now := time.Now()
+		if due = now.Sub(jw.sessionStoreExpiry) >= sessionGracePeriod; due {
+			jw.sessionStoreExpiry = now
+			jw.pruneSessionMissesLocked(now)
+		}
+	}
+	return
+}
+
+// maintainSessionStore saves or deletes the records of the Sessions that
+// expired in memory, refreshes the records of live Sessions whose deadline
+// moved, and optionally asks the store to discard expired records.
+func (jw *Jaws) maintainSessionStore(expired []*Session, expireStore bool) {
+	if store := jw.SessionStore; store != nil {
+		now := time.Now()
+		for _, sess := range expired {
+			sess.expire(store, now)
+		}
+		for _, sess := range jw.Sessions() {
+			sess.persist(store, now, true)
+		}
+		if expireStore {
+			_ = jw.Log(store.ExpireSessions(now))
+		}
+	}
+}
+
+// storeClosedSessions saves the records captured by closeSessionsLocked and
+// deletes the records of the other Sessions that had already expired.
+func (jw *Jaws) storeClosedSessions(records []sessionSnapshot, expired []*Session) {
+	if store := jw.SessionStore; store != nil {
+		for _, snap := range records {
+			snap.sess.storeMu.Lock()
+			snap.sess.storeRecordLocked(store, snap.rec)
+			snap.sess.storeMu.Unlock()
+		}
+		for _, sess := range expired {
+			sess.forget()
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package jaws
+
+import (
+	"encoding/gob"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/lib/key"
+)
+
+type testSessionValue struct {
+	Name  string
+	Count int
+}
+
+func init() {
+	gob.Register(testSessionValue{})
+}
+
+func TestFileSessionStore_RoundTrip(t *testing.T) {
+	jsonCodec := NewJSONSessionCodec()
+	if err := jsonCodec.Register("testSessionValue", testSessionValue{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, codec := range []SessionCodec{GobSessionCodec{}, jsonCodec} {
+		fss, err := NewFileSessionStore(filepath.Join(t.TempDir(), "sessions"), codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &SessionRecord{
+			ID:       key.Key(0x1234),
+			RemoteIP: netip.MustParseAddr("192.0.2.1"),
+			Secure:   true,
+			Deadline: time.Now().Add(time.Hour).Round(0),
+			Data: map[string]any{
+				"str": "bar",
+				"int": 42,
+				"val": testSessionValue{Name: "x", Count: 3},
+			},
+			User: "alice",
+		}
+		if err = fss.SaveSession(want); err != nil {
+			t.Fatalf("%T: %v", codec, err)
+		}
+		got, err := fss.LoadSession(want.ID)
+		if err != nil {
+			t.Fatalf("%T: %v", codec, err)
+		}
+		if got.ID != want.ID || got.RemoteIP != want.RemoteIP || got.Secure != want.Secure || !got.Deadline.Equal(want.Deadline) {
+			t.Errorf("%T: got %+v, want %+v", codec, got, want)
+		}
+		for k, v := range want.Data {
+			if got.Data[k] != v {
+				t.Errorf("%T: %q = %#v, want %#v", codec, k, got.Data[k], v)
+			}
+		}
+		if err = fss.DeleteSession(want.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err = fss.DeleteSession(want.ID); err != nil {
+			t.Error("deleting a missing record:", err)
+		}
+		if got, err = fss.LoadSession(want.ID); got != nil || err != nil {
+			t.Errorf("%T: after delete got %v, %v", codec, got, err)
+		}
+	}
+}
+
+func TestFileSessionStore_ExpireSessions(t *testing.T) {
+	fss, err := NewFileSessionStore(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	live := &SessionRecord{ID: 1, Deadline: now.Add(time.Minute)}
+	dead := &SessionRecord{ID: 2, Deadline: now.Add(-time.Minute)}
+	for _, rec := range []*SessionRecord{live, dead} {
+		if err = fss.SaveSession(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	garbage := filepath.Join(fss.Dir, key.Key(3).String()+fileSessionSuffix)
+	if err = os.WriteFile(garbage, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err = fss.ExpireSessions(now); err != nil {
+		t.Fatal(err)
+	}
+	if rec, _ := fss.LoadSession(live.ID); rec == nil {
+		t.Error("live record expired")
+	}
+	if rec, _ := fss.LoadSession(dead.ID); rec != nil {
+		t.Error("dead record kept")
+	}
+	if _, err = os.Stat(garbage); !errors.Is(err, os.ErrNotExist) {
+		t.Error("undecodable record kept", err)
+	}
+}
+
+func TestJSONSessionCodec_Unregistered(t *testing.T) {
+	codec := NewJSONSessionCodec()
+	rec := &SessionRecord{ID: 1, Data: map[string]any{"val": testSessionValue{}}}
+	if err := codec.EncodeSession(new(nopWriter), rec); !errors.Is(err, ErrSessionValueNotRegistered) {
+		t.Error(err)
+	}
+	if err := codec.Register("int", "not an int"); err == nil {
+		t.Error("expected name conflict")
+	}
+	if err := codec.Register("int", 0); err != nil {
+		t.Error(err)
+	}
+}
+
+type nopWriter struct{}
+
+func (nopWriter) Write(b []byte) (int, error) { return len(b), nil }
+
+func TestSessionStore_RestoreAfterClose(t *testing.T) {
+	fss, err := NewFileSessionStore(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jw1, _ := New()
+	jw1.SessionStore = fss
+	r := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	sess1 := jw1.NewSession(w, r)
+	if sess1 == nil {
+		t.Fatal("no session")
+	}
+	sess1.Set("foo", "bar")
+	jw1.Close()
+
+	jw2, _ := New()
+	defer jw2.Close()
+	jw2.SessionStore = fss
+	r2 := httptest.NewRequest("GET", "/", nil)
+	r2.AddCookie(sess1.Cookie())
+	sess2 := jw2.GetSession(r2)
+	if sess2 == nil {
+		t.Fatal("session not restored")
+	}
+	if sess2.ID() != sess1.ID() {
+		t.Errorf("restored %x, want %x", sess2.ID(), sess1.ID())
+	}
+	if x := sess2.Get("foo"); x != "bar" {
+		t.Error(x)
+	}
+
+	sess2.Close()
+	if rec, err := fss.LoadSession(key.Key(sess1.ID())); rec != nil || err != nil {
+		t.Error("closed session still stored", rec, err)
+	}
+	if sess3 := jw2.GetSession(r2); sess3 != nil {
+		t.Error("closed session restored")
+	}
+}
+
+func TestSessionStore_KeepsRecordAfterMemoryExpiry(t *testing.T) {
+	fss, err := NewFileSessionStore(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jw, _ := New()
+	defer jw.Close()
+	jw.SessionStore = fss
+	sess := jw.NewSession(nil, httptest.NewRequest("GET", "/", nil))
+	id := key.Key(sess.ID())
+	if rec, _ := fss.LoadSession(id); rec == nil || rec.Deadline.Before(time.Now().Add(DefaultSessionStoreTTL)) {
+		t.Fatalf("record %+v does not outlive the Session by the TTL", rec)
+	}
+
+	// The Session expires in memory, such as while the server is down for
+	// longer than the grace period, but its record stays restorable.
+	sess.mu.Lock()
+	sess.deadline = time.Now().Add(-2 * sessionGracePeriod)
+	sess.mu.Unlock()
+	jw.deleteSessionIfCurrent(sess)
+	jw.maintainSessionStore([]*Session{sess}, false)
+	if rec, _ := fss.LoadSession(id); rec == nil || rec.Expired(time.Now()) {
+		t.Errorf("record %+v not kept after memory expiry", rec)
+	}
+
+	// Without a TTL, the record expires with the Session.
+	jw.SessionStoreTTL = 0
+	jw.maintainSessionStore([]*Session{sess}, false)
+	if rec, _ := fss.LoadSession(id); rec != nil {
+		t.Errorf("record %+v kept without a TTL", rec)
+	}
+}
+
+// countingSessionStore counts LoadSession calls.
+type countingSessionStore struct {
+	SessionStore
+	loads int
+}
+
+func (s *countingSessionStore) LoadSession(id key.Key) (*SessionRecord, error) {
+	s.loads++
+	return s.SessionStore.LoadSession(id)
+}
+
+func TestSessionStore_RemembersMissingSessions(t *testing.T) {
+	fss, err := NewFileSessionStore(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &countingSessionStore{SessionStore: fss}
+	jw, _ := New()
+	defer jw.Close()
+	jw.SessionStore = store
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: jw.CookieName, Value: key.Key(0x1234).String()})
+	for range 3 {
+		if sess := jw.GetSession(r); sess != nil {
+			t.Fatal("unknown session restored")
+		}
+	}
+	if store.loads != 1 {
+		t.Errorf("%d loads for a missing session", store.loads)
+	}
+
+	jw.mu.Lock()
+	jw.sessionMisses[0x1234] = time.Now().Add(-sessionGracePeriod)
+	jw.mu.Unlock()
+	jw.GetSession(r)
+	if store.loads != 2 {
+		t.Errorf("%d loads after the miss went stale", store.loads)
+	}
+}
@@ -30,6 +30,8 @@ type statusTags struct {
 	sessions        statusTag
 	activeSessions  statusTag
 	errors          statusTag
+	presence        statusTag
+	clientInfo      statusTag
 }
 
 type statusSample struct {
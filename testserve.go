@@ -79,7 +79,7 @@ func (jw *Jaws) TestServe(rq *Request, onPanic func(recovered any)) (inCh chan w
 		panicValue := rq.process(bcastCh, inCh, outCh)
 		// Recycle before re-panicking: the outer defer reports the loop panic, so
 		// propagating it first would skip the Request's lifecycle cleanup.
-		jw.recycle(rq)
+		rq.stopServe()
 		if panicValue != nil {
 			panic(panicValue)
 		}
@@ -0,0 +1,159 @@
+package jaws
+
+import (
+	"encoding/json"
+	"html/template"
+	"reflect"
+	"time"
+
+	"github.com/linkdata/jaws/lib/what"
+	"github.com/linkdata/jaws/lib/wire"
+)
+
+// Toast is a notification shown by [Request.Toast] or [Jaws.Toast].
+//
+// Toasts stack in a corner of the page. The bundled client renders them with
+// the jaws-toast classes from jaws.css, or as Bootstrap toasts if Bootstrap is
+// loaded, such as by jawsboot.
+type Toast struct {
+	Level   string        // Level is a Bootstrap-style contextual level such as "info", "success", "warning" or "danger"; it defaults to "info".
+	Title   string        // Title is shown as text in the header, if not empty.
+	Body    template.HTML // Body is trusted HTML shown below the title.
+	Timeout time.Duration // Timeout dismisses the toast after the given time; if zero, it stays until dismissed.
+	// Key de-duplicates toasts: showing a toast with the Key of one still shown
+	// replaces it in place, along with its callbacks, rather than stacking
+	// another. Toasts with an empty Key are never de-duplicated.
+	Key string
+	// Action is the label of an action button, which dismisses the toast and
+	// calls OnAction. If empty, no button is shown.
+	Action string
+	// OnAction, if not nil, is called when the user clicks the action button.
+	OnAction func(rq *Request) error
+	// OnDismiss, if not nil, is called when the toast is dismissed by the user
+	// or its Timeout, but not when it is replaced or its action button is
+	// clicked.
+	OnDismiss func(rq *Request) error
+}
+
+type toastData struct {
+	ID      uint64        `json:"id"`
+	Level   string        `json:"level"`
+	Title   string        `json:"title,omitempty"`
+	Body    template.HTML `json:"body,omitempty"`
+	Timeout int64         `json:"timeout,omitempty"`
+	Action  string        `json:"action,omitempty"`
+	Key     string        `json:"key,omitempty"`
+}
+
+type toastEvent struct {
+	ID     uint64 `json:"id"`
+	Action bool   `json:"action"`
+}
+
+type toastEntry struct {
+	key       string
+	onAction  func(rq *Request) error
+	onDismiss func(rq *Request) error
+}
+
+// Toast shows a notification in the browser.
+//
+// The OnAction and OnDismiss callbacks run on the Request's event goroutine,
+// serialized with its event handlers, and errors they return are handled like
+// those of event handlers. Callbacks for toasts still shown are dropped when the
+// Request ends.
+//
+// See [Request] for pointer lifetime and [Jaws.Broadcast] for processing-loop
+// requirements.
+func (rq *Request) Toast(toast Toast) {
+	if k := rq.destKey(); k != 0 {
+		data := newToastData(toast)
+		entry := &toastEntry{key: toast.Key, onAction: toast.OnAction, onDismiss: toast.OnDismiss}
+		rq.mu.Lock()
+		if toast.Key != "" {
+			data.ID = rq.toastKeys[toast.Key]
+		}
+		if data.ID == 0 {
+			rq.toastSeq++
+			data.ID = rq.toastSeq
+		}
+		if rq.toasts == nil {
+			rq.toasts = make(map[uint64]*toastEntry)
+			rq.toastKeys = make(map[string]uint64)
+		}
+		rq.toasts[data.ID] = entry
+		if toast.Key != "" {
+			rq.toastKeys[toast.Key] = data.ID
+		}
+		rq.mu.Unlock()
+		b, _ := json.Marshal(data) // toastData fields always marshal
+		rq.Jaws.Broadcast(wire.Message{Dest: k, What: what.Toast, Data: string(b)})
+	}
+}
+
+// Toast shows a notification in the browser of all active [Request] values.
+//
+// The toasts are not tracked by the Requests, so OnAction and OnDismiss are
+// ignored and the Action button only dismisses the toast. A toast with the Key
+// of one from Jaws.Toast still shown replaces it in place.
+func (jw *Jaws) Toast(toast Toast) {
+	data := newToastData(toast)
+	data.Key = toast.Key
+	b, _ := json.Marshal(data) // toastData fields always marshal
+	jw.Broadcast(wire.Message{What: what.Toast, Data: string(b)})
+}
+
+// newToastData returns the wire form of toast, without an ID.
+func newToastData(toast Toast) (data toastData) {
+	data = toastData{
+		Level:  toast.Level,
+		Title:  toast.Title,
+		Body:   toast.Body,
+		Action: toast.Action,
+	}
+	if data.Level == "" {
+		data.Level = "info"
+	}
+	if toast.Timeout > 0 {
+		data.Timeout = max(toast.Timeout.Milliseconds(), 1)
+	}
+	return
+}
+
+// toastDismissed forgets the toast a Dismiss event reports closed and runs its
+// callback, if any. Called only from eventCaller.
+func (rq *Request) toastDismissed(data string) (err error) {
+	var ev toastEvent
+	if json.Unmarshal([]byte(data), &ev) == nil {
+		rq.mu.Lock()
+		entry := rq.toasts[ev.ID]
+		if entry != nil {
+			delete(rq.toasts, ev.ID)
+			if entry.key != "" {
+				delete(rq.toastKeys, entry.key)
+			}
+		}
+		rq.mu.Unlock()
+		if entry != nil {
+			fn := entry.onDismiss
+			if ev.Action {
+				fn = entry.onAction
+			}
+			if fn != nil {
+				err = callToastFn(rq, fn)
+			}
+		}
+	}
+	return
+}
+
+// callToastFn calls fn, returning a panic in it as an error matching
+// [ErrEventHandlerPanic], as [CallEventHandlers] does for event handlers.
+func callToastFn(rq *Request, fn func(rq *Request) error) (err error) {
+	defer func() {
+		if x := recover(); x != nil {
+			err = errEventHandlerPanic{Type: reflect.TypeOf(fn), Value: x}
+		}
+	}()
+	return fn(rq)
+}
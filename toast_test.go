@@ -0,0 +1,125 @@
+package jaws
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+func TestToast_CallbacksAndDeduplication(t *testing.T) {
+	_, _, rq, dial := newResumeTestRequest(t, 0)
+	conn, err := dial("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+	deadline := time.Now().Add(3 * time.Second)
+	for rq.loadState() != reqRunning && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	next := func(prefix string) string {
+		t.Helper()
+		for {
+			_, data, err := conn.Read(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for line := range strings.SplitSeq(string(data), "\n") {
+				if s, ok := strings.CutPrefix(line, prefix); ok {
+					if s, err = strconv.Unquote(s); err != nil {
+						t.Fatal(err)
+					}
+					return s
+				}
+			}
+		}
+	}
+	nextToast := func() (got toastData) {
+		t.Helper()
+		if err := json.Unmarshal([]byte(next("Toast\t\t")), &got); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	dismiss := func(id uint64, action bool) {
+		t.Helper()
+		b, _ := json.Marshal(toastEvent{ID: id, Action: action})
+		if err := conn.Write(t.Context(), websocket.MessageText, []byte("Dismiss\t\t"+strconv.Quote(string(b))+"\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	calls := make(chan string, 4)
+	rq.Toast(Toast{
+		Title:     "Saved",
+		Body:      "<b>ok</b>",
+		Timeout:   5 * time.Second,
+		Key:       "save",
+		OnDismiss: func(*Request) error { calls <- "dismiss first"; return nil },
+	})
+	first := nextToast()
+	if first.ID == 0 || first.Level != "info" || first.Title != "Saved" || first.Body != "<b>ok</b>" || first.Timeout != 5000 {
+		t.Errorf("first toast %+v", first)
+	}
+	rq.Toast(Toast{
+		Level:     "success",
+		Body:      "saved again",
+		Key:       "save",
+		Action:    "Undo",
+		OnAction:  func(*Request) error { calls <- "undo"; return nil },
+		OnDismiss: func(*Request) error { calls <- "dismiss second"; return nil },
+	})
+	second := nextToast()
+	if second.ID != first.ID || second.Level != "success" || second.Action != "Undo" || second.Timeout != 0 {
+		t.Errorf("second toast %+v, want id %d", second, first.ID)
+	}
+	dismiss(second.ID, true)
+	if got := <-calls; got != "undo" {
+		t.Errorf("action called %q", got)
+	}
+
+	// Once dismissed, the key no longer de-duplicates and the id is forgotten.
+	rq.Toast(Toast{Key: "save", OnDismiss: func(*Request) error { return errors.New("boom") }})
+	third := nextToast()
+	if third.ID == first.ID {
+		t.Errorf("dismissed toast id %d reused", third.ID)
+	}
+	dismiss(first.ID, false)
+	dismiss(third.ID, false)
+	if got := next("Alert\t\t"); got != "danger\nboom" {
+		t.Errorf("callback error alert %q", got)
+	}
+
+	// A panicking callback is reported like a failing one.
+	rq.Toast(Toast{OnDismiss: func(*Request) error { panic("bang") }})
+	dismiss(nextToast().ID, false)
+	if got := next("Alert\t\t"); !strings.HasPrefix(got, "danger\n") || !strings.HasSuffix(got, " panic: bang") {
+		t.Errorf("callback panic alert %q", got)
+	}
+
+	// Jaws.Toast reaches the Request without being tracked by it.
+	rq.Jaws.Toast(Toast{Key: "deploy", Body: "Restarting", Action: "OK", OnAction: func(*Request) error {
+		calls <- "broadcast"
+		return nil
+	}})
+	if got := nextToast(); got.ID != 0 || got.Key != "deploy" || got.Level != "info" || got.Body != "Restarting" {
+		t.Errorf("broadcast toast %+v", got)
+	}
+	rq.mu.RLock()
+	tracked := len(rq.toasts)
+	rq.mu.RUnlock()
+	if tracked != 0 {
+		t.Errorf("%d toasts tracked", tracked)
+	}
+	select {
+	case got := <-calls:
+		t.Errorf("unexpected call %q", got)
+	default:
+	}
+}
@@ -0,0 +1,90 @@
+package jaws
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// typedValue is the JSON form of a value whose concrete Go type is named by a
+// typeRegistry.
+type typedValue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// typeRegistry maps names to concrete Go types for JSON encodings that would
+// otherwise lose the type of interface values. The zero value is ready for use.
+type typeRegistry struct {
+	mu     sync.RWMutex // leaf lock
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}
+
+// register makes the type of value known as name. Registering the same name
+// and type again is a no-op; reusing a name or type with a different
+// counterpart is an error.
+func (tr *typeRegistry) register(name string, value any) (err error) {
+	t := reflect.TypeOf(value)
+	if t == nil || name == "" {
+		return fmt.Errorf("invalid registration of %T as %q", value, name)
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	oldType, nameUsed := tr.byName[name]
+	oldName, typeUsed := tr.byType[t]
+	switch {
+	case nameUsed && oldType == t:
+	case nameUsed:
+		err = fmt.Errorf("name %q already registered for %v", name, oldType)
+	case typeUsed:
+		err = fmt.Errorf("type %v already registered as %q", t, oldName)
+	default:
+		if tr.byName == nil {
+			tr.byName = make(map[string]reflect.Type)
+			tr.byType = make(map[reflect.Type]string)
+		}
+		tr.byName[name] = t
+		tr.byType[t] = name
+	}
+	return
+}
+
+// known reports whether the type of v is registered.
+func (tr *typeRegistry) known(v any) (ok bool) {
+	tr.mu.RLock()
+	_, ok = tr.byType[reflect.TypeOf(v)]
+	tr.mu.RUnlock()
+	return
+}
+
+// marshal returns v tagged with its registered type name, or an error wrapping
+// notRegistered.
+func (tr *typeRegistry) marshal(v any, notRegistered error) (tv typedValue, err error) {
+	tr.mu.RLock()
+	name, ok := tr.byType[reflect.TypeOf(v)]
+	tr.mu.RUnlock()
+	if !ok {
+		return tv, fmt.Errorf("%w: %T", notRegistered, v)
+	}
+	tv.Type = name
+	tv.Value, err = json.Marshal(v)
+	return
+}
+
+// unmarshal decodes tv into a value of its registered type, or returns an
+// error wrapping notRegistered.
+func (tr *typeRegistry) unmarshal(tv typedValue, notRegistered error) (v any, err error) {
+	tr.mu.RLock()
+	t, ok := tr.byName[tv.Type]
+	tr.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", notRegistered, tv.Type)
+	}
+	pv := reflect.New(t)
+	if err = json.Unmarshal(tv.Value, pv.Interface()); err == nil {
+		v = pv.Elem().Interface()
+	}
+	return
+}
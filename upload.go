@@ -0,0 +1,117 @@
+package jaws
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/linkdata/jaws/lib/jid"
+	"github.com/linkdata/jaws/lib/key"
+)
+
+// Upload is a file sent from the browser to an [UploadHandler].
+type Upload struct {
+	// Name is the file name reported by the browser. It is not sanitized; do not
+	// use it as a file system path without cleaning it first.
+	Name string
+	// Size is the length of the file in bytes, as declared by the browser.
+	// Reading more than Size bytes from the Reader fails.
+	Size int64
+	// Type is the MIME type reported by the browser, and may be empty.
+	Type string
+	// Reader streams the file contents.
+	io.Reader
+}
+
+// UploadHandler receives files uploaded from an [Element].
+//
+// The bundled client uploads each file selected in a managed file input with a
+// data-jawsupload attribute as a separate HTTP POST to
+// "/jaws/<key>/upload/<jid>", which [Jaws.ServeHTTP] accepts only for a Request
+// with a connected WebSocket, from the Request's remote IP and with an Origin
+// header matching the initial request.
+//
+// JawsUpload is called on the HTTP request's goroutine, concurrently with the
+// Request's event processing, and the upload completes when it returns. It
+// should return an error matching [ErrUploadTooLarge] to reject a file because
+// of its size.
+type UploadHandler interface {
+	JawsUpload(elem *Element, upload Upload) (err error)
+}
+
+// runningRequest returns the Request for jawsKey if its WebSocket is being
+// served, it is not cancelled and r comes from its remote IP.
+func (jw *Jaws) runningRequest(jawsKey key.Key, r *http.Request) (rq *Request) {
+	remoteIP := jw.clientIP(r)
+	jw.mu.RLock()
+	defer jw.mu.RUnlock()
+	if found := jw.requests[jawsKey]; found != nil && found.loadState() == reqRunning {
+		found.mu.RLock()
+		if equalIP(found.remoteIP, remoteIP) && found.ctx.Err() == nil {
+			rq = found
+		}
+		found.mu.RUnlock()
+	}
+	return
+}
+
+// uploadTarget returns the live Element with the given Jid string and the
+// UploadHandler to call for it.
+func (rq *Request) uploadTarget(jidStr string) (elem *Element, h UploadHandler) {
+	rq.mu.RLock()
+	if e := rq.getElementByJidLocked(jid.ParseString(jidStr)); e != nil && !e.deleted.Load() && e.frozen.Load() {
+		elem = e
+	}
+	rq.mu.RUnlock()
+	if elem != nil {
+		for i := len(elem.handlers) - 1; i >= 0 && h == nil; i-- {
+			h, _ = elem.handlers[i].(UploadHandler)
+		}
+		if h == nil {
+			h, _ = elem.UI().(UploadHandler)
+		}
+	}
+	return
+}
+
+// serveUpload handles a POST of one file to "/jaws/<key>/upload/<jid>?name=<name>".
+func (jw *Jaws) serveUpload(w http.ResponseWriter, r *http.Request, jawsKey key.Key, jidStr string) {
+	var elem *Element
+	var h UploadHandler
+	rq := jw.runningRequest(jawsKey, r)
+	if rq != nil {
+		elem, h = rq.uploadTarget(jidStr)
+	}
+	if h == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err := rq.validateWebSocketOrigin(r); err != nil {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		_ = jw.Log(err)
+		return
+	}
+	if r.ContentLength < 0 {
+		w.WriteHeader(http.StatusLengthRequired)
+		return
+	}
+	upload := Upload{
+		Name:   r.URL.Query().Get("name"),
+		Size:   r.ContentLength,
+		Type:   r.Header.Get("Content-Type"),
+		Reader: http.MaxBytesReader(w, r.Body, r.ContentLength),
+	}
+	w.Header().Set("Cache-Control", headerCacheControlNoStore)
+	err := h.JawsUpload(elem, upload)
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, ErrUploadTooLarge):
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, ErrEventUnhandled):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+		_ = rq.Jaws.Log(err)
+	}
+}
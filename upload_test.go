@@ -0,0 +1,100 @@
+package jaws
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testUploadWidget struct {
+	testDivWidget
+	got []Upload
+	err error
+}
+
+func (tw *testUploadWidget) JawsUpload(elem *Element, upload Upload) (err error) {
+	b, err := io.ReadAll(upload)
+	if err == nil {
+		upload.Reader = strings.NewReader(string(b))
+		tw.got = append(tw.got, upload)
+		err = tw.err
+	}
+	return
+}
+
+func TestUpload_ServeHTTP(t *testing.T) {
+	_, server, rq, dial := newResumeTestRequest(t, 0)
+	tw := &testUploadWidget{testDivWidget: testDivWidget{inner: "x"}}
+	elem := rq.NewElement(tw)
+	elem.Freeze()
+	other := rq.NewElement(testDivWidget{inner: "y"})
+	other.Freeze()
+
+	post := func(path, origin, body string) int {
+		t.Helper()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "text/plain")
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	uploadPath := "/jaws/" + rq.JawsKeyString() + "/upload/" + elem.Jid().String()
+
+	if code := post(uploadPath+"?name=a.txt", server.URL, "early"); code != http.StatusNotFound {
+		t.Errorf("upload before connect: %d", code)
+	}
+	conn, err := dial("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+	deadline := time.Now().Add(3 * time.Second)
+	for rq.loadState() != reqRunning && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if code := post(uploadPath+"?name=a%20b.txt", server.URL, "hello"); code != http.StatusNoContent {
+		t.Fatalf("upload: %d", code)
+	}
+	if len(tw.got) != 1 {
+		t.Fatalf("got %d uploads", len(tw.got))
+	}
+	up := tw.got[0]
+	if b, _ := io.ReadAll(up); up.Name != "a b.txt" || up.Size != 5 || up.Type != "text/plain" || string(b) != "hello" {
+		t.Errorf("upload %q %d %q %q", up.Name, up.Size, up.Type, b)
+	}
+
+	if code := post(uploadPath, "https://evil.test", "x"); code != http.StatusForbidden {
+		t.Errorf("bad origin: %d", code)
+	}
+	if code := post("/jaws/"+rq.JawsKeyString()+"/upload/"+other.Jid().String(), server.URL, "x"); code != http.StatusNotFound {
+		t.Errorf("no handler: %d", code)
+	}
+	if code := post("/jaws/"+rq.JawsKeyString()+"/upload/Jid.999999", server.URL, "x"); code != http.StatusNotFound {
+		t.Errorf("unknown jid: %d", code)
+	}
+	if code := post("/jaws/1/upload/"+elem.Jid().String(), server.URL, "x"); code != http.StatusNotFound {
+		t.Errorf("unknown key: %d", code)
+	}
+
+	tw.err = ErrUploadTooLarge
+	if code := post(uploadPath, server.URL, "big"); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("too large: %d", code)
+	}
+	tw.err = errors.New("boom")
+	if code := post(uploadPath, server.URL, "bad"); code != http.StatusUnprocessableEntity {
+		t.Errorf("handler error: %d", code)
+	}
+}